@@ -4,8 +4,11 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"runtime"
+	"time"
 
 	"github.com/YakDriver/copyplop/internal/copyright"
 	"github.com/spf13/cobra"
@@ -13,31 +16,112 @@ import (
 )
 
 var checkCmd = &cobra.Command{
-	Use:   "check",
+	Use:   "check [files...]",
 	Short: "Check for missing or incorrect copyright headers",
-	Long:  `Scan files and report any missing or incorrect copyright headers.`,
+	Long: `Scan files and report any missing or incorrect copyright headers.
+
+With files given, only those files are checked, e.g. the staged files a pre-commit hook
+passes. Files the config does not select, such as other extensions, are skipped.`,
+	Args: cobra.ArbitraryArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
+		start := time.Now()
 		path := viper.GetString("path")
+		stats, _ := cmd.Flags().GetBool("stats")
+		modified, _ := cmd.Flags().GetBool("modified")
+		failFast, _ := cmd.Flags().GetBool("fail-fast")
+		jobs, _ := cmd.Flags().GetInt("jobs")
+		onlyMissing, _ := cmd.Flags().GetBool("only-missing")
+		summary, _ := cmd.Flags().GetBool("summary")
+		suggest, _ := cmd.Flags().GetBool("fix-suggestion")
+		timeout, _ := cmd.Flags().GetDuration("timeout")
+
+		ctx := cmd.Context()
+		if timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+		format, _ := cmd.Flags().GetString("format")
+		reportFile, _ := cmd.Flags().GetString("report-file")
+		groupBy, _ := cmd.Flags().GetString("group-by")
+		rev, _ := cmd.Flags().GetString("rev")
+
+		if format != "text" && format != "json" && format != "sarif" {
+			return fmt.Errorf("unknown format %q (expected text, json or sarif)", format)
+		}
+		if groupBy != "" && groupBy != "kind" {
+			return fmt.Errorf("unknown group-by %q (expected kind)", groupBy)
+		}
+		if groupBy != "" && format != "text" {
+			return fmt.Errorf("--group-by is only supported with --format text")
+		}
+		if summary && format != "text" {
+			return fmt.Errorf("--summary is only supported with --format text")
+		}
+		if rev != "" && modified {
+			return fmt.Errorf("--rev cannot be combined with --modified")
+		}
+		if len(args) > 0 && (rev != "" || modified) {
+			return fmt.Errorf("--rev and --modified cannot be combined with files")
+		}
 
 		checker := copyright.NewChecker(cfg)
-		issues, err := checker.Check(path)
+		checker.Modified = modified
+		checker.FailFast = failFast
+		checker.Jobs = jobs
+		checker.Quiet = quiet
+		checker.OnlyMissing = onlyMissing
+		checker.Suggest = suggest
+		var issues []copyright.Issue
+		var err error
+		switch {
+		case rev != "":
+			issues, err = checker.CheckRevision(ctx, path, rev)
+		case len(args) > 0:
+			issues, err = checker.CheckFiles(ctx, args)
+		default:
+			issues, err = checker.Check(ctx, path)
+		}
 		if err != nil {
 			return fmt.Errorf("check failed: %w", err)
 		}
 
-		if len(issues) > 0 {
-			for _, issue := range issues {
-				fmt.Printf("%s: %s\n", issue.File, issue.Problem)
+		if stats {
+			printStats(os.Stderr, start)
+		}
+
+		if reportFile != "" {
+			if err := writeReportFile(reportFile, format, groupBy, issues); err != nil {
+				return fmt.Errorf("writing report: %w", err)
 			}
-			fmt.Printf("\nFound %d files with copyright issues\n", len(issues))
-			os.Exit(1)
 		}
 
-		fmt.Println("✓ All files have correct copyright headers")
+		if err := writeReport(os.Stdout, format, groupBy, issues); err != nil {
+			return err
+		}
+		if summary {
+			writeCheckSummary(os.Stdout, checker.Summary())
+		}
+
+		if len(issues) > 0 {
+			os.Exit(1)
+		}
 		return nil
 	},
 }
 
 func init() {
+	checkCmd.Flags().Bool("modified", false, "only process files modified or untracked in the git working tree")
+	checkCmd.Flags().String("rev", "", "check files as committed at this git revision instead of the working tree")
+	checkCmd.Flags().Bool("only-missing", false, "report only files with no copyright header at all, ignoring outdated or malformed ones")
+	checkCmd.Flags().Bool("fail-fast", false, "stop at the first file with an issue")
+	checkCmd.Flags().IntP("jobs", "j", runtime.NumCPU(), "number of files checked concurrently")
+	checkCmd.Flags().Duration("timeout", 0, "stop processing after this long, e.g. 5m (0 = no timeout)")
+	checkCmd.Flags().Bool("stats", false, "print elapsed time and memory usage to stderr")
+	checkCmd.Flags().String("format", "text", "output format: text, json or sarif (for GitHub code scanning)")
+	checkCmd.Flags().Bool("fix-suggestion", false, "print the header each file should have under its issue, ready to copy into the file")
+	checkCmd.Flags().Bool("summary", false, "also print how many files were scanned and passed, with the issues per file extension")
+	checkCmd.Flags().String("group-by", "", "group text output under a heading per issue kind: kind")
+	checkCmd.Flags().String("report-file", "", "also write the results, in --format, to this file (overwritten if it exists)")
 	rootCmd.AddCommand(checkCmd)
 }
@@ -0,0 +1,68 @@
+// Copyright IBM Corp. 2014, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"runtime"
+
+	"github.com/YakDriver/copyplop/internal/copyright"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var dedupeCmd = &cobra.Command{
+	Use:   "dedupe",
+	Short: "Remove duplicate or conflicting copyright headers",
+	Long: `Scan files and remove duplicate canonical headers and old headers that conflict
+with a canonical one. Files with a single correct header are left untouched.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path := viper.GetString("path")
+		jobs, _ := cmd.Flags().GetInt("jobs")
+		timeout, _ := cmd.Flags().GetDuration("timeout")
+
+		ctx := cmd.Context()
+		if timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+
+		fixer := copyright.NewFixer(cfg)
+		fixer.Quiet = quiet
+		fixer.Jobs = jobs
+		results, errs, err := fixer.Dedupe(ctx, path)
+		if err != nil {
+			return fmt.Errorf("dedupe failed: %w", err)
+		}
+
+		if len(results) == 0 && len(errs) == 0 {
+			fmt.Println("✓ No duplicate headers found")
+			return nil
+		}
+
+		total := 0
+		for _, result := range results {
+			fmt.Printf("%s: removed %d duplicate header lines\n", result.File, result.Removed)
+			total += result.Removed
+		}
+		if len(results) > 0 {
+			fmt.Printf("\n✓ Removed %d duplicate header lines from %d files\n", total, len(results))
+		}
+
+		if len(errs) > 0 {
+			writeFileErrors(os.Stderr, errs)
+			return fmt.Errorf("%d files could not be deduplicated", len(errs))
+		}
+		return nil
+	},
+}
+
+func init() {
+	dedupeCmd.Flags().IntP("jobs", "j", runtime.NumCPU(), "number of files deduplicated concurrently")
+	dedupeCmd.Flags().Duration("timeout", 0, "stop processing after this long, e.g. 5m (0 = no timeout)")
+	rootCmd.AddCommand(dedupeCmd)
+}
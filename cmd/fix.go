@@ -4,41 +4,213 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"time"
 
+	"github.com/YakDriver/copyplop/internal/config"
 	"github.com/YakDriver/copyplop/internal/copyright"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
 
 var fixCmd = &cobra.Command{
-	Use:   "fix",
+	Use:   "fix [files...]",
 	Short: "Fix missing or incorrect copyright headers",
-	Long:  `Add or update copyright headers in source code files.`,
+	Long: `Add or update copyright headers in source code files.
+
+With files given, only those files are fixed and the exit status is 1 if any of them was
+changed, which is what hook runners such as pre-commit expect.`,
+	Args: cobra.ArbitraryArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
+		start := time.Now()
 		path := viper.GetString("path")
+		limit, _ := cmd.Flags().GetInt("limit")
+		stats, _ := cmd.Flags().GetBool("stats")
+		modified, _ := cmd.Flags().GetBool("modified")
+		staged, _ := cmd.Flags().GetBool("staged")
+		failFast, _ := cmd.Flags().GetBool("fail-fast")
+		jobs, _ := cmd.Flags().GetInt("jobs")
+		force, _ := cmd.Flags().GetBool("force")
+		changedOnly, _ := cmd.Flags().GetBool("changed-only")
+		noThirdParty, _ := cmd.Flags().GetBool("no-third-party")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		summaryOnly, _ := cmd.Flags().GetBool("summary-only")
+		verify, _ := cmd.Flags().GetBool("verify")
+		manifest, _ := cmd.Flags().GetString("manifest")
+		diff, _ := cmd.Flags().GetBool("diff")
+		timeout, _ := cmd.Flags().GetDuration("timeout")
+
+		ctx := cmd.Context()
+		if timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
 
-		fixer := copyright.NewFixer(cfg)
-		results, err := fixer.Fix(path)
+		if summaryOnly && !dryRun {
+			return fmt.Errorf("--summary-only requires --dry-run")
+		}
+		if manifest != "" && dryRun {
+			return fmt.Errorf("--manifest cannot be combined with --dry-run")
+		}
+		if staged && modified {
+			return fmt.Errorf("--staged cannot be combined with --modified")
+		}
+		if len(args) > 0 && (staged || modified) {
+			return fmt.Errorf("--staged and --modified cannot be combined with files")
+		}
+
+		fixCfg := cfg
+		if noThirdParty {
+			fixCfg = withoutThirdParty(cfg)
+		}
+
+		fixer := copyright.NewFixer(fixCfg)
+		fixer.Modified = modified
+		fixer.Staged = staged
+		fixer.FailFast = failFast
+		fixer.Jobs = jobs
+		fixer.Quiet = quiet
+		fixer.Force = force
+		fixer.Limit = limit
+		fixer.DryRun = dryRun
+		fixer.Verify = verify
+		if diff {
+			fixer.Diff = os.Stdout
+		}
+
+		var results *copyright.FixResult
+		var err error
+		if len(args) > 0 {
+			results, err = fixer.FixFiles(ctx, args)
+		} else {
+			results, err = fixer.Fix(ctx, path)
+		}
 		if err != nil {
 			return fmt.Errorf("fix failed: %w", err)
 		}
 
-		if results.Fixed == 0 && results.Added == 0 {
-			fmt.Println("✓ No files needed fixing")
-		} else {
-			if results.Fixed > 0 {
-				fmt.Printf("✓ Fixed %d files\n", results.Fixed)
-			}
-			if results.Added > 0 {
-				fmt.Printf("✓ Added headers to %d files\n", results.Added)
+		if manifest != "" {
+			if err := writeManifestFile(manifest, results.Applied); err != nil {
+				return fmt.Errorf("writing manifest: %w", err)
 			}
 		}
 
+		if dryRun {
+			writeDryRunResult(os.Stdout, results, summaryOnly)
+		} else {
+			writeFixResult(os.Stdout, os.Stderr, results, changedOnly)
+		}
+
+		if stats {
+			printStats(os.Stderr, start)
+		}
+
+		for _, file := range results.Unstaged {
+			fmt.Fprintf(os.Stderr, "Warning: %s has unstaged changes; stage it to commit the fixed header\n", file)
+		}
+
+		// Like issues found by check, these are reported rather than returned as usage errors
+		if len(results.Errors) > 0 {
+			writeFileErrors(os.Stderr, results.Errors)
+		}
+		if len(results.Unverified) > 0 {
+			writeUnverified(os.Stderr, results.Unverified)
+		}
+		if len(results.Errors) > 0 || len(results.Unverified) > 0 {
+			os.Exit(1)
+		}
+
+		// Hook mode: a changed file fails the hook so the user can review and re-stage it
+		if len(args) > 0 && len(results.Changed) > 0 && !dryRun {
+			os.Exit(1)
+		}
 		return nil
 	},
 }
 
+// withoutThirdParty returns a copy of c that leaves third-party notices untouched,
+// whatever third_party.action is configured
+func withoutThirdParty(c *config.Config) *config.Config {
+	leave := *c
+	leave.ThirdParty.Action = "leave"
+	return &leave
+}
+
+// writeFixResult prints a summary of the fix results to stdout or, with changedOnly, just the
+// paths of the changed files, one per line, with the summary moved to stderr
+func writeFixResult(stdout, stderr io.Writer, results *copyright.FixResult, changedOnly bool) {
+	summary := stdout
+	if changedOnly {
+		for _, file := range results.Changed {
+			fmt.Fprintln(stdout, file)
+		}
+		summary = stderr
+	}
+
+	if results.Fixed == 0 && results.Added == 0 {
+		fmt.Fprintln(summary, "✓ No files needed fixing")
+		return
+	}
+	if results.Fixed > 0 {
+		fmt.Fprintf(summary, "✓ Fixed %d files\n", results.Fixed)
+	}
+	if results.Added > 0 {
+		fmt.Fprintf(summary, "✓ Added headers to %d files\n", results.Added)
+	}
+}
+
+// writeDryRunResult prints the files a dry run would change, one per line, followed by the
+// counts of files whose header would be fixed and of files that would get one added; with
+// summaryOnly just the counts are printed
+func writeDryRunResult(w io.Writer, results *copyright.FixResult, summaryOnly bool) {
+	if !summaryOnly {
+		for _, file := range results.Changed {
+			fmt.Fprintf(w, "Would fix: %s\n", file)
+		}
+	}
+
+	if results.Fixed == 0 && results.Added == 0 {
+		fmt.Fprintln(w, "✓ No files need fixing")
+		return
+	}
+	fmt.Fprintf(w, "%d files would be fixed, %d would be added\n", results.Fixed, results.Added)
+}
+
+// writeUnverified lists the fixed files the checker still reports, which points at a
+// disagreement between fix and check rather than at the files themselves
+func writeUnverified(w io.Writer, issues []copyright.Issue) {
+	for _, issue := range issues {
+		fmt.Fprintf(w, "✗ %s: %s after fixing\n", issue.File, issue.Problem)
+	}
+}
+
+// writeFileErrors lists the files that could not be fixed, e.g. because they are read-only
+func writeFileErrors(w io.Writer, errs []copyright.FileError) {
+	for _, err := range errs {
+		fmt.Fprintf(w, "✗ %v\n", err)
+	}
+}
+
 func init() {
+	fixCmd.Flags().Int("limit", 0, "stop after modifying this many files (0 = no limit)")
+	fixCmd.Flags().Bool("modified", false, "only process files modified or untracked in the git working tree")
+	fixCmd.Flags().Bool("staged", false, "only process files staged in the git index, staging them again once fixed")
+	fixCmd.Flags().Bool("fail-fast", false, "stop at the first file that cannot be fixed")
+	fixCmd.Flags().Bool("changed-only", false, "print only the paths of changed files to stdout, one per line; the summary goes to stderr")
+	fixCmd.Flags().Bool("no-third-party", false, "leave third-party notices untouched, overriding third_party.action")
+	fixCmd.Flags().Bool("dry-run", false, "report the files that would be fixed without modifying them")
+	fixCmd.Flags().Bool("summary-only", false, "with --dry-run, print only the count, not each file")
+	fixCmd.Flags().Bool("verify", false, "check each fixed file afterwards and fail if the checker still reports a problem")
+	fixCmd.Flags().String("manifest", "", "write the header applied to each changed file, with a timestamp, to this JSON file")
+	fixCmd.Flags().Bool("diff", false, "print a unified diff of each file's header changes to stdout; with --dry-run, nothing is written")
+	fixCmd.Flags().Bool("force", false, "rebuild headers even when they are already correct, normalizing their layout")
+	fixCmd.Flags().IntP("jobs", "j", runtime.NumCPU(), "number of files fixed concurrently")
+	fixCmd.Flags().Duration("timeout", 0, "stop processing after this long, e.g. 5m (0 = no timeout)")
+	fixCmd.Flags().Bool("stats", false, "print elapsed time and memory usage to stderr")
 	rootCmd.AddCommand(fixCmd)
 }
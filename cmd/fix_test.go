@@ -0,0 +1,348 @@
+// Copyright IBM Corp. 2014, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/YakDriver/copyplop/internal/config"
+	"github.com/YakDriver/copyplop/internal/copyright"
+)
+
+// TestFix_Hook simulates a pre-commit invocation: the changed files are passed as
+// arguments and only the files that were modified are listed
+func TestFix_Hook(t *testing.T) {
+	dir := t.TempDir()
+	dirty := filepath.Join(dir, "dirty.go")
+	clean := filepath.Join(dir, "clean.go")
+	ignored := filepath.Join(dir, "notes.txt")
+	files := map[string]string{
+		dirty:   "package main\n",
+		clean:   "// Copyright IBM Corp. 2014, 2025\n// SPDX-License-Identifier: MPL-2.0\n\npackage main\n",
+		ignored: "notes\n",
+	}
+	for file, content := range files {
+		if err := os.WriteFile(file, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cfg := &config.Config{
+		Copyright: config.Copyright{
+			Holder:      "IBM Corp.",
+			StartYear:   2014,
+			CurrentYear: 2025,
+			Format:      "Copyright {{.Holder}} {{.StartYear}}, {{.CurrentYear}}",
+		},
+		License: config.License{
+			Enabled:    true,
+			Identifier: "MPL-2.0",
+			Format:     "SPDX-License-Identifier: {{.Identifier}}",
+		},
+		Files: config.Files{
+			Extensions:    []string{".go"},
+			CommentStyles: map[string]string{"go": "//"},
+		},
+		Detection: config.Detection{
+			MaxScanLines: 20,
+		},
+	}
+	fixer := copyright.NewFixer(cfg)
+	args := []string{dirty, clean, ignored}
+
+	// First run fixes the dirty file and fails the hook
+	results, err := fixer.FixFiles(context.Background(), args)
+	if err != nil {
+		t.Fatalf("FixFiles() error = %v", err)
+	}
+	var buf bytes.Buffer
+	writeFixResult(&buf, io.Discard, results, true)
+	if buf.String() != dirty+"\n" {
+		t.Errorf("changed files = %q, want only %q", buf.String(), dirty)
+	}
+
+	content, err := os.ReadFile(dirty)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != files[clean] {
+		t.Errorf("dirty file not fixed:\n%s", content)
+	}
+	content, err = os.ReadFile(ignored)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != files[ignored] {
+		t.Errorf("file with unconfigured extension was modified:\n%s", content)
+	}
+
+	// Once re-staged, the hook passes
+	results, err = fixer.FixFiles(context.Background(), args)
+	if err != nil {
+		t.Fatalf("FixFiles() error = %v", err)
+	}
+	if len(results.Changed) != 0 {
+		t.Errorf("second run changed %v, want nothing", results.Changed)
+	}
+	buf.Reset()
+	writeFixResult(&buf, io.Discard, results, true)
+	if buf.Len() != 0 {
+		t.Errorf("second run listed %q, want no output", buf.String())
+	}
+}
+
+func TestFix_ChangedOnly(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"a.go":      "package a\n",
+		"b.go":      "// Copyright IBM Corp. 2014, 2025\n// SPDX-License-Identifier: MPL-2.0\n\npackage b\n",
+		"c.go":      "// Copyright IBM Corp. 2014, 2020\n// SPDX-License-Identifier: MPL-2.0\n\npackage c\n",
+		"sub/d.go":  "package d\n",
+		"notes.txt": "notes\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cfg := &config.Config{
+		Copyright: config.Copyright{
+			Holder:      "IBM Corp.",
+			StartYear:   2014,
+			CurrentYear: 2025,
+			Format:      "Copyright {{.Holder}} {{.StartYear}}, {{.CurrentYear}}",
+		},
+		License: config.License{
+			Enabled:    true,
+			Identifier: "MPL-2.0",
+			Format:     "SPDX-License-Identifier: {{.Identifier}}",
+		},
+		Files: config.Files{
+			Extensions:    []string{".go"},
+			CommentStyles: map[string]string{"go": "//"},
+		},
+		Detection: config.Detection{
+			MaxScanLines: 20,
+		},
+	}
+
+	results, err := copyright.NewFixer(cfg).Fix(context.Background(), dir)
+	if err != nil {
+		t.Fatalf("Fix() error = %v", err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	writeFixResult(&stdout, &stderr, results, true)
+
+	// The printed list is exactly the files whose content changed
+	var expected strings.Builder
+	for _, name := range []string{"a.go", "c.go", "sub/d.go"} {
+		expected.WriteString(filepath.Join(dir, name) + "\n")
+	}
+	for name, original := range files {
+		content, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		listed := strings.Contains(stdout.String(), filepath.Join(dir, name)+"\n")
+		if changed := string(content) != original; changed != listed {
+			t.Errorf("%s: changed = %v but listed = %v", name, changed, listed)
+		}
+	}
+	if stdout.String() != expected.String() {
+		t.Errorf("stdout = %q, want %q", stdout.String(), expected.String())
+	}
+
+	if stderr.String() != "✓ Fixed 1 files\n✓ Added headers to 2 files\n" {
+		t.Errorf("stderr = %q, want the summary", stderr.String())
+	}
+}
+
+func TestFix_NoThirdParty(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "main.go")
+	input := "// Copyright (c) 2019 Oracle and/or its affiliates.\n\npackage main\n"
+	if err := os.WriteFile(file, []byte(input), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &config.Config{
+		Copyright: config.Copyright{
+			Holder:      "IBM Corp.",
+			StartYear:   2014,
+			CurrentYear: 2025,
+			Format:      "Copyright {{.Holder}} {{.StartYear}}, {{.CurrentYear}}",
+		},
+		Files: config.Files{
+			Extensions:    []string{".go"},
+			CommentStyles: map[string]string{"go": "//"},
+		},
+		Detection: config.Detection{
+			MaxScanLines: 20,
+		},
+		ThirdParty: config.ThirdParty{
+			Action:   "replace",
+			Patterns: []string{"Copyright.*Oracle"},
+		},
+	}
+
+	if _, err := copyright.NewFixer(withoutThirdParty(cfg)).FixFiles(context.Background(), []string{file}); err != nil {
+		t.Fatalf("FixFiles() error = %v", err)
+	}
+
+	content, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "// Copyright IBM Corp. 2014, 2025\n\n" + input
+	if string(content) != expected {
+		t.Errorf("Expected:\n%q\n\nGot:\n%q", expected, string(content))
+	}
+	if cfg.ThirdParty.Action != "replace" {
+		t.Errorf("loaded config was modified: third_party.action = %q", cfg.ThirdParty.Action)
+	}
+}
+
+func TestFix_DryRunSummaryOnly(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		filepath.Join(dir, "a.go"):    "package main\n",
+		filepath.Join(dir, "b.go"):    "// Copyright IBM Corp. 2014, 2020\n\npackage main\n",
+		filepath.Join(dir, "good.go"): "// Copyright IBM Corp. 2014, 2025\n\npackage main\n",
+	}
+	for file, content := range files {
+		if err := os.WriteFile(file, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cfg := &config.Config{
+		Copyright: config.Copyright{
+			Holder:      "IBM Corp.",
+			StartYear:   2014,
+			CurrentYear: 2025,
+			Format:      "Copyright {{.Holder}} {{.StartYear}}, {{.CurrentYear}}",
+		},
+		Files: config.Files{
+			Extensions:    []string{".go"},
+			CommentStyles: map[string]string{"go": "//"},
+		},
+		Detection: config.Detection{
+			MaxScanLines: 20,
+		},
+	}
+	fixer := copyright.NewFixer(cfg)
+	fixer.DryRun = true
+
+	results, err := fixer.Fix(context.Background(), dir)
+	if err != nil {
+		t.Fatalf("Fix() error = %v", err)
+	}
+
+	var summary bytes.Buffer
+	writeDryRunResult(&summary, results, true)
+	if summary.String() != "1 files would be fixed, 1 would be added\n" {
+		t.Errorf("summary-only output = %q, want just the counts", summary.String())
+	}
+
+	var full bytes.Buffer
+	writeDryRunResult(&full, results, false)
+	if !strings.Contains(full.String(), "Would fix: "+filepath.Join(dir, "a.go")+"\n") {
+		t.Errorf("dry-run output = %q, want a line per file", full.String())
+	}
+
+	for file, content := range files {
+		got, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != content {
+			t.Errorf("dry run modified %s:\n%s", file, got)
+		}
+	}
+}
+
+func TestFix_Manifest(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		filepath.Join(dir, "a.go"):    "package main\n",
+		filepath.Join(dir, "b.go"):    "// Copyright IBM Corp. 2014, 2020\n// SPDX-License-Identifier: MPL-2.0\n\npackage main\n",
+		filepath.Join(dir, "good.go"): "// Copyright IBM Corp. 2014, 2025\n// SPDX-License-Identifier: MPL-2.0\n\npackage main\n",
+	}
+	for file, content := range files {
+		if err := os.WriteFile(file, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cfg := &config.Config{
+		Copyright: config.Copyright{
+			Holder:      "IBM Corp.",
+			StartYear:   2014,
+			CurrentYear: 2025,
+			Format:      "Copyright {{.Holder}} {{.StartYear}}, {{.CurrentYear}}",
+		},
+		License: config.License{
+			Enabled:    true,
+			Identifier: "MPL-2.0",
+			Format:     "SPDX-License-Identifier: {{.Identifier}}",
+		},
+		Files: config.Files{
+			Extensions:    []string{".go"},
+			CommentStyles: map[string]string{"go": "//"},
+		},
+		Detection: config.Detection{
+			MaxScanLines: 20,
+		},
+	}
+
+	before := time.Now()
+	results, err := copyright.NewFixer(cfg).Fix(context.Background(), dir)
+	if err != nil {
+		t.Fatalf("Fix() error = %v", err)
+	}
+
+	path := filepath.Join(dir, "audit", "manifest.json")
+	if err := writeManifestFile(path, results.Applied); err != nil {
+		t.Fatalf("writeManifestFile() error = %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var manifest fixManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		t.Fatalf("manifest is not valid JSON: %v\n%s", err, data)
+	}
+
+	header := "// Copyright IBM Corp. 2014, 2025\n// SPDX-License-Identifier: MPL-2.0"
+	want := []string{filepath.Join(dir, "a.go"), filepath.Join(dir, "b.go")}
+	if manifest.Count != len(want) || len(manifest.Files) != len(want) {
+		t.Fatalf("manifest = %+v, want entries for %v", manifest, want)
+	}
+	for i, entry := range manifest.Files {
+		if entry.File != want[i] {
+			t.Errorf("entry %d file = %q, want %q", i, entry.File, want[i])
+		}
+		if entry.Header != header {
+			t.Errorf("entry %d header = %q, want %q", i, entry.Header, header)
+		}
+		if entry.Time.Before(before) {
+			t.Errorf("entry %d time = %v, want a time after %v", i, entry.Time, before)
+		}
+	}
+}
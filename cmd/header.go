@@ -0,0 +1,50 @@
+// Copyright IBM Corp. 2014, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/YakDriver/copyplop/internal/copyright"
+	"github.com/spf13/cobra"
+)
+
+var headerCmd = &cobra.Command{
+	Use:   "header [file]",
+	Short: "Print the canonical header for an extension or file",
+	Long: `Print the exact header block fix would insert, using the current config.
+Pass --ext for an extension, or a file name to resolve compound and smart extensions.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ext, _ := cmd.Flags().GetString("ext")
+
+		if len(args) == 1 {
+			var err error
+			ext, err = copyright.ExtensionForFile(cfg, args[0])
+			if err != nil {
+				return fmt.Errorf("%s: %w", args[0], err)
+			}
+		}
+
+		if ext == "" {
+			return errors.New("specify --ext or a file")
+		}
+
+		lines, err := copyright.CanonicalHeader(cfg, ext)
+		if err != nil {
+			return fmt.Errorf("rendering header: %w", err)
+		}
+
+		for _, line := range lines {
+			fmt.Fprintln(cmd.OutOrStdout(), line)
+		}
+		return nil
+	},
+}
+
+func init() {
+	headerCmd.Flags().String("ext", "", "file extension, e.g. .go")
+	rootCmd.AddCommand(headerCmd)
+}
@@ -0,0 +1,201 @@
+// Copyright IBM Corp. 2014, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package cmd
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// initConfigFile is the config file init writes, the one copyplop reads by default
+const initConfigFile = ".copyplop.yaml"
+
+// knownCommentStyles are the comment styles init writes for the extensions it recognizes
+var knownCommentStyles = map[string]string{
+	".c":     "/* */",
+	".cpp":   "//",
+	".cs":    "//",
+	".css":   "/* */",
+	".go":    "//",
+	".h":     "/* */",
+	".hcl":   "#",
+	".java":  "//",
+	".js":    "//",
+	".kt":    "//",
+	".md":    "<!--",
+	".php":   "//",
+	".py":    "#",
+	".rb":    "#",
+	".rs":    "//",
+	".scala": "//",
+	".sh":    "#",
+	".swift": "//",
+	".tf":    "#",
+	".ts":    "//",
+	".yaml":  "#",
+	".yml":   "#",
+}
+
+// defaultInitExtensions are the extensions init configures when none are detected
+var defaultInitExtensions = []string{".go", ".sh", ".py", ".yml", ".yaml"}
+
+var initCmd = &cobra.Command{
+	Use:   "init",
+	Short: "Write a commented .copyplop.yaml to start from",
+	Long: `Write a .copyplop.yaml with sensible defaults and comments to the current directory.
+With --detect, the extensions and comment styles are those of the source files found under
+--path, most common first. An existing file is only replaced with --force.`,
+	Annotations: map[string]string{skipConfigAnnotation: "true"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		force, _ := cmd.Flags().GetBool("force")
+		detect, _ := cmd.Flags().GetBool("detect")
+
+		extensions := defaultInitExtensions
+		if detect {
+			detected, err := detectExtensions(viper.GetString("path"))
+			if err != nil {
+				return fmt.Errorf("detecting languages: %w", err)
+			}
+			if len(detected) > 0 {
+				extensions = detected
+			}
+		}
+
+		if err := writeInitConfig(initConfigFile, initConfigContent(extensions, time.Now().Year()), force); err != nil {
+			return err
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "✓ Wrote %s for %s\n", initConfigFile, strings.Join(extensions, ", "))
+		return nil
+	},
+}
+
+// writeInitConfig writes content to path, refusing to replace an existing file unless force
+func writeInitConfig(path, content string, force bool) error {
+	flags := os.O_WRONLY | os.O_CREATE | os.O_EXCL
+	if force {
+		flags = os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	}
+	f, err := os.OpenFile(path, flags, 0644)
+	if errors.Is(err, fs.ErrExist) {
+		return fmt.Errorf("%s already exists (use --force to overwrite it)", path)
+	}
+	if err != nil {
+		return err
+	}
+	if _, err := f.WriteString(content); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// detectExtensions returns the recognized source extensions of the files under root, most
+// common first. Hidden directories such as .git and vendored dependencies are not counted.
+func detectExtensions(root string) ([]string, error) {
+	counts := make(map[string]int)
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			name := d.Name()
+			if path != root && (strings.HasPrefix(name, ".") || name == "vendor" || name == "node_modules") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if ext := filepath.Ext(path); knownCommentStyles[ext] != "" {
+			counts[ext]++
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	extensions := make([]string, 0, len(counts))
+	for ext := range counts {
+		extensions = append(extensions, ext)
+	}
+	slices.SortFunc(extensions, func(a, b string) int {
+		return cmp.Or(cmp.Compare(counts[b], counts[a]), cmp.Compare(a, b))
+	})
+	return extensions, nil
+}
+
+// initConfigContent renders the config init writes for extensions, starting headers in year
+func initConfigContent(extensions []string, year int) string {
+	var exts, styles strings.Builder
+	for _, ext := range extensions {
+		fmt.Fprintf(&exts, "    - %q\n", ext)
+		key := strings.ReplaceAll(strings.TrimPrefix(ext, "."), ".", "_")
+		fmt.Fprintf(&styles, "    %s: %q\n", key, knownCommentStyles[ext])
+	}
+
+	return fmt.Sprintf(`# copyplop configuration; see https://github.com/YakDriver/copyplop for every option
+
+copyright:
+  holder: "Your Company"  # Who owns the copyright
+  start_year: %d
+  # current_year defaults to the current year; set it to pin the end of the range
+  # current_year: %d
+  format: "Copyright {{.Holder}} {{.StartYear}}, {{.CurrentYear}}"
+
+license:
+  enabled: true
+  identifier: "MPL-2.0"  # Your project's SPDX license identifier
+  format: "SPDX-License-Identifier: {{.Identifier}}"
+
+files:
+  # Only process files tracked by git (respects .gitignore), or every file outside a repository
+  git_tracked: true
+  git_fallback: true
+
+  # Paths to skip or limit processing to, as doublestar globs
+  # exclude_paths: ["vendor/**", "**/testdata/**"]
+  # include_paths: ["internal/**"]
+
+  extensions:
+%s
+
+  # Comment style per extension (without the dot): a line prefix such as "//" or "#",
+  # "<!--" for HTML comments or an opening and closing pair such as "/* */"
+  comment_styles:
+%s
+detection:
+  # Leave generated files alone
+  skip_generated: true
+  generated_patterns: ["Code generated", "DO NOT EDIT"]
+
+  # Also leave alone files whose first lines match, e.g. vendored files with a marker
+  skip_content_patterns: []
+
+  # Existing headers to replace with yours, as regular expressions
+  replace_patterns: []
+
+  # Only the first lines of a file are searched for a header (0 = the whole file)
+  max_scan_lines: 20
+
+third_party:
+  # What to do with other copyright holders' headers: "leave", "above", "below" or "replace"
+  action: "leave"
+  patterns: []
+`, year, year, strings.TrimSuffix(exts.String(), "\n"), styles.String())
+}
+
+func init() {
+	initCmd.Flags().Bool("force", false, "overwrite an existing .copyplop.yaml")
+	initCmd.Flags().Bool("detect", false, "configure the extensions of the source files found under --path")
+	rootCmd.AddCommand(initCmd)
+}
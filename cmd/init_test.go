@@ -0,0 +1,78 @@
+// Copyright IBM Corp. 2014, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestDetectExtensions(t *testing.T) {
+	root := t.TempDir()
+	for _, name := range []string{
+		"main.go", "internal/a.go", "internal/b.go",
+		"tools/gen.py", "script.sh", "tools/run.sh",
+		"README.txt",                       // not a recognized source extension
+		".git/hooks/x.rs", "vendor/dep.rb", // hidden and vendored directories are skipped
+	} {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	extensions, err := detectExtensions(root)
+	if err != nil {
+		t.Fatalf("detectExtensions() error = %v", err)
+	}
+	// Most common first, ties in name order
+	want := []string{".go", ".sh", ".py"}
+	if !slices.Equal(extensions, want) {
+		t.Errorf("detectExtensions() = %v, want %v", extensions, want)
+	}
+}
+
+func TestInitConfigContent(t *testing.T) {
+	content := initConfigContent([]string{".go", ".c", ".md"}, 2026)
+	for _, want := range []string{
+		"  start_year: 2026\n",
+		"  extensions:\n    - \".go\"\n    - \".c\"\n    - \".md\"\n\n",
+		"  comment_styles:\n    go: \"//\"\n    c: \"/* */\"\n    md: \"<!--\"\n",
+		"third_party:\n",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("config missing %q:\n%s", want, content)
+		}
+	}
+}
+
+func TestWriteInitConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), initConfigFile)
+
+	if err := writeInitConfig(path, "first\n", false); err != nil {
+		t.Fatalf("writeInitConfig() error = %v", err)
+	}
+
+	// An existing config is kept unless forced
+	err := writeInitConfig(path, "second\n", false)
+	if err == nil || !strings.Contains(err.Error(), "--force") {
+		t.Errorf("writeInitConfig() error = %v, want a hint to use --force", err)
+	}
+	if content, _ := os.ReadFile(path); string(content) != "first\n" {
+		t.Errorf("existing config changed to %q", content)
+	}
+
+	if err := writeInitConfig(path, "second\n", true); err != nil {
+		t.Fatalf("writeInitConfig() with force error = %v", err)
+	}
+	if content, _ := os.ReadFile(path); string(content) != "second\n" {
+		t.Errorf("forced config = %q, want %q", content, "second\n")
+	}
+}
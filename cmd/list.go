@@ -0,0 +1,58 @@
+// Copyright IBM Corp. 2014, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/YakDriver/copyplop/internal/copyright"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var listCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the files that would be processed",
+	Long: `List the files that check and fix would process under the path, to verify the extension
+and include/exclude configuration before fixing anything. File contents are not read.
+With --with-reason the files left out are listed too, with the reason each is skipped.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path := viper.GetString("path")
+		withReason, _ := cmd.Flags().GetBool("with-reason")
+		modified, _ := cmd.Flags().GetBool("modified")
+
+		files, err := copyright.ListFiles(path, cfg, modified)
+		if err != nil {
+			return fmt.Errorf("list failed: %w", err)
+		}
+
+		return writeFileList(os.Stdout, files, withReason)
+	},
+}
+
+// writeFileList writes the files that are processed, one per line; with withReason the skipped
+// files are written too, each followed by the reason it is skipped
+func writeFileList(w io.Writer, files []copyright.ListedFile, withReason bool) error {
+	for _, file := range files {
+		var err error
+		switch {
+		case file.SkipReason == "":
+			_, err = fmt.Fprintln(w, file.File)
+		case withReason:
+			_, err = fmt.Fprintf(w, "%s (skipped: %s)\n", file.File, file.SkipReason)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func init() {
+	listCmd.Flags().Bool("with-reason", false, "also list skipped files, with the reason each is skipped")
+	listCmd.Flags().Bool("modified", false, "only list files modified or untracked in the git working tree")
+	rootCmd.AddCommand(listCmd)
+}
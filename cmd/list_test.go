@@ -0,0 +1,43 @@
+// Copyright IBM Corp. 2014, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/YakDriver/copyplop/internal/copyright"
+)
+
+func TestWriteFileList(t *testing.T) {
+	files := []copyright.ListedFile{
+		{File: "README.txt", SkipReason: "extension not in files.extensions or files.smart_extensions"},
+		{File: "main.go"},
+	}
+
+	tests := []struct {
+		name       string
+		withReason bool
+		expected   string
+	}{
+		{name: "processed only", expected: "main.go\n"},
+		{
+			name:       "with reason",
+			withReason: true,
+			expected:   "README.txt (skipped: extension not in files.extensions or files.smart_extensions)\nmain.go\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := writeFileList(&buf, files, tt.withReason); err != nil {
+				t.Fatal(err)
+			}
+			if buf.String() != tt.expected {
+				t.Errorf("writeFileList() =\n%q\nwant:\n%q", buf.String(), tt.expected)
+			}
+		})
+	}
+}
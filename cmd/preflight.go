@@ -0,0 +1,86 @@
+// Copyright IBM Corp. 2014, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/YakDriver/copyplop/internal/copyright"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var preflightCmd = &cobra.Command{
+	Use:   "preflight",
+	Short: "Preview what fix would do to a sample of files",
+	Long: `Sample a few files of each configured extension and report what fix would do to them,
+without modifying anything. Extensions that select no files and files that fix would keep
+changing on every run are flagged.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path := viper.GetString("path")
+		samples, _ := cmd.Flags().GetInt("samples")
+		modified, _ := cmd.Flags().GetBool("modified")
+
+		fixer := copyright.NewFixer(cfg)
+		fixer.Modified = modified
+		report, err := fixer.Preflight(path, samples)
+		if err != nil {
+			return fmt.Errorf("preflight failed: %w", err)
+		}
+
+		if !writePreflight(os.Stdout, report) {
+			os.Exit(1)
+		}
+		return nil
+	},
+}
+
+// writePreflight prints the preflight report and returns false if any sampled file
+// could not be fixed or would not be stable after fixing
+func writePreflight(w io.Writer, report *copyright.PreflightReport) bool {
+	ok := true
+	var warnings []string
+
+	for _, ext := range report.Extensions {
+		if ext.Files == 0 {
+			warnings = append(warnings, fmt.Sprintf("Warning: %s matches no files (check files.extensions and path filters)", ext.Extension))
+			continue
+		}
+
+		fmt.Fprintf(w, "%s: %d files\n", ext.Extension, ext.Files)
+		for _, sample := range ext.Samples {
+			switch sample.Action {
+			case copyright.PreflightError:
+				ok = false
+				fmt.Fprintf(w, "  ✗ %s: error: %s\n", sample.File, sample.Problem)
+				continue
+			case copyright.PreflightFix:
+				if sample.Problem != "" {
+					fmt.Fprintf(w, "  ~ %s: would fix (%s)\n", sample.File, sample.Problem)
+				} else {
+					fmt.Fprintf(w, "  ~ %s: would fix\n", sample.File)
+				}
+			default:
+				fmt.Fprintf(w, "  ✓ %s: ok\n", sample.File)
+			}
+			if sample.NonIdempotent {
+				ok = false
+				fmt.Fprintf(w, "  ✗ %s: not idempotent, a second fix changes it again\n", sample.File)
+			}
+		}
+	}
+
+	for _, warning := range warnings {
+		fmt.Fprintln(w, warning)
+	}
+	return ok
+}
+
+func init() {
+	preflightCmd.Flags().Int("samples", 3, "number of files to sample per extension")
+	preflightCmd.Flags().Bool("modified", false, "only sample files modified or untracked in the git working tree")
+	rootCmd.AddCommand(preflightCmd)
+}
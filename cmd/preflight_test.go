@@ -0,0 +1,61 @@
+// Copyright IBM Corp. 2014, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/YakDriver/copyplop/internal/copyright"
+)
+
+func TestWritePreflight(t *testing.T) {
+	tests := []struct {
+		name     string
+		report   copyright.PreflightReport
+		ok       bool
+		contains []string
+	}{
+		{
+			name: "extension without files",
+			report: copyright.PreflightReport{Extensions: []copyright.ExtensionPreflight{
+				{Extension: ".go", Files: 1, Samples: []copyright.PreflightSample{{File: "main.go", Action: copyright.PreflightOK}}},
+				{Extension: ".rts"},
+			}},
+			ok:       true,
+			contains: []string{"✓ main.go: ok", "Warning: .rts matches no files"},
+		},
+		{
+			name: "would fix",
+			report: copyright.PreflightReport{Extensions: []copyright.ExtensionPreflight{
+				{Extension: ".go", Files: 1, Samples: []copyright.PreflightSample{{File: "main.go", Action: copyright.PreflightFix, Problem: "missing license header"}}},
+			}},
+			ok:       true,
+			contains: []string{"~ main.go: would fix (missing license header)"},
+		},
+		{
+			name: "non-idempotent",
+			report: copyright.PreflightReport{Extensions: []copyright.ExtensionPreflight{
+				{Extension: ".go", Files: 1, Samples: []copyright.PreflightSample{{File: "main.go", Action: copyright.PreflightFix, NonIdempotent: true}}},
+			}},
+			ok:       false,
+			contains: []string{"✗ main.go: not idempotent"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if ok := writePreflight(&buf, &tt.report); ok != tt.ok {
+				t.Errorf("writePreflight() = %v, want %v", ok, tt.ok)
+			}
+			for _, want := range tt.contains {
+				if !strings.Contains(buf.String(), want) {
+					t.Errorf("output missing %q:\n%s", want, buf.String())
+				}
+			}
+		})
+	}
+}
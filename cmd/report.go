@@ -0,0 +1,231 @@
+// Copyright IBM Corp. 2014, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/YakDriver/copyplop/internal/copyright"
+)
+
+// checkReport is the JSON form of check results
+type checkReport struct {
+	Count   int               `json:"count"`
+	Summary checkSummary      `json:"summary"`
+	Issues  []copyright.Issue `json:"issues"`
+}
+
+// checkSummary totals check results, overall and per issue kind
+type checkSummary struct {
+	Total  int            `json:"total"`
+	ByKind map[string]int `json:"by_kind"`
+}
+
+// summarize totals issues per kind; issues without a kind are not broken down
+func summarize(issues []copyright.Issue) checkSummary {
+	summary := checkSummary{Total: len(issues), ByKind: map[string]int{}}
+	for _, issue := range issues {
+		if issue.Kind != "" {
+			summary.ByKind[issue.Kind]++
+		}
+	}
+	return summary
+}
+
+// fixManifest is the JSON form of the headers fix applied
+type fixManifest struct {
+	Count int                       `json:"count"`
+	Files []copyright.AppliedHeader `json:"files"`
+}
+
+// issueKindOrder is the order in which grouped text output lists issue kinds
+var issueKindOrder = []string{
+	copyright.KindMissing,
+	copyright.KindIncorrect,
+	copyright.KindLicenseMissing,
+	copyright.KindThirdParty,
+	copyright.KindError,
+}
+
+// writeReport writes check results in the given format ("text", "json" or "sarif"). With groupBy
+// "kind", text output lists issues under a heading per issue kind.
+func writeReport(w io.Writer, format, groupBy string, issues []copyright.Issue) error {
+	if groupBy != "" && groupBy != "kind" {
+		return fmt.Errorf("unknown group-by %q (expected kind)", groupBy)
+	}
+	if groupBy != "" && format != "text" {
+		return fmt.Errorf("--group-by is only supported with --format text")
+	}
+	if groupBy == "kind" && len(issues) > 0 {
+		return writeGroupedReport(w, issues)
+	}
+
+	switch format {
+	case "sarif":
+		return writeSARIF(w, issues)
+	case "json":
+		report := checkReport{Count: len(issues), Summary: summarize(issues), Issues: issues}
+		if report.Issues == nil {
+			report.Issues = []copyright.Issue{}
+		}
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(report)
+	case "text":
+		if len(issues) == 0 {
+			_, err := fmt.Fprintln(w, "✓ All files have correct copyright headers")
+			return err
+		}
+		for _, issue := range issues {
+			if _, err := fmt.Fprintf(w, "%s: %s\n", issue.File, issue.Problem); err != nil {
+				return err
+			}
+			if err := writeSuggestion(w, "    ", issue.Suggestion); err != nil {
+				return err
+			}
+		}
+		_, err := fmt.Fprintf(w, "\nFound %d files with copyright issues\n", len(issues))
+		return err
+	default:
+		return fmt.Errorf("unknown format %q (expected text, json or sarif)", format)
+	}
+}
+
+// writeSuggestion writes the suggested header of an issue, if any, each line indented by
+// indent so it stands apart from the issue it belongs to
+func writeSuggestion(w io.Writer, indent, suggestion string) error {
+	if suggestion == "" {
+		return nil
+	}
+	for line := range strings.SplitSeq(suggestion, "\n") {
+		if _, err := fmt.Fprintln(w, indent+line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeCheckSummary writes how many files a check scanned and passed, followed by a line
+// per extension with issues counting them per kind, e.g. ".go: 12 missing, 1 incorrect"
+func writeCheckSummary(w io.Writer, summary copyright.CheckSummary) {
+	fmt.Fprintf(w, "\nScanned %d files: %d passed, %d with issues\n", summary.Files, summary.Passed, summary.Files-summary.Passed)
+	for _, ext := range summary.Extensions {
+		if len(ext.Issues) == 0 {
+			continue
+		}
+
+		kinds := slices.Clone(issueKindOrder)
+		for _, kind := range slices.Sorted(maps.Keys(ext.Issues)) {
+			if !slices.Contains(kinds, kind) {
+				kinds = append(kinds, kind)
+			}
+		}
+		var counts []string
+		for _, kind := range kinds {
+			if n := ext.Issues[kind]; n > 0 {
+				if kind == "" {
+					kind = "other"
+				}
+				counts = append(counts, fmt.Sprintf("%d %s", n, kind))
+			}
+		}
+
+		name := ext.Extension
+		if name == "" {
+			name = "(no extension)"
+		}
+		fmt.Fprintf(w, "  %s: %s\n", name, strings.Join(counts, ", "))
+	}
+}
+
+// writeGroupedReport writes issues as text grouped by kind, with a count per group
+func writeGroupedReport(w io.Writer, issues []copyright.Issue) error {
+	groups := make(map[string][]copyright.Issue)
+	for _, issue := range issues {
+		groups[issue.Kind] = append(groups[issue.Kind], issue)
+	}
+
+	kinds := slices.Clone(issueKindOrder)
+	for kind := range groups {
+		if !slices.Contains(kinds, kind) {
+			kinds = append(kinds, kind)
+		}
+	}
+
+	for _, kind := range kinds {
+		group := groups[kind]
+		if len(group) == 0 {
+			continue
+		}
+		heading := kind
+		if heading == "" {
+			heading = "other"
+		}
+		if _, err := fmt.Fprintf(w, "%s (%d)\n", heading, len(group)); err != nil {
+			return err
+		}
+		for _, issue := range group {
+			if _, err := fmt.Fprintf(w, "  %s: %s\n", issue.File, issue.Problem); err != nil {
+				return err
+			}
+			if err := writeSuggestion(w, "      ", issue.Suggestion); err != nil {
+				return err
+			}
+		}
+		if _, err := fmt.Fprintln(w); err != nil {
+			return err
+		}
+	}
+
+	_, err := fmt.Fprintf(w, "Found %d files with copyright issues\n", len(issues))
+	return err
+}
+
+// writeReportFile writes check results to path, creating parent directories as needed
+// and replacing any existing file
+func writeReportFile(path, format, groupBy string, issues []copyright.Issue) error {
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	if err := writeReport(f, format, groupBy, issues); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// writeManifestFile writes the headers applied by fix to path as JSON, creating parent
+// directories as needed and replacing any existing file
+func writeManifestFile(path string, applied []copyright.AppliedHeader) error {
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+	}
+
+	manifest := fixManifest{Count: len(applied), Files: applied}
+	if manifest.Files == nil {
+		manifest.Files = []copyright.AppliedHeader{}
+	}
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
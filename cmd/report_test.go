@@ -0,0 +1,238 @@
+// Copyright IBM Corp. 2014, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/YakDriver/copyplop/internal/copyright"
+)
+
+func TestWriteReportFile(t *testing.T) {
+	issues := []copyright.Issue{
+		{File: "main.go", Kind: copyright.KindMissing, Problem: "missing or incorrect copyright header"},
+		{File: "doc.md", Kind: copyright.KindLicenseMissing, Problem: "missing license header"},
+	}
+
+	t.Run("json creates directories", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "reports", "nested", "copyplop.json")
+		if err := writeReportFile(path, "json", "", issues); err != nil {
+			t.Fatalf("writeReportFile() error = %v", err)
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var report checkReport
+		if err := json.Unmarshal(content, &report); err != nil {
+			t.Fatalf("report is not valid JSON: %v\n%s", err, content)
+		}
+		if report.Count != 2 || len(report.Issues) != 2 {
+			t.Fatalf("report = %+v, want 2 issues", report)
+		}
+		if report.Issues[0] != issues[0] || report.Issues[1] != issues[1] {
+			t.Errorf("report issues = %+v, want %+v", report.Issues, issues)
+		}
+		if report.Summary.Total != 2 || report.Summary.ByKind[copyright.KindMissing] != 1 ||
+			report.Summary.ByKind[copyright.KindLicenseMissing] != 1 {
+			t.Errorf("report summary = %+v, want one missing and one license-missing", report.Summary)
+		}
+	})
+
+	t.Run("json with no issues", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "copyplop.json")
+		if err := writeReportFile(path, "json", "", nil); err != nil {
+			t.Fatalf("writeReportFile() error = %v", err)
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(content), `"issues": []`) || !strings.Contains(string(content), `"total": 0`) {
+			t.Errorf("expected empty issues array and a zero total, got:\n%s", content)
+		}
+	})
+
+	t.Run("overwrites existing file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "copyplop.txt")
+		if err := os.WriteFile(path, []byte(strings.Repeat("stale\n", 100)), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := writeReportFile(path, "text", "", issues); err != nil {
+			t.Fatalf("writeReportFile() error = %v", err)
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected := "main.go: missing or incorrect copyright header\ndoc.md: missing license header\n\nFound 2 files with copyright issues\n"
+		if string(content) != expected {
+			t.Errorf("Expected:\n%q\n\nGot:\n%q", expected, string(content))
+		}
+	})
+
+	t.Run("unknown format", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "copyplop.out")
+		if err := writeReportFile(path, "xml", "", issues); err == nil {
+			t.Error("expected error for unknown format")
+		}
+	})
+}
+
+func TestWriteReport_GroupByKind(t *testing.T) {
+	issues := []copyright.Issue{
+		{File: "a.go", Kind: copyright.KindIncorrect, Problem: "copyright header uses wrong comment syntax"},
+		{File: "b.go", Kind: copyright.KindMissing, Problem: "missing copyright header"},
+		{File: "c.go", Kind: copyright.KindLicenseMissing, Problem: "missing license header"},
+		{File: "d.go", Kind: copyright.KindMissing, Problem: "missing or incorrect copyright header"},
+		{File: "e.go", Kind: copyright.KindThirdParty, Problem: "missing or incorrect copyright header"},
+	}
+
+	var buf bytes.Buffer
+	if err := writeReport(&buf, "text", "kind", issues); err != nil {
+		t.Fatalf("writeReport() error = %v", err)
+	}
+
+	expected := `missing (2)
+  b.go: missing copyright header
+  d.go: missing or incorrect copyright header
+
+incorrect (1)
+  a.go: copyright header uses wrong comment syntax
+
+license-missing (1)
+  c.go: missing license header
+
+third-party (1)
+  e.go: missing or incorrect copyright header
+
+Found 5 files with copyright issues
+`
+	if buf.String() != expected {
+		t.Errorf("writeReport() =\n%s\nwant:\n%s", buf.String(), expected)
+	}
+
+	if err := writeReport(&buf, "json", "kind", issues); err == nil {
+		t.Error("Expected an error for --group-by with json output")
+	}
+	if err := writeReport(&buf, "text", "file", issues); err == nil {
+		t.Error("Expected an error for an unknown group-by")
+	}
+}
+
+func TestWriteReport_Suggestion(t *testing.T) {
+	issues := []copyright.Issue{
+		{File: "a.go", Kind: copyright.KindMissing, Problem: "missing or incorrect copyright header", Suggestion: "// Copyright IBM Corp. 2014, 2025\n// SPDX-License-Identifier: MPL-2.0"},
+		{File: "b.go", Kind: copyright.KindError, Problem: "could not read file"},
+	}
+
+	var buf bytes.Buffer
+	if err := writeReport(&buf, "text", "", issues); err != nil {
+		t.Fatalf("writeReport() error = %v", err)
+	}
+
+	expected := `a.go: missing or incorrect copyright header
+    // Copyright IBM Corp. 2014, 2025
+    // SPDX-License-Identifier: MPL-2.0
+b.go: could not read file
+
+Found 2 files with copyright issues
+`
+	if buf.String() != expected {
+		t.Errorf("writeReport() =\n%s\nwant:\n%s", buf.String(), expected)
+	}
+}
+
+func TestWriteCheckSummary(t *testing.T) {
+	summary := copyright.CheckSummary{
+		Files:  20,
+		Passed: 16,
+		Extensions: []copyright.ExtensionSummary{
+			{Extension: "", Files: 1, Issues: map[string]int{copyright.KindError: 1}},
+			{Extension: ".go", Files: 12, Issues: map[string]int{copyright.KindIncorrect: 1, copyright.KindMissing: 2}},
+			{Extension: ".py", Files: 4, Issues: map[string]int{}},
+			{Extension: ".sh", Files: 3, Issues: map[string]int{copyright.KindLicenseMissing: 1}},
+		},
+	}
+
+	var buf bytes.Buffer
+	writeCheckSummary(&buf, summary)
+
+	expected := `
+Scanned 20 files: 16 passed, 4 with issues
+  (no extension): 1 error
+  .go: 2 missing, 1 incorrect
+  .sh: 1 license-missing
+`
+	if buf.String() != expected {
+		t.Errorf("writeCheckSummary() =\n%s\nwant:\n%s", buf.String(), expected)
+	}
+}
+
+func TestWriteReport_SARIF(t *testing.T) {
+	issues := []copyright.Issue{
+		{File: "./internal/main.go", Kind: copyright.KindMissing, Problem: "missing copyright header"},
+		{File: "doc.md", Kind: copyright.KindLicenseMissing, Problem: "missing license header"},
+		{File: "old.go", Problem: "missing or incorrect copyright header"},
+	}
+
+	var buf bytes.Buffer
+	if err := writeReport(&buf, "sarif", "", issues); err != nil {
+		t.Fatalf("writeReport() error = %v", err)
+	}
+
+	var report sarifReport
+	if err := json.Unmarshal(buf.Bytes(), &report); err != nil {
+		t.Fatalf("report is not valid JSON: %v\n%s", err, buf.String())
+	}
+	if report.Version != "2.1.0" || len(report.Runs) != 1 {
+		t.Fatalf("report = %+v, want one SARIF 2.1.0 run", report)
+	}
+	run := report.Runs[0]
+	if run.Tool.Driver.Name != "copyplop" || len(run.Tool.Driver.Rules) != len(sarifRules) {
+		t.Errorf("driver = %+v, want copyplop with its rules", run.Tool.Driver)
+	}
+
+	want := []struct {
+		ruleID, uri, message string
+	}{
+		{"copyplop/missing-header", "internal/main.go", "missing copyright header"},
+		{"copyplop/missing-license", "doc.md", "missing license header"},
+		{"copyplop/header-issue", "old.go", "missing or incorrect copyright header"},
+	}
+	if len(run.Results) != len(want) {
+		t.Fatalf("got %d results, want %d", len(run.Results), len(want))
+	}
+	for i, w := range want {
+		result := run.Results[i]
+		if result.RuleID != w.ruleID || run.Tool.Driver.Rules[result.RuleIndex].ID != w.ruleID {
+			t.Errorf("result %d rule = %s (index %d), want %s", i, result.RuleID, result.RuleIndex, w.ruleID)
+		}
+		if uri := result.Locations[0].PhysicalLocation.ArtifactLocation.URI; uri != w.uri {
+			t.Errorf("result %d uri = %s, want %s", i, uri, w.uri)
+		}
+		if result.Message.Text != w.message || result.Level != "error" {
+			t.Errorf("result %d = %+v, want error %q", i, result, w.message)
+		}
+	}
+
+	// No issues still gives a valid log with an empty results array
+	buf.Reset()
+	if err := writeReport(&buf, "sarif", "", nil); err != nil {
+		t.Fatalf("writeReport() error = %v", err)
+	}
+	if !strings.Contains(buf.String(), `"results": []`) {
+		t.Errorf("expected empty results array, got:\n%s", buf.String())
+	}
+}
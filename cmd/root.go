@@ -4,8 +4,15 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"os"
+	"os/signal"
+	"path/filepath"
+	"slices"
+	"syscall"
+	"time"
 
 	"github.com/YakDriver/copyplop/internal/config"
 	"github.com/YakDriver/copyplop/version"
@@ -14,8 +21,11 @@ import (
 )
 
 var (
-	cfgFile string
-	cfg     *config.Config
+	cfgFile         string
+	nowFlag         string
+	printConfigPath bool
+	quiet           bool
+	cfg             *config.Config
 )
 
 var rootCmd = &cobra.Command{
@@ -24,19 +34,32 @@ var rootCmd = &cobra.Command{
 	Version: version.Version(),
 	Long: `copyplop is a configurable tool for managing copyright headers in source code files.
 It can check for missing headers, fix incorrect ones, and handle any copyright format.`,
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		if cmd.Annotations[skipConfigAnnotation] == "" {
+			initConfig()
+		}
+	},
 }
 
+// skipConfigAnnotation marks commands that run without a config file, such as init
+const skipConfigAnnotation = "copyplop/skip-config"
+
 func Execute() {
-	err := rootCmd.Execute()
+	// Ctrl-C or SIGTERM cancels the context so runs stop cleanly between files
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	err := rootCmd.ExecuteContext(ctx)
+	stop()
 	if err != nil {
 		os.Exit(1)
 	}
 }
 
 func init() {
-	cobra.OnInitialize(initConfig)
-	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is .copyplop.yaml)")
+	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file or directory of layered configs (default is .copyplop.yaml)")
 	rootCmd.PersistentFlags().StringP("path", "p", ".", "path to process")
+	rootCmd.PersistentFlags().BoolVar(&printConfigPath, "print-config-path", false, "print the config file(s) loaded, in merge order, to stderr")
+	rootCmd.PersistentFlags().StringVar(&nowFlag, "now", "", "date headers are rendered with, as 2006-01-02 or RFC 3339 (default is the current time)")
+	rootCmd.PersistentFlags().BoolVarP(&quiet, "quiet", "q", false, "suppress progress bars, e.g. in CI logs, printing only the results")
 
 	// Customize version template to show "v0.10.0" instead of "version 0.10.0"
 	rootCmd.SetVersionTemplate("v{{.Version}}\n")
@@ -46,25 +69,127 @@ func init() {
 }
 
 func initConfig() {
-	if cfgFile != "" {
-		viper.SetConfigFile(cfgFile)
-	} else {
-		viper.SetConfigName(".copyplop")
-		viper.SetConfigType("yaml")
-		viper.AddConfigPath(".")
-	}
-
 	viper.SetEnvPrefix("COPYPLOP")
 	viper.AutomaticEnv()
 
-	if err := viper.ReadInConfig(); err != nil {
+	paths, err := readConfig(viper.GetViper(), cfgFile)
+	if err != nil {
 		fmt.Printf("Warning: Could not read config file: %v\n", err)
 		os.Exit(1)
 	}
+	if printConfigPath {
+		writeConfigPaths(os.Stderr, paths)
+	}
 
 	cfg = &config.Config{}
 	if err := viper.Unmarshal(cfg); err != nil {
 		fmt.Printf("Error parsing config: %v\n", err)
 		os.Exit(1)
 	}
+
+	// "path" comes from the --path flag rather than the config file
+	unknown := slices.DeleteFunc(config.UnknownKeys(viper.AllKeys()), func(key string) bool { return key == "path" })
+	for _, key := range unknown {
+		fmt.Printf("Error: unknown config field %q\n", key)
+	}
+	if len(unknown) > 0 {
+		os.Exit(1)
+	}
+	if err := cfg.Validate(); err != nil {
+		fmt.Printf("Error: invalid config:\n%v\n", err)
+		os.Exit(1)
+	}
+
+	now, err := parseNow(nowFlag)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+	cfg.Copyright.Now = now
+
+	ignored, err := config.ReadIgnoreFiles(viper.GetString("path"))
+	if err != nil {
+		fmt.Printf("Error: reading %s: %v\n", config.IgnoreFileName, err)
+		os.Exit(1)
+	}
+	cfg.Files.Ignored = ignored
+}
+
+// parseNow parses the --now flag, which pins the date headers are rendered with so builds
+// are reproducible. An empty value returns the zero time, meaning the current time.
+func parseNow(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.DateOnly, value); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid --now %q: want YYYY-MM-DD or RFC 3339", value)
+	}
+	return t, nil
+}
+
+// writeConfigPaths prints the config files that were loaded, one per line
+func writeConfigPaths(w io.Writer, paths []string) {
+	for _, path := range paths {
+		fmt.Fprintf(w, "Config file: %s\n", path)
+	}
+}
+
+// readConfig loads the config into v and returns the files it read, in merge order. When
+// cfgFile is a directory, its YAML files are layered in lexical order, so later files
+// (e.g. repo overrides) win over earlier ones (e.g. an org-wide base).
+func readConfig(v *viper.Viper, cfgFile string) ([]string, error) {
+	if cfgFile == "" {
+		v.SetConfigName(".copyplop")
+		v.SetConfigType("yaml")
+		v.AddConfigPath(".")
+		if err := v.ReadInConfig(); err != nil {
+			return nil, err
+		}
+		return []string{v.ConfigFileUsed()}, nil
+	}
+
+	info, err := os.Stat(cfgFile)
+	if err != nil || !info.IsDir() {
+		v.SetConfigFile(cfgFile)
+		if err := v.ReadInConfig(); err != nil {
+			return nil, err
+		}
+		return []string{v.ConfigFileUsed()}, nil
+	}
+
+	entries, err := os.ReadDir(cfgFile)
+	if err != nil {
+		return nil, err
+	}
+
+	// os.ReadDir returns entries sorted by filename
+	var files []string
+	for _, entry := range entries {
+		ext := filepath.Ext(entry.Name())
+		if !entry.IsDir() && (ext == ".yaml" || ext == ".yml") {
+			files = append(files, filepath.Join(cfgFile, entry.Name()))
+		}
+	}
+
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no YAML config files found in %s", cfgFile)
+	}
+
+	for i, file := range files {
+		v.SetConfigFile(file)
+		if i == 0 {
+			err = v.ReadInConfig()
+		} else {
+			err = v.MergeInConfig()
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+	}
+
+	return files, nil
 }
@@ -0,0 +1,120 @@
+// Copyright IBM Corp. 2014, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/YakDriver/copyplop/internal/config"
+	"github.com/spf13/viper"
+)
+
+func TestReadConfig_Directory(t *testing.T) {
+	dir := t.TempDir()
+
+	files := map[string]string{
+		"00-base.yaml": `copyright:
+  holder: "Base Corp"
+  current_year: 2025
+  format: "Copyright {{.Holder}} {{.CurrentYear}}"
+license:
+  enabled: true
+  identifier: "MPL-2.0"
+files:
+  extensions: [".go"]
+`,
+		"10-override.yml": `copyright:
+  holder: "Repo Corp"
+`,
+		"README.txt": "not a config file",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	v := viper.New()
+	paths, err := readConfig(v, dir)
+	if err != nil {
+		t.Fatalf("readConfig() error = %v", err)
+	}
+
+	expectedPaths := []string{filepath.Join(dir, "00-base.yaml"), filepath.Join(dir, "10-override.yml")}
+	if !slices.Equal(paths, expectedPaths) {
+		t.Errorf("readConfig() paths = %v, want %v", paths, expectedPaths)
+	}
+
+	cfg := &config.Config{}
+	if err := v.Unmarshal(cfg); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+
+	if cfg.Copyright.Holder != "Repo Corp" {
+		t.Errorf("Holder = %q, want override %q", cfg.Copyright.Holder, "Repo Corp")
+	}
+	if cfg.Copyright.CurrentYear != 2025 {
+		t.Errorf("CurrentYear = %d, want base value 2025", cfg.Copyright.CurrentYear)
+	}
+	if cfg.License.Identifier != "MPL-2.0" {
+		t.Errorf("License.Identifier = %q, want base value %q", cfg.License.Identifier, "MPL-2.0")
+	}
+}
+
+func TestReadConfig_EmptyDirectory(t *testing.T) {
+	if _, err := readConfig(viper.New(), t.TempDir()); err == nil {
+		t.Error("readConfig() expected error for directory without YAML files")
+	}
+}
+
+func TestReadConfig_PrintConfigPath(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "custom.yaml")
+	if err := os.WriteFile(file, []byte("copyright:\n  holder: \"Acme Corp\"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	paths, err := readConfig(viper.New(), file)
+	if err != nil {
+		t.Fatalf("readConfig() error = %v", err)
+	}
+	if !slices.Equal(paths, []string{file}) {
+		t.Errorf("readConfig() paths = %v, want [%s]", paths, file)
+	}
+
+	var out strings.Builder
+	writeConfigPaths(&out, paths)
+	if expected := "Config file: " + file + "\n"; out.String() != expected {
+		t.Errorf("writeConfigPaths() = %q, want %q", out.String(), expected)
+	}
+}
+
+func TestParseNow(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected time.Time
+		wantErr  bool
+	}{
+		{value: "", expected: time.Time{}},
+		{value: "2025-03-07", expected: time.Date(2025, time.March, 7, 0, 0, 0, 0, time.UTC)},
+		{value: "2025-03-07T10:30:00Z", expected: time.Date(2025, time.March, 7, 10, 30, 0, 0, time.UTC)},
+		{value: "March 7", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := parseNow(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseNow(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+			if !got.Equal(tt.expected) {
+				t.Errorf("parseNow(%q) = %v, want %v", tt.value, got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,135 @@
+// Copyright IBM Corp. 2014, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package cmd
+
+import (
+	"encoding/json"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/YakDriver/copyplop/internal/copyright"
+	"github.com/YakDriver/copyplop/version"
+)
+
+// sarifRules are the SARIF rules check results map to, one per issue kind, in report order
+var sarifRules = []sarifRule{
+	{ID: "copyplop/missing-header", kind: copyright.KindMissing, ShortDescription: sarifMessage{Text: "File has no copyright header"}},
+	{ID: "copyplop/incorrect-header", kind: copyright.KindIncorrect, ShortDescription: sarifMessage{Text: "Copyright header is outdated, misplaced or malformed"}},
+	{ID: "copyplop/missing-license", kind: copyright.KindLicenseMissing, ShortDescription: sarifMessage{Text: "Copyright header has no license line"}},
+	{ID: "copyplop/third-party-header", kind: copyright.KindThirdParty, ShortDescription: sarifMessage{Text: "File has only a third-party copyright header"}},
+	{ID: "copyplop/unreadable-file", kind: copyright.KindError, ShortDescription: sarifMessage{Text: "File could not be checked"}},
+	{ID: "copyplop/header-issue", ShortDescription: sarifMessage{Text: "Copyright header issue"}},
+}
+
+type sarifReport struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version,omitempty"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+	kind             string       // issue kind the rule reports; "" for issues without one
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	RuleIndex int             `json:"ruleIndex"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           sarifRegion           `json:"region"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine int `json:"startLine"`
+}
+
+// writeSARIF writes check results as a SARIF 2.1.0 log, e.g. for GitHub code scanning. Each
+// issue is an error-level result at the top of its file, where the header belongs.
+func writeSARIF(w io.Writer, issues []copyright.Issue) error {
+	results := []sarifResult{}
+	for _, issue := range issues {
+		index := sarifRuleIndex(issue.Kind)
+		results = append(results, sarifResult{
+			RuleID:    sarifRules[index].ID,
+			RuleIndex: index,
+			Level:     "error",
+			Message:   sarifMessage{Text: issue.Problem},
+			Locations: []sarifLocation{{
+				PhysicalLocation: sarifPhysicalLocation{
+					ArtifactLocation: sarifArtifactLocation{URI: sarifURI(issue.File)},
+					Region:           sarifRegion{StartLine: 1},
+				},
+			}},
+		})
+	}
+
+	report := sarifReport{
+		Version: "2.1.0",
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Runs: []sarifRun{{
+			Tool: sarifTool{Driver: sarifDriver{
+				Name:           "copyplop",
+				Version:        version.Version(),
+				InformationURI: "https://github.com/YakDriver/copyplop",
+				Rules:          sarifRules,
+			}},
+			Results: results,
+		}},
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(report)
+}
+
+// sarifRuleIndex returns the index of the rule reporting issues of kind
+func sarifRuleIndex(kind string) int {
+	for i, rule := range sarifRules {
+		if rule.kind == kind {
+			return i
+		}
+	}
+	return len(sarifRules) - 1
+}
+
+// sarifURI is file as a relative URI reference, with forward slashes and no "./" prefix, so
+// code scanning resolves it against the repository root
+func sarifURI(file string) string {
+	return strings.TrimPrefix(filepath.ToSlash(filepath.Clean(file)), "./")
+}
@@ -0,0 +1,56 @@
+// Copyright IBM Corp. 2014, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/YakDriver/copyplop/internal/copyright"
+	"github.com/spf13/cobra"
+)
+
+var selfTestCmd = &cobra.Command{
+	Use:    "self-test",
+	Short:  "Check that fix behaves well with the current config",
+	Hidden: true,
+	Long: `Fix a bundled corpus of header layouts for each configured extension, in a temporary
+directory, and report any sample for which fix is not idempotent, leaves a header that check
+still reports, or changes the content below the header. Such violations mean the config
+would make fix churn files.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		violations, err := copyright.NewFixer(cfg).SelfTest()
+		if err != nil {
+			return fmt.Errorf("self-test failed: %w", err)
+		}
+
+		if !writeSelfTest(os.Stdout, violations) {
+			os.Exit(1)
+		}
+		return nil
+	},
+}
+
+// writeSelfTest prints self-test violations and returns false if there were any
+func writeSelfTest(w io.Writer, violations []copyright.SelfTestViolation) bool {
+	if len(violations) == 0 {
+		fmt.Fprintln(w, "✓ Self-test passed")
+		return true
+	}
+
+	for _, v := range violations {
+		if v.Sample == "" {
+			fmt.Fprintf(w, "✗ %s: %s\n", v.Extension, v.Problem)
+		} else {
+			fmt.Fprintf(w, "✗ %s (%s): %s\n", v.Extension, v.Sample, v.Problem)
+		}
+	}
+	fmt.Fprintf(w, "Found %d self-test violations\n", len(violations))
+	return false
+}
+
+func init() {
+	rootCmd.AddCommand(selfTestCmd)
+}
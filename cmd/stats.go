@@ -0,0 +1,91 @@
+// Copyright IBM Corp. 2014, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"time"
+
+	"github.com/YakDriver/copyplop/internal/copyright"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var statsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show how many files have correct copyright headers",
+	Long: `Check files and report how many have correct copyright headers. With --by-extension the
+coverage is broken down per file extension, e.g. to see which file types need attention first.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path := viper.GetString("path")
+		byExtension, _ := cmd.Flags().GetBool("by-extension")
+		modified, _ := cmd.Flags().GetBool("modified")
+		format, _ := cmd.Flags().GetString("format")
+
+		if format != "text" && format != "json" {
+			return fmt.Errorf("unknown format %q (expected text or json)", format)
+		}
+
+		checker := copyright.NewChecker(cfg)
+		checker.Modified = modified
+		checker.Quiet = quiet
+		coverage, err := checker.Coverage(cmd.Context(), path)
+		if err != nil {
+			return fmt.Errorf("stats failed: %w", err)
+		}
+		if !byExtension {
+			coverage.Extensions = nil
+		}
+
+		return writeCoverage(os.Stdout, format, coverage)
+	},
+}
+
+// writeCoverage writes header coverage in the given format ("text" or "json"), with a line
+// per extension when coverage has a per-extension breakdown
+func writeCoverage(w io.Writer, format string, coverage *copyright.Coverage) error {
+	if format == "json" {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(coverage)
+	}
+
+	for _, ext := range coverage.Extensions {
+		fmt.Fprintf(w, "%s: %d of %d files compliant (%s)\n", ext.Extension, ext.Compliant, ext.Files, percent(ext.Compliant, ext.Files))
+	}
+	_, err := fmt.Fprintf(w, "Total: %d of %d files compliant (%s)\n", coverage.Compliant, coverage.Files, percent(coverage.Compliant, coverage.Files))
+	return err
+}
+
+// percent formats n of total as a percentage with one decimal
+func percent(n, total int) string {
+	if total == 0 {
+		return "100.0%"
+	}
+	return fmt.Sprintf("%.1f%%", float64(n)*100/float64(total))
+}
+
+// printStats writes elapsed time and memory usage since start as "key: value" lines
+func printStats(w io.Writer, start time.Time) {
+	var m runtime.MemStats
+	runtime.ReadMemStats(&m)
+
+	fmt.Fprintln(w, "--- stats ---")
+	fmt.Fprintf(w, "elapsed: %s\n", time.Since(start).Round(time.Millisecond))
+	// Memory the Go runtime has obtained from the OS, which is not the process's peak RSS
+	fmt.Fprintf(w, "sys_bytes: %d\n", m.Sys)
+	fmt.Fprintf(w, "total_alloc_bytes: %d\n", m.TotalAlloc)
+	fmt.Fprintf(w, "num_gc: %d\n", m.NumGC)
+}
+
+func init() {
+	statsCmd.Flags().Bool("by-extension", false, "break coverage down per file extension")
+	statsCmd.Flags().Bool("modified", false, "only process files modified or untracked in the git working tree")
+	statsCmd.Flags().String("format", "text", "output format: text or json")
+	rootCmd.AddCommand(statsCmd)
+}
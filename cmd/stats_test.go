@@ -0,0 +1,80 @@
+// Copyright IBM Corp. 2014, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/YakDriver/copyplop/internal/copyright"
+)
+
+func TestPrintStats(t *testing.T) {
+	var buf bytes.Buffer
+	printStats(&buf, time.Now().Add(-1500*time.Millisecond))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) == 0 || lines[0] != "--- stats ---" {
+		t.Fatalf("expected stats block header, got:\n%s", buf.String())
+	}
+
+	values := make(map[string]string)
+	for _, line := range lines[1:] {
+		key, value, ok := strings.Cut(line, ": ")
+		if !ok {
+			t.Fatalf("stats line %q is not in key: value form", line)
+		}
+		values[key] = value
+	}
+
+	elapsed, err := time.ParseDuration(values["elapsed"])
+	if err != nil {
+		t.Fatalf("elapsed %q not parseable: %v", values["elapsed"], err)
+	}
+	if elapsed < 1500*time.Millisecond {
+		t.Errorf("elapsed = %s, want at least 1.5s", elapsed)
+	}
+
+	for _, key := range []string{"sys_bytes", "total_alloc_bytes", "num_gc"} {
+		if _, err := strconv.ParseUint(values[key], 10, 64); err != nil {
+			t.Errorf("%s %q not parseable: %v", key, values[key], err)
+		}
+	}
+}
+
+func TestWriteCoverage(t *testing.T) {
+	coverage := &copyright.Coverage{
+		Files:     3,
+		Compliant: 2,
+		Extensions: []copyright.ExtensionCoverage{
+			{Extension: ".go", Files: 2, Compliant: 2},
+			{Extension: ".sh", Files: 1, Compliant: 0},
+		},
+	}
+
+	var text bytes.Buffer
+	if err := writeCoverage(&text, "text", coverage); err != nil {
+		t.Fatalf("writeCoverage() error = %v", err)
+	}
+	expected := ".go: 2 of 2 files compliant (100.0%)\n.sh: 0 of 1 files compliant (0.0%)\nTotal: 2 of 3 files compliant (66.7%)\n"
+	if text.String() != expected {
+		t.Errorf("writeCoverage() text =\n%s\nwant\n%s", text.String(), expected)
+	}
+
+	var out bytes.Buffer
+	if err := writeCoverage(&out, "json", coverage); err != nil {
+		t.Fatalf("writeCoverage() error = %v", err)
+	}
+	var decoded copyright.Coverage
+	if err := json.Unmarshal(out.Bytes(), &decoded); err != nil {
+		t.Fatalf("writeCoverage() JSON does not parse: %v\n%s", err, out.String())
+	}
+	if decoded.Files != 3 || decoded.Compliant != 2 || len(decoded.Extensions) != 2 || decoded.Extensions[1].Extension != ".sh" {
+		t.Errorf("writeCoverage() JSON = %s", out.String())
+	}
+}
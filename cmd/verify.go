@@ -0,0 +1,72 @@
+// Copyright IBM Corp. 2014, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"runtime"
+
+	"github.com/YakDriver/copyplop/internal/copyright"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var verifyCmd = &cobra.Command{
+	Use:   "verify [files...]",
+	Short: "Verify headers exactly match the canonical header",
+	Long: `Strictly compare each file's header block with the canonical rendered header and
+report any deviation with a diff. Unlike check, extra text on a header line is flagged.
+
+With files given, only those files are verified. Files the config does not select, such
+as other extensions, are skipped.`,
+	Args: cobra.ArbitraryArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path := viper.GetString("path")
+		jobs, _ := cmd.Flags().GetInt("jobs")
+		timeout, _ := cmd.Flags().GetDuration("timeout")
+
+		ctx := cmd.Context()
+		if timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+
+		checker := copyright.NewChecker(cfg)
+		checker.Jobs = jobs
+		checker.Quiet = quiet
+		var issues []copyright.Issue
+		var err error
+		if len(args) > 0 {
+			issues, err = checker.VerifyFiles(ctx, args)
+		} else {
+			issues, err = checker.Verify(ctx, path)
+		}
+		if err != nil {
+			return fmt.Errorf("verify failed: %w", err)
+		}
+
+		if len(issues) > 0 {
+			for _, issue := range issues {
+				fmt.Printf("%s: %s\n", issue.File, issue.Problem)
+				if issue.Diff != "" {
+					fmt.Print(issue.Diff)
+				}
+			}
+			fmt.Printf("\nFound %d files with non-canonical headers\n", len(issues))
+			os.Exit(1)
+		}
+
+		fmt.Println("✓ All files have canonical copyright headers")
+		return nil
+	},
+}
+
+func init() {
+	verifyCmd.Flags().IntP("jobs", "j", runtime.NumCPU(), "number of files verified concurrently")
+	verifyCmd.Flags().Duration("timeout", 0, "stop processing after this long, e.g. 5m (0 = no timeout)")
+	rootCmd.AddCommand(verifyCmd)
+}
@@ -4,7 +4,9 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
 
 	"github.com/YakDriver/copyplop/version"
 	"github.com/spf13/cobra"
@@ -13,11 +15,32 @@ import (
 var versionCmd = &cobra.Command{
 	Use:   "version",
 	Short: "Print the version number of copyplop",
-	Run: func(cmd *cobra.Command, args []string) {
-		fmt.Printf("v%s\n", version.Version())
+	RunE: func(cmd *cobra.Command, args []string) error {
+		short, _ := cmd.Flags().GetBool("short")
+		asJSON, _ := cmd.Flags().GetBool("json")
+
+		return printVersion(cmd.OutOrStdout(), version.Get(), short, asJSON)
 	},
 }
 
+// printVersion writes build information as plain text, just the version number (short) or JSON
+func printVersion(w io.Writer, info version.Info, short, asJSON bool) error {
+	switch {
+	case asJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(info)
+	case short:
+		_, err := fmt.Fprintln(w, info.Version)
+		return err
+	default:
+		_, err := fmt.Fprintf(w, "v%s\ncommit: %s\nbuilt: %s\n", info.Version, info.Commit, info.Date)
+		return err
+	}
+}
+
 func init() {
+	versionCmd.Flags().Bool("short", false, "print only the version number")
+	versionCmd.Flags().Bool("json", false, "print version information as JSON")
 	rootCmd.AddCommand(versionCmd)
 }
@@ -0,0 +1,69 @@
+// Copyright IBM Corp. 2014, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/YakDriver/copyplop/version"
+)
+
+func TestPrintVersion(t *testing.T) {
+	info := version.Info{Version: "1.2.3", Commit: "abc123", Date: "2026-01-02T15:04:05Z"}
+
+	tests := []struct {
+		name     string
+		short    bool
+		asJSON   bool
+		expected string
+	}{
+		{
+			name:     "default",
+			expected: "v1.2.3\ncommit: abc123\nbuilt: 2026-01-02T15:04:05Z\n",
+		},
+		{
+			name:     "short",
+			short:    true,
+			expected: "1.2.3\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := printVersion(&buf, info, tt.short, tt.asJSON); err != nil {
+				t.Fatalf("printVersion() error = %v", err)
+			}
+			if buf.String() != tt.expected {
+				t.Errorf("printVersion() = %q, want %q", buf.String(), tt.expected)
+			}
+		})
+	}
+}
+
+func TestPrintVersion_JSON(t *testing.T) {
+	info := version.Info{Version: "1.2.3", Commit: "abc123", Date: "2026-01-02T15:04:05Z"}
+
+	var buf bytes.Buffer
+	if err := printVersion(&buf, info, false, true); err != nil {
+		t.Fatalf("printVersion() error = %v", err)
+	}
+
+	var got map[string]string
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("output is not valid JSON: %v\n%s", err, buf.String())
+	}
+
+	expected := map[string]string{"version": "1.2.3", "commit": "abc123", "date": "2026-01-02T15:04:05Z"}
+	if len(got) != len(expected) {
+		t.Errorf("JSON has keys %v, want %v", got, expected)
+	}
+	for key, value := range expected {
+		if got[key] != value {
+			t.Errorf("JSON %q = %q, want %q", key, got[key], value)
+		}
+	}
+}
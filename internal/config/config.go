@@ -5,32 +5,124 @@ package config
 
 import (
 	"bytes"
+	"fmt"
+	"io/fs"
+	"maps"
+	"path"
+	"path/filepath"
 	"regexp"
+	"slices"
+	"strconv"
 	"strings"
+	"sync"
 	"text/template"
+	"time"
+	"unicode"
 
 	"github.com/bmatcuk/doublestar/v4"
 )
 
+// IgnoreFileName is the name of the files listing paths never to process, like .gitignore
+const IgnoreFileName = ".copyplopignore"
+
+// defaultShebangCommentStyles maps script interpreters to their comment style. It is
+// consulted for shebang files whose extension has no configured comment style.
+var defaultShebangCommentStyles = map[string]string{
+	"bash":   "#",
+	"sh":     "#",
+	"zsh":    "#",
+	"python": "#",
+	"ruby":   "#",
+	"perl":   "#",
+	"node":   "//",
+	"deno":   "//",
+	"bun":    "//",
+}
+
+// defaultCommentStyles maps extensions to their comment style. It is consulted for
+// extensions without a configured comment style; any other extension uses "//".
+var defaultCommentStyles = map[string]string{
+	".go":            "//",
+	".proto":         "//",
+	".thrift":        "//",
+	".fbs":           "//",
+	".adoc":          "//",
+	".rs":            "//",
+	".kt":            "//",
+	".kts":           "//",
+	".swift":         "//",
+	".js":            "//",
+	".jsx":           "//",
+	".ts":            "//",
+	".tsx":           "//",
+	".java":          "//",
+	".c":             "//",
+	".cpp":           "//",
+	".h":             "//",
+	".hpp":           "//",
+	".sh":            "#",
+	".py":            "#",
+	".rb":            "#",
+	".hcl":           "#",
+	".tf":            "#",
+	".yml":           "#",
+	".yaml":          "#",
+	".md":            "<!--",
+	".html.markdown": "<!--",
+	".rst":           "..",
+}
+
 type Config struct {
-	Copyright  Copyright  `yaml:"copyright"`
-	License    License    `yaml:"license"`
-	Files      Files      `yaml:"files"`
-	Detection  Detection  `yaml:"detection"`
-	ThirdParty ThirdParty `yaml:"third_party"`
+	Copyright  Copyright  `yaml:"copyright" mapstructure:"copyright"`
+	License    License    `yaml:"license" mapstructure:"license"`
+	Files      Files      `yaml:"files" mapstructure:"files"`
+	Detection  Detection  `yaml:"detection" mapstructure:"detection"`
+	ThirdParty ThirdParty `yaml:"third_party" mapstructure:"third_party"`
 }
 
 type Copyright struct {
-	Holder      string `yaml:"holder" mapstructure:"holder"`
-	StartYear   int    `yaml:"start_year" mapstructure:"start_year"`
-	CurrentYear int    `yaml:"current_year" mapstructure:"current_year"`
-	Format      string `yaml:"format" mapstructure:"format"`
+	Holder            string   `yaml:"holder" mapstructure:"holder"`
+	StartYear         int      `yaml:"start_year" mapstructure:"start_year"`
+	CurrentYear       int      `yaml:"current_year" mapstructure:"current_year"`
+	Format            string   `yaml:"format" mapstructure:"format"`
+	LegacyFormats     []string `yaml:"legacy_formats" mapstructure:"legacy_formats"`
+	StripTrailingText bool     `yaml:"strip_trailing_text" mapstructure:"strip_trailing_text"`
+	HolderAliases     []string `yaml:"holder_aliases" mapstructure:"holder_aliases"`
+	BannerBefore      string   `yaml:"banner_before" mapstructure:"banner_before"`
+	BannerAfter       string   `yaml:"banner_after" mapstructure:"banner_after"`
+	StartYearFromGit  bool     `yaml:"start_year_from_git" mapstructure:"start_year_from_git"`
+	// CollapseYears writes a range from a year to the same year, e.g. "2025, 2025", as "2025"
+	CollapseYears bool `yaml:"collapse_years" mapstructure:"collapse_years"`
+	// FormatsByExt overrides Format for the extensions listed, keyed like files.comment_styles
+	FormatsByExt map[string]string `yaml:"formats_by_ext" mapstructure:"formats_by_ext"`
+	// UpdateYearOnly updates just the years of an outdated copyright line naming the holder,
+	// leaving the rest of the line as it is, instead of rewriting the whole line
+	UpdateYearOnly bool `yaml:"update_year_only" mapstructure:"update_year_only"`
+
+	// Now is the time formats render dates from, e.g. {{.Now.Format "2006-01-02"}}. It is
+	// set at startup (see --now) rather than configured; when zero the current time is used.
+	Now time.Time `yaml:"-" mapstructure:"-"`
+}
+
+// Year returns the year headers end with: CurrentYear when set, otherwise the year of Now,
+// or of the current time, so headers do not go stale every January
+func (c Copyright) Year() int {
+	if c.CurrentYear != 0 {
+		return c.CurrentYear
+	}
+	if c.Now.IsZero() {
+		return time.Now().Year()
+	}
+	return c.Now.Year()
 }
 
 type License struct {
 	Enabled    bool   `yaml:"enabled" mapstructure:"enabled"`
 	Identifier string `yaml:"identifier" mapstructure:"identifier"`
 	Format     string `yaml:"format" mapstructure:"format"`
+	Notice     string `yaml:"notice" mapstructure:"notice"`
+	// FormatsByExt overrides Format for the extensions listed, keyed like files.comment_styles
+	FormatsByExt map[string]string `yaml:"formats_by_ext" mapstructure:"formats_by_ext"`
 }
 
 type SmartExtensionIndicators struct {
@@ -39,31 +131,89 @@ type SmartExtensionIndicators struct {
 	Filenames []string `yaml:"filenames" mapstructure:"filenames"`
 }
 
+// SmartExtensionOverride forces files matching Pattern to be treated as Extension,
+// bypassing smart-extension content detection
+type SmartExtensionOverride struct {
+	Pattern   string `yaml:"pattern" mapstructure:"pattern"`
+	Extension string `yaml:"extension" mapstructure:"extension"`
+}
+
+// BlockCommentStyle writes the header as a block comment: Open and Close on lines of their
+// own and each header line prefixed with Continuation, e.g. "/*", " * " and " */"
+type BlockCommentStyle struct {
+	Open         string `yaml:"open" mapstructure:"open"`
+	Continuation string `yaml:"continuation" mapstructure:"continuation"`
+	Close        string `yaml:"close" mapstructure:"close"`
+}
+
 type PlacementExceptions struct {
-	XMLDeclaration  bool     `yaml:"xml_declaration" mapstructure:"xml_declaration"`
-	MarkdownHeading bool     `yaml:"markdown_heading" mapstructure:"markdown_heading"`
-	Frontmatter     []string `yaml:"frontmatter" mapstructure:"frontmatter"`
+	XMLDeclaration            bool     `yaml:"xml_declaration" mapstructure:"xml_declaration"`
+	MarkdownHeading           bool     `yaml:"markdown_heading" mapstructure:"markdown_heading"`
+	PHPOpenTag                bool     `yaml:"php_open_tag" mapstructure:"php_open_tag"`
+	LeadingLines              []string `yaml:"leading_lines" mapstructure:"leading_lines"`
+	Frontmatter               []string `yaml:"frontmatter" mapstructure:"frontmatter"`
+	FrontmatterFormats        []string `yaml:"frontmatter_formats" mapstructure:"frontmatter_formats"`
+	BlankLineAfterFrontmatter bool     `yaml:"blank_line_after_frontmatter" mapstructure:"blank_line_after_frontmatter"`
 }
 
 type Files struct {
-	Extensions               []string                   `yaml:"extensions" mapstructure:"extensions"`
-	SmartExtensions          []string                   `yaml:"smart_extensions" mapstructure:"smart_extensions"`
-	SmartExtensionIndicators []SmartExtensionIndicators `yaml:"smart_extension_indicators" mapstructure:"smart_extension_indicators"`
-	IgnorePatterns           []string                   `yaml:"ignore_patterns" mapstructure:"ignore_patterns"`
-	IncludePaths             []string                   `yaml:"include_paths" mapstructure:"include_paths"`
-	ExcludePaths             []string                   `yaml:"exclude_paths" mapstructure:"exclude_paths"`
-	CommentStyles            map[string]string          `yaml:"comment_styles" mapstructure:"comment_styles"`
-	BelowFrontmatter         []string                   `yaml:"below_frontmatter" mapstructure:"below_frontmatter"`
-	PlacementExceptions      PlacementExceptions        `yaml:"placement_exceptions" mapstructure:"placement_exceptions"`
-	GitTracked               bool                       `yaml:"git_tracked" mapstructure:"git_tracked"`
+	Extensions               []string                     `yaml:"extensions" mapstructure:"extensions"`
+	SmartExtensions          []string                     `yaml:"smart_extensions" mapstructure:"smart_extensions"`
+	SmartExtensionIndicators []SmartExtensionIndicators   `yaml:"smart_extension_indicators" mapstructure:"smart_extension_indicators"`
+	SmartExtensionOverrides  []SmartExtensionOverride     `yaml:"smart_extension_overrides" mapstructure:"smart_extension_overrides"`
+	IgnorePatterns           []string                     `yaml:"ignore_patterns" mapstructure:"ignore_patterns"`
+	IncludePaths             []string                     `yaml:"include_paths" mapstructure:"include_paths"`
+	ExcludePaths             []string                     `yaml:"exclude_paths" mapstructure:"exclude_paths"`
+	CommentStyles            map[string]string            `yaml:"comment_styles" mapstructure:"comment_styles"`
+	UnspacedComments         []string                     `yaml:"unspaced_comments" mapstructure:"unspaced_comments"`
+	BlockCommentStyles       map[string]BlockCommentStyle `yaml:"block_comment_styles" mapstructure:"block_comment_styles"`
+	ShebangCommentStyles     map[string]string            `yaml:"shebang_comment_styles" mapstructure:"shebang_comment_styles"`
+	BelowFrontmatter         []string                     `yaml:"below_frontmatter" mapstructure:"below_frontmatter"`
+	PlacementExceptions      PlacementExceptions          `yaml:"placement_exceptions" mapstructure:"placement_exceptions"`
+	GitTracked               bool                         `yaml:"git_tracked" mapstructure:"git_tracked"`
+	GitFallback              bool                         `yaml:"git_fallback" mapstructure:"git_fallback"`
+	SkipExecutable           bool                         `yaml:"skip_executable" mapstructure:"skip_executable"`
+	BlankAfterShebang        bool                         `yaml:"blank_after_shebang" mapstructure:"blank_after_shebang"`
+
+	// BlankLinesAfterHeader is how many blank lines separate the header from the code. Unset,
+	// fix writes one and leaves existing runs alone; set, headers are held to exactly that many.
+	BlankLinesAfterHeader *int `yaml:"blank_lines_after_header" mapstructure:"blank_lines_after_header"`
+	// BlankLinesAfterHeaderByExt overrides blank_lines_after_header for the extensions listed,
+	// keyed like comment_styles
+	BlankLinesAfterHeaderByExt map[string]int `yaml:"blank_lines_after_header_by_ext" mapstructure:"blank_lines_after_header_by_ext"`
+
+	// Ignored holds the patterns of the .copyplopignore files found where files are processed.
+	// They are read at startup rather than configured.
+	Ignored []IgnoreFile `yaml:"-" mapstructure:"-"`
+}
+
+// IgnoreFile is a .copyplopignore file: doublestar patterns, as in exclude_paths, of files
+// never to process, matched against paths relative to the directory holding the file
+type IgnoreFile struct {
+	Dir      string
+	Patterns []string
 }
 
 type Detection struct {
 	SkipGenerated     bool     `yaml:"skip_generated" mapstructure:"skip_generated"`
 	GeneratedPatterns []string `yaml:"generated_patterns" mapstructure:"generated_patterns"`
 	ReplacePatterns   []string `yaml:"replace_patterns" mapstructure:"replace_patterns"`
-	MaxScanLines      int      `yaml:"max_scan_lines" mapstructure:"max_scan_lines"`
-	RequireAtTop      bool     `yaml:"require_at_top" mapstructure:"require_at_top"`
+	// MaxScanLines is how many lines from where the header belongs it may start on; 0 means
+	// the whole file is searched
+	MaxScanLines     int    `yaml:"max_scan_lines" mapstructure:"max_scan_lines"`
+	RequireAtTop     bool   `yaml:"require_at_top" mapstructure:"require_at_top"`
+	HeaderBlockOnly  bool   `yaml:"header_block_only" mapstructure:"header_block_only"`
+	Minified         string `yaml:"minified" mapstructure:"minified"`
+	PythonDocstrings bool   `yaml:"python_docstrings" mapstructure:"python_docstrings"`
+	// CollapseBlankLines reduces a run of blank lines after the header to a single one
+	CollapseBlankLines bool `yaml:"collapse_blank_lines" mapstructure:"collapse_blank_lines"`
+	// SkipContentPatterns leave files alone when one matches within the first max_scan_lines
+	// lines, e.g. for vendored files with a marker that generated_patterns does not catch
+	SkipContentPatterns []string `yaml:"skip_content_patterns" mapstructure:"skip_content_patterns"`
+	// GeneratedFilePatterns are globs of generated file names, e.g. "*.pb.go", matched against
+	// the base name or, for patterns with a slash, the path; with skip_generated the files are
+	// left alone without being read
+	GeneratedFilePatterns []string `yaml:"generated_file_patterns" mapstructure:"generated_file_patterns"`
 }
 
 type ThirdParty struct {
@@ -71,55 +221,163 @@ type ThirdParty struct {
 	Patterns []string `yaml:"patterns" mapstructure:"patterns"`
 }
 
+// templateFuncs are the functions formats can call besides the built-in ones such as if and ne,
+// e.g. "Copyright {{.Holder}} {{yearRange .StartYear .CurrentYear}}"
+var templateFuncs = template.FuncMap{
+	"yearRange": yearRange,
+	"upper":     strings.ToUpper,
+	"lower":     strings.ToLower,
+}
+
+// parseFormat parses a copyright or license format, with templateFuncs available
+func parseFormat(name, format string) (*template.Template, error) {
+	return template.New(name).Funcs(templateFuncs).Parse(format)
+}
+
+// yearRange renders the years from start to end as "2014-2025", or as "2025" alone when
+// they are the same or start is not set
+func yearRange(start, end any) string {
+	from, to := fmt.Sprint(start), fmt.Sprint(end)
+	if from == to || from == "0" {
+		return to
+	}
+	return from + "-" + to
+}
+
+// defaultFrontmatterFormats are the frontmatter formats recognized when
+// placement_exceptions.frontmatter_formats is not set
+var defaultFrontmatterFormats = []string{"yaml"}
+
+// FrontmatterFormats returns the frontmatter formats the header is placed below: "yaml",
+// "toml" or "json", YAML only by default
+func (c *Config) FrontmatterFormats() []string {
+	if len(c.Files.PlacementExceptions.FrontmatterFormats) > 0 {
+		return c.Files.PlacementExceptions.FrontmatterFormats
+	}
+	return defaultFrontmatterFormats
+}
+
+// HeaderBlankLines returns how many blank lines follow the header in files with ext, and
+// whether that count is configured and so enforced: its entry in
+// files.blank_lines_after_header_by_ext, files.blank_lines_after_header or one. Go files keep
+// at least one, so that the header does not become the package doc comment.
+func (c *Config) HeaderBlankLines(ext string) (int, bool) {
+	extKey := strings.TrimPrefix(ext, ".")
+	extKey = strings.ReplaceAll(extKey, ".", "_")
+	n, exact := 1, false
+	if count, ok := c.Files.BlankLinesAfterHeaderByExt[extKey]; ok {
+		n, exact = count, true
+	} else if c.Files.BlankLinesAfterHeader != nil {
+		n, exact = *c.Files.BlankLinesAfterHeader, true
+	}
+	if ext == ".go" {
+		n = max(n, 1)
+	}
+	return n, exact
+}
+
+// GetCopyrightHeader returns the copyright header for ext. A format spanning several lines,
+// e.g. a YAML block scalar, gives a header of several lines, each in the comment style for ext.
+// With collapse_years, a range from a year to the same year is written as that year alone.
 func (c *Config) GetCopyrightHeader(ext string) (string, error) {
-	tmpl, err := template.New("copyright").Parse(c.Copyright.Format)
+	text, year, err := c.renderCopyright(ext)
 	if err != nil {
 		return "", err
 	}
+	if c.Copyright.CollapseYears {
+		text = collapseYears(text, strconv.Itoa(year))
+	}
+	return c.commentLines(ext, text), nil
+}
 
-	var buf bytes.Buffer
-	err = tmpl.Execute(&buf, c.Copyright)
+// CopyrightHeaderVariants returns the forms of the copyright header for ext that are as
+// current as the one GetCopyrightHeader returns, so they are left as they are: with
+// collapse_years, the header with its single year still written as a range, e.g. "2025, 2025"
+func (c *Config) CopyrightHeaderVariants(ext string) ([]string, error) {
+	if !c.Copyright.CollapseYears {
+		return nil, nil
+	}
+	text, year, err := c.renderCopyright(ext)
 	if err != nil {
-		return "", err
+		return nil, err
+	}
+	if collapseYears(text, strconv.Itoa(year)) == text {
+		return nil, nil
 	}
+	return []string{c.commentLines(ext, text)}, nil
+}
 
-	// Remove the dot from extension for lookup
+// CopyrightFormat returns the copyright format for ext: its entry in copyright.formats_by_ext,
+// or copyright.format
+func (c *Config) CopyrightFormat(ext string) string {
 	extKey := strings.TrimPrefix(ext, ".")
 	extKey = strings.ReplaceAll(extKey, ".", "_")
-	prefix := c.Files.CommentStyles[extKey]
-	if prefix == "" {
-		// Fallback to hardcoded values if not found in config
-		switch ext {
-		case ".go":
-			prefix = "//"
-		case ".sh", ".py", ".hcl", ".tf", ".yml", ".yaml":
-			prefix = "#"
-		case ".md", ".html.markdown":
-			prefix = "<!--"
-		default:
-			prefix = "//"
-		}
+	if format, ok := c.Copyright.FormatsByExt[extKey]; ok {
+		return format
 	}
+	return c.Copyright.Format
+}
 
-	// Special case: HTML/markdown comments need closing -->
-	if prefix == "<!--" {
-		return prefix + " " + buf.String() + " -->", nil
+// LicenseFormat returns the license format for ext: its entry in license.formats_by_ext,
+// or license.format
+func (c *Config) LicenseFormat(ext string) string {
+	extKey := strings.TrimPrefix(ext, ".")
+	extKey = strings.ReplaceAll(extKey, ".", "_")
+	if format, ok := c.License.FormatsByExt[extKey]; ok {
+		return format
 	}
+	return c.License.Format
+}
 
-	// Special case: JS/CSS block comments
-	if prefix == "/**" {
-		return " * " + buf.String(), nil
+// copyrightFormats returns copyright.format and the formats of copyright.formats_by_ext, in
+// a stable order
+func (c *Config) copyrightFormats() []string {
+	formats := []string{c.Copyright.Format}
+	for _, ext := range slices.Sorted(maps.Keys(c.Copyright.FormatsByExt)) {
+		formats = append(formats, c.Copyright.FormatsByExt[ext])
 	}
+	return formats
+}
 
-	// Special case: YAML files need quotes around comments containing colons
-	if ext == ".yml" || ext == ".yaml" {
-		content := buf.String()
-		if strings.Contains(content, ":") {
-			return prefix + " \"" + content + "\"", nil
-		}
+// renderCopyright renders the copyright format for ext, returning the text and the current year
+func (c *Config) renderCopyright(ext string) (string, int, error) {
+	tmpl, err := parseFormat("copyright", c.CopyrightFormat(ext))
+	if err != nil {
+		return "", 0, err
 	}
 
-	return prefix + " " + buf.String(), nil
+	var buf bytes.Buffer
+	data := c.Copyright
+	if data.Now.IsZero() {
+		data.Now = time.Now()
+	}
+	data.CurrentYear = data.Year()
+	err = tmpl.Execute(&buf, data)
+	if err != nil {
+		return "", 0, err
+	}
+	return buf.String(), data.CurrentYear, nil
+}
+
+// commentLines puts each line of text in the comment style for ext
+func (c *Config) commentLines(ext, text string) string {
+	lines := strings.Split(strings.TrimRight(text, "\n"), "\n")
+	for i, line := range lines {
+		// A blank line of the format is a comment without text, without trailing space
+		lines[i] = strings.TrimRight(c.formatComment(ext, line), " ")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// yearSeparators are the separators of a year range that collapseYears recognizes
+var yearSeparators = []string{", ", ",", " - ", "-", "–"}
+
+// collapseYears writes a range from year to the same year, such as "2025, 2025", as year alone
+func collapseYears(text, year string) string {
+	for _, sep := range yearSeparators {
+		text = strings.ReplaceAll(text, year+sep+year, year)
+	}
+	return text
 }
 
 func (c *Config) GetLicenseHeader(ext string) (string, error) {
@@ -127,7 +385,28 @@ func (c *Config) GetLicenseHeader(ext string) (string, error) {
 		return "", nil
 	}
 
-	tmpl, err := template.New("license").Parse(c.License.Format)
+	tmpl, err := parseFormat("license", c.LicenseFormat(ext))
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	err = tmpl.Execute(&buf, c.License)
+	if err != nil {
+		return "", err
+	}
+
+	return c.formatComment(ext, buf.String()), nil
+}
+
+// GetNoticeHeader returns the optional notice line (e.g. "See NOTICE file") that
+// follows the license line, or an empty string if no notice is configured
+func (c *Config) GetNoticeHeader(ext string) (string, error) {
+	if c.License.Notice == "" {
+		return "", nil
+	}
+
+	tmpl, err := parseFormat("notice", c.License.Notice)
 	if err != nil {
 		return "", err
 	}
@@ -138,46 +417,232 @@ func (c *Config) GetLicenseHeader(ext string) (string, error) {
 		return "", err
 	}
 
+	return c.formatComment(ext, buf.String()), nil
+}
+
+// GetBannerLines returns the configured banner lines in the comment style for ext;
+// a banner that is not configured is returned as an empty string
+func (c *Config) GetBannerLines(ext string) (before, after string) {
+	if c.Copyright.BannerBefore != "" {
+		before = c.formatComment(ext, c.Copyright.BannerBefore)
+	}
+	if c.Copyright.BannerAfter != "" {
+		after = c.formatComment(ext, c.Copyright.BannerAfter)
+	}
+	return before, after
+}
+
+// CommentPrefix returns the comment prefix configured for ext, falling back to
+// built-in defaults for common extensions
+func (c *Config) CommentPrefix(ext string) string {
 	// Remove the dot from extension for lookup
 	extKey := strings.TrimPrefix(ext, ".")
 	extKey = strings.ReplaceAll(extKey, ".", "_")
-	prefix := c.Files.CommentStyles[extKey]
-	if prefix == "" {
-		// Fallback to hardcoded values if not found in config
-		switch ext {
-		case ".go":
-			prefix = "//"
-		case ".sh", ".py", ".hcl", ".tf", ".yml", ".yaml":
-			prefix = "#"
-		case ".md", ".html.markdown":
-			prefix = "<!--"
-		default:
-			prefix = "//"
+	if style := c.Files.BlockCommentStyles[extKey]; style.Open != "" {
+		return style.Open
+	}
+	if prefix := c.Files.CommentStyles[extKey]; prefix != "" {
+		if style, ok := pairedCommentStyle(prefix); ok {
+			return style.Open
 		}
+		return prefix
+	}
+
+	if prefix, ok := defaultCommentStyles[ext]; ok {
+		return prefix
+	}
+	return "//"
+}
+
+// javadocStyle is the block comment written for the "/**" comment style
+var javadocStyle = BlockCommentStyle{Open: "/**", Continuation: " * ", Close: " */"}
+
+// pairedCommentStyle reads a comment style holding an opening and a closing token, such as
+// "/* */", as a block comment. Lines inside comments opened with "*", as in C, are prefixed
+// " * " and the close is aligned below them; other pairs indent their lines by two spaces.
+func pairedCommentStyle(value string) (BlockCommentStyle, bool) {
+	fields := strings.Fields(value)
+	if len(fields) != 2 {
+		return BlockCommentStyle{}, false
+	}
+	if strings.HasSuffix(fields[0], "*") {
+		return BlockCommentStyle{Open: fields[0], Continuation: " * ", Close: " " + fields[1]}, true
+	}
+	return BlockCommentStyle{Open: fields[0], Continuation: "  ", Close: fields[1]}, true
+}
+
+// BlockComment returns the block comment style for ext, reporting whether headers in ext are
+// written as a block comment: one from block_comment_styles, a paired comment style such as
+// "/* */" or the "/**" comment style
+func (c *Config) BlockComment(ext string) (BlockCommentStyle, bool) {
+	extKey := strings.TrimPrefix(ext, ".")
+	extKey = strings.ReplaceAll(extKey, ".", "_")
+	if style := c.Files.BlockCommentStyles[extKey]; style.Open != "" {
+		return style, true
+	}
+	if style, ok := pairedCommentStyle(c.Files.CommentStyles[extKey]); ok {
+		return style, true
+	}
+	if c.CommentPrefix(ext) == "/**" {
+		return javadocStyle, true
+	}
+	return BlockCommentStyle{}, false
+}
+
+// commentDelimiters returns the tokens a header line for ext opens and closes with: the
+// comment prefix and, for HTML comments, "-->"; lines of line comments close with ""
+func (c *Config) commentDelimiters(ext string) (open, close string) {
+	open = c.CommentPrefix(ext)
+	if open == "<!--" {
+		return open, "-->"
 	}
+	return open, ""
+}
+
+// formatComment wraps content in the comment style configured for ext
+func (c *Config) formatComment(ext, content string) string {
+	prefix, closer := c.commentDelimiters(ext)
 
 	// Special case: HTML/markdown comments need closing -->
-	if prefix == "<!--" {
-		return prefix + " " + buf.String() + " -->", nil
+	if closer != "" {
+		return prefix + " " + content + " " + closer
 	}
 
-	// Special case: JS/CSS block comments
-	if prefix == "/**" {
-		return " * " + buf.String(), nil
+	// Special case: block comments such as JS/CSS /** */
+	if style, ok := c.BlockComment(ext); ok {
+		return style.Continuation + content
 	}
 
+	space := c.commentSpace(ext)
+
 	// Special case: YAML files need quotes around comments containing colons
-	if ext == ".yml" || ext == ".yaml" {
-		content := buf.String()
-		if strings.Contains(content, ":") {
-			return prefix + " \"" + content + "\"", nil
+	if (ext == ".yml" || ext == ".yaml") && strings.Contains(content, ":") {
+		return prefix + space + "\"" + content + "\""
+	}
+
+	return prefix + space + content
+}
+
+// commentSpace returns what separates the comment prefix for ext from the text of a header
+// line: a space, or nothing for the extensions listed in files.unspaced_comments, e.g. "//Copyright"
+func (c *Config) commentSpace(ext string) string {
+	extKey := strings.TrimPrefix(ext, ".")
+	extKey = strings.ReplaceAll(extKey, ".", "_")
+	if slices.Contains(c.Files.UnspacedComments, extKey) {
+		return ""
+	}
+	return " "
+}
+
+// HasCommentSpacing reports whether a header line in the line comment style for ext separates
+// its prefix from the text as the headers written for ext do. Lines of block or HTML comments,
+// and lines not starting with the prefix, are not held to it.
+func (c *Config) HasCommentSpacing(line, ext string) bool {
+	prefix, closer := c.commentDelimiters(ext)
+	if _, ok := c.BlockComment(ext); ok || closer != "" {
+		return true
+	}
+	after, ok := strings.CutPrefix(strings.TrimSpace(line), prefix)
+	// A longer run of the prefix's last character, such as "///", is still the prefix
+	after = strings.TrimLeft(after, prefix[len(prefix)-1:])
+	if !ok || after == "" {
+		return true
+	}
+	spaced := after[0] == ' ' || after[0] == '\t'
+	return spaced == (c.commentSpace(ext) != "")
+}
+
+// ShebangInterpreter extracts the interpreter name from a shebang line,
+// e.g. "#!/usr/bin/env python3" returns "python3"
+func ShebangInterpreter(line string) string {
+	after, ok := strings.CutPrefix(strings.TrimSpace(line), "#!")
+	if !ok {
+		return ""
+	}
+
+	fields := strings.Fields(after)
+	if len(fields) == 0 {
+		return ""
+	}
+
+	interpreter := filepath.Base(fields[0])
+	if interpreter == "env" {
+		// Skip env options such as -S
+		interpreter = ""
+		for _, field := range fields[1:] {
+			if !strings.HasPrefix(field, "-") {
+				interpreter = filepath.Base(field)
+				break
+			}
+		}
+	}
+	return interpreter
+}
+
+// shebangCommentStyle returns the comment style for the interpreter named in the
+// shebang line, checking configured styles before the built-in defaults
+func (c *Config) shebangCommentStyle(shebang string) string {
+	interpreter := ShebangInterpreter(shebang)
+	if interpreter == "" {
+		return ""
+	}
+
+	// Try the exact name first, then without a version suffix (python3.12 -> python)
+	candidates := []string{interpreter, strings.TrimRight(interpreter, "0123456789.")}
+	for _, name := range candidates {
+		if prefix := c.Files.ShebangCommentStyles[name]; prefix != "" {
+			return prefix
+		}
+	}
+	for _, name := range candidates {
+		if prefix := defaultShebangCommentStyles[name]; prefix != "" {
+			return prefix
 		}
 	}
+	return ""
+}
+
+// ForShebang returns a config that uses the shebang interpreter's comment style for ext
+// when ext has no configured comment style. Otherwise it returns c unchanged.
+func (c *Config) ForShebang(shebang, ext string) *Config {
+	extKey := strings.TrimPrefix(ext, ".")
+	extKey = strings.ReplaceAll(extKey, ".", "_")
+	if c.Files.CommentStyles[extKey] != "" || c.Files.BlockCommentStyles[extKey].Open != "" {
+		return c
+	}
+
+	prefix := c.shebangCommentStyle(shebang)
+	if prefix == "" {
+		return c
+	}
+	return c.WithCommentStyle(ext, prefix)
+}
+
+// WithCommentStyle returns a copy of the config that uses prefix as the comment style for ext
+func (c *Config) WithCommentStyle(ext, prefix string) *Config {
+	extKey := strings.TrimPrefix(ext, ".")
+	extKey = strings.ReplaceAll(extKey, ".", "_")
 
-	return prefix + " " + buf.String(), nil
+	clone := *c
+	clone.Files.CommentStyles = maps.Clone(c.Files.CommentStyles)
+	if clone.Files.CommentStyles == nil {
+		clone.Files.CommentStyles = make(map[string]string)
+	}
+	clone.Files.CommentStyles[extKey] = prefix
+	if _, ok := c.Files.BlockCommentStyles[extKey]; ok {
+		clone.Files.BlockCommentStyles = maps.Clone(c.Files.BlockCommentStyles)
+		delete(clone.Files.BlockCommentStyles, extKey)
+	}
+	return &clone
 }
 
 func (c *Config) ShouldProcess(file string) bool {
+	return c.SkipReason(file) == ""
+}
+
+// SkipReason returns why ShouldProcess leaves file out, its extension, a generated file name
+// or the path filters, or "" when file is processed
+func (c *Config) SkipReason(file string) string {
 	// Check extension first
 	hasValidExt := false
 
@@ -200,11 +665,43 @@ func (c *Config) ShouldProcess(file string) bool {
 	}
 
 	if !hasValidExt {
-		return false
+		return "extension not in files.extensions or files.smart_extensions"
+	}
+
+	if pattern, ok := c.generatedFilePattern(file); ok {
+		return fmt.Sprintf("generated file name matches %q (detection.generated_file_patterns)", pattern)
 	}
 
 	// Apply path filtering logic
-	return c.shouldProcessPath(file)
+	return c.pathSkipReason(file)
+}
+
+// generatedFilePattern returns the generated_file_patterns entry that file matches, when
+// skip_generated is set
+func (c *Config) generatedFilePattern(file string) (string, bool) {
+	if !c.Detection.SkipGenerated {
+		return "", false
+	}
+	file = filepath.ToSlash(file)
+	for _, pattern := range c.Detection.GeneratedFilePatterns {
+		target := path.Base(file)
+		if strings.Contains(pattern, "/") {
+			target = file
+		}
+		if matched, _ := doublestar.Match(pattern, target); matched {
+			return pattern, true
+		}
+	}
+	return "", false
+}
+
+// ShouldProcessMode reports whether a file with the given mode should be processed;
+// with skip_executable set, files with any execute bit are left alone
+func (c *Config) ShouldProcessMode(mode fs.FileMode) bool {
+	if c.Files.SkipExecutable && mode&0o111 != 0 {
+		return false
+	}
+	return true
 }
 
 // shouldProcessPath implements the include/exclude path logic:
@@ -213,19 +710,28 @@ func (c *Config) ShouldProcess(file string) bool {
 // - Has excludes = process everything except excludes
 // - Has both = process files that match includes AND don't match excludes
 func (c *Config) shouldProcessPath(file string) bool {
+	return c.pathSkipReason(file) == ""
+}
+
+// pathSkipReason returns why the path filters leave file out, or "" when they select it
+func (c *Config) pathSkipReason(file string) string {
 	hasIncludes := len(c.Files.IncludePaths) > 0
 	hasExcludes := len(c.Files.ExcludePaths) > 0
 
+	if reason := c.ignoreReason(file); reason != "" {
+		return reason
+	}
+
 	// No path filters = process everything
 	if !hasIncludes && !hasExcludes {
-		return true
+		return ""
 	}
 
 	// Check excludes first (if any)
 	if hasExcludes {
 		for _, pattern := range c.Files.ExcludePaths {
 			if matchesPath(pattern, file) {
-				return false
+				return fmt.Sprintf("matches files.exclude_paths pattern %q", pattern)
 			}
 		}
 	}
@@ -234,14 +740,38 @@ func (c *Config) shouldProcessPath(file string) bool {
 	if hasIncludes {
 		for _, pattern := range c.Files.IncludePaths {
 			if matchesPath(pattern, file) {
-				return true
+				return ""
 			}
 		}
-		return false // Has includes but file didn't match any
+		return "matches no files.include_paths pattern" // Has includes but file didn't match any
 	}
 
 	// Has excludes but no includes, and file didn't match excludes
-	return true
+	return ""
+}
+
+// ignoreReason returns why a .copyplopignore file leaves file out, or "" when none does
+func (c *Config) ignoreReason(file string) string {
+	if len(c.Files.Ignored) == 0 {
+		return ""
+	}
+	abs, err := filepath.Abs(file)
+	if err != nil {
+		return ""
+	}
+	for _, ignore := range c.Files.Ignored {
+		rel, err := filepath.Rel(ignore.Dir, abs)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			continue
+		}
+		rel = filepath.ToSlash(rel)
+		for _, pattern := range ignore.Patterns {
+			if matchesPath(pattern, rel) {
+				return fmt.Sprintf("matches %q in %s", pattern, filepath.Join(ignore.Dir, IgnoreFileName))
+			}
+		}
+	}
+	return ""
 }
 
 // matchesPath checks if a file path matches a pattern, supporting doublestar glob patterns
@@ -268,71 +798,173 @@ func matchesPath(pattern, path string) bool {
 	return false
 }
 
+// IsGenerated reports whether a generated pattern matches one of the first max_scan_lines
+// lines (all lines when 0), the same window headers are looked for in, so markers below a
+// license header or build constraints are found too
 func (c *Config) IsGenerated(lines []string) bool {
-	if !c.Detection.SkipGenerated || len(lines) == 0 {
+	if !c.Detection.SkipGenerated {
+		return false
+	}
+	return c.matchesHeadLines(c.Detection.GeneratedPatterns, lines)
+}
+
+// IsSkippedContent reports whether a file with lines is left alone: it is generated, or one of
+// skip_content_patterns matches within the same first max_scan_lines lines
+func (c *Config) IsSkippedContent(lines []string) bool {
+	return c.IsGenerated(lines) || c.matchesHeadLines(c.Detection.SkipContentPatterns, lines)
+}
+
+// matchesHeadLines reports whether one of patterns matches one of the first max_scan_lines
+// lines, or of all lines when 0
+func (c *Config) matchesHeadLines(patterns, lines []string) bool {
+	if len(patterns) == 0 || len(lines) == 0 {
 		return false
 	}
 
-	for _, pattern := range c.Detection.GeneratedPatterns {
-		re := regexp.MustCompile(pattern)
-		if re.MatchString(lines[0]) || (len(lines) > 1 && re.MatchString(lines[1])) {
+	window := lines
+	if c.Detection.MaxScanLines > 0 && len(window) > c.Detection.MaxScanLines {
+		window = window[:c.Detection.MaxScanLines]
+	}
+
+	for _, line := range window {
+		if matchesAny(patterns, line) {
 			return true
 		}
 	}
 	return false
 }
 
-func (c *Config) ShouldReplace(line string) bool {
-	for _, pattern := range c.Detection.ReplacePatterns {
-		re := regexp.MustCompile(pattern)
-		if re.MatchString(line) {
+// compiledPatterns caches patterns by source. The same few patterns are matched against
+// many lines of every file, by concurrent workers and by copies of a Config alike.
+var compiledPatterns sync.Map // pattern -> *regexp.Regexp, nil if it does not compile
+
+// compilePattern returns pattern compiled, or nil if it is not a valid regular expression
+func compilePattern(pattern string) *regexp.Regexp {
+	if re, ok := compiledPatterns.Load(pattern); ok {
+		return re.(*regexp.Regexp)
+	}
+	re, _ := regexp.Compile(pattern)
+	compiledPatterns.Store(pattern, re)
+	return re
+}
+
+// matchesAny reports whether one of patterns matches line. Invalid patterns, which
+// Validate reports, match nothing.
+func matchesAny(patterns []string, line string) bool {
+	for _, pattern := range patterns {
+		if re := compilePattern(pattern); re != nil && re.MatchString(line) {
 			return true
 		}
 	}
 	return false
 }
 
-func (c *Config) IsThirdPartyCopyright(line string) bool {
+// phpOpenTagPattern matches a PHP opening tag alone on its line; code following the tag on
+// the same line would leave a header below it outside PHP mode
+var phpOpenTagPattern = regexp.MustCompile(`^<\?php\s*$`)
+
+// IsLeadingLine reports whether line is a mandatory first construct that must stay above
+// the header: a PHP opening tag (with php_open_tag set) or a line matching one of the
+// leading_lines patterns
+func (c *Config) IsLeadingLine(line string) bool {
+	if c.Files.PlacementExceptions.PHPOpenTag && phpOpenTagPattern.MatchString(strings.TrimSpace(line)) {
+		return true
+	}
+	return matchesAny(c.Files.PlacementExceptions.LeadingLines, line)
+}
+
+func (c *Config) ShouldReplace(line string) bool {
+	return matchesAny(c.Detection.ReplacePatterns, line)
+}
+
+// holderYearsPattern returns a pattern matching "Copyright <holder> <years>" at the start of a
+// copyright line, whatever follows, with the years as groups: the first year and, for a range,
+// the separator and the last year
+func holderYearsPattern(holder string) string {
+	return `\bCopyright\s+(?:\(c\)\s+|©\s+)?` + fieldsPattern(holder) + `,?\s+(\d{4})(?:(\s*(?:,|-|–)\s*)(\d{4}))?\b`
+}
+
+// UpdateCopyrightYear returns line, a comment starting "Copyright <holder> <years>", brought up
+// to the current year for update_year_only: the last year of a range is set to the current year,
+// and a single earlier year becomes the start of a range rendered as the copyright format does,
+// e.g. "Copyright IBM Corp. 2014" becomes "Copyright IBM Corp. 2014, 2026". The rest of the line,
+// trailing text included, is left as it is. It reports false for lines it does not apply to.
+func (c *Config) UpdateCopyrightYear(line, ext string) (string, bool) {
+	if c.Copyright.Holder == "" || !c.isCommentLine(line, ext) {
+		return "", false
+	}
+	re := compilePattern(holderYearsPattern(c.Copyright.Holder))
+	m := re.FindStringSubmatchIndex(line)
+	// Nothing but the comment delimiters may precede "Copyright"
+	if m == nil || strings.ContainsFunc(line[:m[0]], isWordRune) {
+		return "", false
+	}
+
+	current := strconv.Itoa(c.Copyright.Year())
+	if m[6] >= 0 {
+		return line[:m[6]] + current + line[m[7]:], true
+	}
+	if line[m[2]:m[3]] == current {
+		return line, true
+	}
+
+	// A single year is kept as the start of the range
+	start, _ := strconv.Atoi(line[m[2]:m[3]])
+	clone := *c
+	clone.Copyright.StartYear = start
+	text, _, err := clone.renderCopyright(ext)
+	if err != nil {
+		return "", false
+	}
+	rendered := re.FindStringSubmatchIndex(text)
+	if rendered == nil || rendered[6] < 0 {
+		return "", false
+	}
+	return line[:m[2]] + text[rendered[2]:rendered[7]] + line[m[3]:], true
+}
+
+// isWordRune reports whether r is a letter or digit, as opposed to comment delimiters or quotes
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// IsThirdPartyCopyright reports whether line is a third-party notice: a comment in the style
+// of ext matching a third-party pattern. Text outside comments, such as a Python docstring, is
+// never a notice, as moving it above the header would break the code.
+func (c *Config) IsThirdPartyCopyright(line, ext string) bool {
 	// First check if it matches replacement patterns - if so, NOT third-party
-	if c.ShouldReplace(line) {
+	if c.ShouldReplace(line) || !c.isCommentLine(line, ext) {
 		return false
 	}
 
 	// Then check third-party patterns
-	for _, pattern := range c.ThirdParty.Patterns {
-		re := regexp.MustCompile(pattern)
-		if re.MatchString(line) {
-			return true
-		}
+	return matchesAny(c.ThirdParty.Patterns, line)
+}
+
+// isCommentLine reports whether line starts with the comment prefix for ext or, in the block
+// comment style of ext, with its continuation marker, e.g. "*"
+func (c *Config) isCommentLine(line, ext string) bool {
+	trimmed := strings.TrimSpace(line)
+	if strings.HasPrefix(trimmed, c.CommentPrefix(ext)) {
+		return true
+	}
+	if style, ok := c.BlockComment(ext); ok {
+		marker := strings.TrimSpace(style.Continuation)
+		return marker != "" && strings.HasPrefix(trimmed, marker)
 	}
 	return false
 }
 
 // IsOwnCopyrightLine checks if a line matches our own copyright format (for self-updating)
 func (c *Config) IsOwnCopyrightLine(line, ext string) bool {
-	// Get comment prefix for this extension
-	extKey := strings.TrimPrefix(ext, ".")
-	extKey = strings.ReplaceAll(extKey, ".", "_")
-	prefix := c.Files.CommentStyles[extKey]
-	if prefix == "" {
-		// Fallback to hardcoded values if not found in config
-		switch ext {
-		case ".go":
-			prefix = "//"
-		case ".sh", ".py", ".hcl", ".tf", ".yml", ".yaml":
-			prefix = "#"
-		case ".md", ".html.markdown":
-			prefix = "<!--"
-		default:
-			prefix = "//"
-		}
-	}
+	// Get comment delimiters for this extension
+	prefix, closer := c.commentDelimiters(ext)
 
 	var content string
 
 	// Handle block comment style - don't trim spaces first
-	if prefix == "/**" {
-		if after, ok := strings.CutPrefix(line, " * "); ok {
+	if style, ok := c.BlockComment(ext); ok {
+		if after, ok := strings.CutPrefix(line, style.Continuation); ok {
 			content = strings.TrimSpace(after)
 		} else {
 			return false
@@ -344,8 +976,8 @@ func (c *Config) IsOwnCopyrightLine(line, ext string) bool {
 			content = strings.TrimSpace(after)
 
 			// Handle HTML-style comments
-			if prefix == "<!--" {
-				content = strings.TrimSuffix(content, "-->")
+			if closer != "" {
+				content = strings.TrimSuffix(content, closer)
 				content = strings.TrimSpace(content)
 			}
 		} else {
@@ -353,10 +985,259 @@ func (c *Config) IsOwnCopyrightLine(line, ext string) bool {
 		}
 	}
 
-	// Check if it matches our copyright pattern: "Copyright <holder> <years>"
-	copyrightPattern := `^Copyright\s+` + regexp.QuoteMeta(c.Copyright.Holder) + `\s+\d{4}(,\s*\d{4})?$`
-	matched, _ := regexp.MatchString(copyrightPattern, content)
-	return matched
+	return c.isOwnCopyrightContent(content)
+}
+
+// isOwnCopyrightContent checks if comment content matches our copyright pattern: "Copyright <holder> <years>",
+// one of the configured legacy formats, or (with strip_trailing_text) starts with our holder portion.
+// Any of the holder aliases is accepted in place of the holder.
+func (c *Config) isOwnCopyrightContent(content string) bool {
+	holder := c.holderPattern()
+	copyrightPattern := `^Copyright\s+` + holder + `\s+\d{4}(,\s*\d{4})?$`
+	if re := compilePattern(copyrightPattern); re != nil && re.MatchString(content) {
+		return true
+	}
+
+	for _, format := range c.Copyright.LegacyFormats {
+		if matchesFormat(format, holder, content) {
+			return true
+		}
+	}
+
+	// Headers in the current formats with other years or dates are ours too: a format with dates
+	// renders differently every day, one using yearRange "2014-2025" or "2025" depending on the
+	// years, and the lines of a multi-line format other than the copyright line are found this way.
+	// Those of another extension's format are ours as well, e.g. in a renamed file.
+	for _, format := range c.copyrightFormats() {
+		if matchesFormat(format, holder, content) {
+			return true
+		}
+	}
+
+	// Optionally any line starting with our holder portion is ours, whatever follows it
+	// (e.g. "Copyright IBM Corp. 2014, 2025. All rights reserved.")
+	if c.Copyright.StripTrailingText {
+		holderPattern := `^Copyright\s+(?:\(c\)\s+|©\s+)?(?:\d{4}(?:\s*[-,]\s*\d{4})?\s+)?` + holder
+		if re := compilePattern(holderPattern); re != nil && re.MatchString(content) {
+			return true
+		}
+	}
+	return false
+}
+
+// holderPattern returns a pattern matching the holder, tolerating differences in whitespace,
+// or any of the holder aliases. Aliases are regular expressions; one that does not compile
+// is matched literally.
+func (c *Config) holderPattern() string {
+	alternatives := []string{fieldsPattern(c.Copyright.Holder)}
+	for _, alias := range c.Copyright.HolderAliases {
+		// compilePattern caches aliases, as this runs for every line scanned
+		if compilePattern(alias) == nil {
+			alias = regexp.QuoteMeta(alias)
+		}
+		alternatives = append(alternatives, alias)
+	}
+	return "(?:" + strings.Join(alternatives, "|") + ")"
+}
+
+// fieldsPattern quotes text for a pattern in which any run of whitespace matches any other
+func fieldsPattern(text string) string {
+	fields := strings.Fields(text)
+	for i, field := range fields {
+		fields[i] = regexp.QuoteMeta(field)
+	}
+	return strings.Join(fields, `\s+`)
+}
+
+// matchesFormat reports whether content is one of the lines format renders, with any years
+// and dates. Blank lines of the format, which render as comments without text, are not
+// matched: on their own they tell nothing about a header.
+func matchesFormat(format, holder, content string) bool {
+	for line := range strings.SplitSeq(format, "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		if re := compileFormatPattern(line, holder); re != nil && re.MatchString(content) {
+			return true
+		}
+	}
+	return false
+}
+
+// CopyrightLines returns how many lines the longest copyright header spans: one, unless a
+// format spans several lines
+func (c *Config) CopyrightLines() int {
+	lines := 0
+	for _, format := range c.copyrightFormats() {
+		lines = max(lines, strings.Count(strings.TrimRight(format, "\n"), "\n")+1)
+	}
+	return lines
+}
+
+// ScanLines returns how many lines from where the header belongs are searched for it
+// (0 = all lines): max_scan_lines, plus the lines a multi-line copyright format adds, so
+// that configuring such a format does not push the license line out of reach
+func (c *Config) ScanLines() int {
+	if c.Detection.MaxScanLines <= 0 {
+		return 0
+	}
+	return c.Detection.MaxScanLines + c.CopyrightLines() - 1
+}
+
+// Placeholders stand in for years and the holder when turning a legacy format into a pattern.
+// They are in mixed case so that, passed through upper or lower, they are still recognized.
+const (
+	yearPlaceholder      = "\x00Year\x00"
+	startYearPlaceholder = "\x00StartYear\x00"
+	holderPlaceholder    = "\x00Holder\x00"
+)
+
+// nowPlaceholder stands in for .Now; the numbers and names it renders as are generalized
+var nowPlaceholder = time.Date(1999, time.December, 31, 23, 59, 58, 0, time.UTC)
+
+var (
+	digitsPattern     = regexp.MustCompile(`\d+`)
+	dateNamesReplacer = strings.NewReplacer("December", `[[:alpha:]]+`, "Dec", `[[:alpha:]]+`, "Friday", `[[:alpha:]]+`, "Fri", `[[:alpha:]]+`)
+)
+
+// formatPatterns caches the patterns legacyFormatPattern builds, by format line and holder
+// pattern, as every scanned line is matched against them
+var formatPatterns sync.Map // [2]string{format, holder} -> *regexp.Regexp, nil if it fails
+
+// compileFormatPattern returns legacyFormatPattern(format, holder), or nil if it fails
+func compileFormatPattern(format, holder string) *regexp.Regexp {
+	key := [2]string{format, holder}
+	if re, ok := formatPatterns.Load(key); ok {
+		return re.(*regexp.Regexp)
+	}
+	re, _ := legacyFormatPattern(format, holder)
+	formatPatterns.Store(key, re)
+	return re
+}
+
+// legacyFormatPattern turns a copyright format template into a pattern matching headers
+// it produced with any years (and, for formats using .Now, any dates), tolerating
+// differences in whitespace. holder is the pattern matching the holder.
+func legacyFormatPattern(format, holder string) (*regexp.Regexp, error) {
+	tmpl, err := parseFormat("legacy", format)
+	if err != nil {
+		return nil, err
+	}
+
+	placeholders := strings.NewReplacer(
+		yearPlaceholder, `\d{4}`, strings.ToUpper(yearPlaceholder), `\d{4}`, strings.ToLower(yearPlaceholder), `\d{4}`,
+		startYearPlaceholder, `\d{4}`, strings.ToUpper(startYearPlaceholder), `\d{4}`, strings.ToLower(startYearPlaceholder), `\d{4}`,
+		holderPlaceholder, holder, strings.ToUpper(holderPlaceholder), "(?i:"+holder+")", strings.ToLower(holderPlaceholder), "(?i:"+holder+")",
+	)
+	usesNow := strings.Contains(format, ".Now")
+
+	// Formats may tell a start year equal to the current year apart from an earlier one, e.g.
+	// with yearRange, so both cases are rendered, the first also as collapse_years writes it
+	var renderings []string
+	for _, startYear := range []string{yearPlaceholder, startYearPlaceholder} {
+		var buf bytes.Buffer
+		err = tmpl.Execute(&buf, map[string]any{
+			"Holder":      holderPlaceholder,
+			"StartYear":   startYear,
+			"CurrentYear": yearPlaceholder,
+			"Now":         nowPlaceholder,
+		})
+		if err != nil {
+			return nil, err
+		}
+		renderings = append(renderings, buf.String())
+		if startYear == yearPlaceholder {
+			renderings = append(renderings, collapseYears(buf.String(), yearPlaceholder))
+		}
+	}
+
+	var alternatives []string
+	for _, rendering := range renderings {
+		var pattern strings.Builder
+		for i, field := range strings.Fields(rendering) {
+			if i > 0 {
+				pattern.WriteString(`\s+`)
+			}
+			quoted := regexp.QuoteMeta(field)
+			if usesNow {
+				quoted = dateNamesReplacer.Replace(digitsPattern.ReplaceAllString(quoted, `\d+`))
+			}
+			pattern.WriteString(placeholders.Replace(quoted))
+		}
+		if !slices.Contains(alternatives, pattern.String()) {
+			alternatives = append(alternatives, pattern.String())
+		}
+	}
+	return regexp.Compile("^(?:" + strings.Join(alternatives, "|") + ")$")
+}
+
+// commentMarkers lists the comment syntaxes recognized when looking for header lines
+// written in the wrong style, ordered so that longer markers are tried first
+var commentMarkers = []string{"<!--", "/**", "/*", "//", "--", "#", ";", "%", "*"}
+
+// markupCommentMarkers are the comment markers recognized in markup files such as Markdown,
+// where "#", "*", "-" and "%" start headings, list items and other text rather than comments
+var markupCommentMarkers = []string{"<!--", "/**", "/*", "//"}
+
+// commentMarkersFor returns the comment syntaxes recognized in files with ext: those of
+// markup files, commented with "<!--" or "..", leave out the markers that are markup there
+func (c *Config) commentMarkersFor(ext string) []string {
+	if prefix := c.CommentPrefix(ext); prefix == "<!--" || prefix == ".." {
+		return markupCommentMarkers
+	}
+	return commentMarkers
+}
+
+// IsWrongSyntaxHeaderLine checks if a line is one of our copyright or SPDX header lines
+// written in a comment syntax other than the one configured for ext (e.g. "# Copyright" in a .go file)
+func (c *Config) IsWrongSyntaxHeaderLine(line, ext string) bool {
+	trimmed := strings.TrimSpace(line)
+
+	marker := ""
+	for _, m := range c.commentMarkersFor(ext) {
+		if strings.HasPrefix(trimmed, m) {
+			marker = m
+			break
+		}
+	}
+	if marker == "" {
+		return false
+	}
+
+	if marker == c.CommentPrefix(ext) {
+		return false
+	}
+	if style, ok := c.BlockComment(ext); ok && marker == strings.TrimSpace(style.Continuation) {
+		return false
+	}
+
+	content := strings.TrimSpace(strings.TrimPrefix(trimmed, marker))
+	content = strings.TrimSuffix(content, "-->")
+	content = strings.TrimSuffix(content, "*/")
+	content = strings.Trim(strings.TrimSpace(content), `"`)
+
+	if strings.HasPrefix(content, "SPDX-License-Identifier:") {
+		return true
+	}
+	return c.isOwnCopyrightContent(content)
+}
+
+// ForcedSmartExtensionType returns the extension configured for filename in
+// smart_extension_overrides. The first matching pattern wins; patterns without a "/"
+// are matched against the file's base name.
+func (c *Config) ForcedSmartExtensionType(filename string) (string, bool) {
+	for _, override := range c.Files.SmartExtensionOverrides {
+		matched := false
+		if strings.Contains(override.Pattern, "/") {
+			matched = matchesPath(override.Pattern, filepath.ToSlash(filename))
+		} else {
+			matched, _ = doublestar.Match(override.Pattern, filepath.Base(filename))
+		}
+		if matched {
+			return override.Extension, true
+		}
+	}
+	return "", false
 }
 
 // DetectSmartExtensionType analyzes content to determine the actual file type for smart extensions
@@ -380,9 +1261,11 @@ func (c *Config) DetectSmartExtensionType(content []byte, filename string) strin
 	return c.detectByPatterns(contentStr, filename)
 }
 
-// detectByScoring uses configurable indicators to score each extension type
+// detectByScoring uses configurable indicators to score each extension type. Ties go to
+// the indicator listed first, so similar files always classify the same way.
 func (c *Config) detectByScoring(content, filename string) string {
-	scores := make(map[string]int)
+	maxScore := 0
+	bestExt := ".go" // default
 
 	for _, indicator := range c.Files.SmartExtensionIndicators {
 		score := 0
@@ -401,16 +1284,9 @@ func (c *Config) detectByScoring(content, filename string) string {
 			}
 		}
 
-		scores[indicator.Extension] = score
-	}
-
-	// Find extension with highest score
-	maxScore := 0
-	bestExt := ".go" // default
-	for ext, score := range scores {
 		if score > maxScore {
 			maxScore = score
-			bestExt = ext
+			bestExt = indicator.Extension
 		}
 	}
 
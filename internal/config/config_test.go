@@ -4,9 +4,20 @@
 package config
 
 import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 )
 
+func TestCopyright_Year(t *testing.T) {
+	if got, want := (Copyright{}).Year(), time.Now().Year(); got != want {
+		t.Errorf("Year() without a configured year or Now = %d, want the current year %d", got, want)
+	}
+}
+
 func TestGetCopyrightHeader(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -59,6 +70,81 @@ func TestGetCopyrightHeader(t *testing.T) {
 			ext:      ".sh",
 			expected: "# Copyright 2025 Acme Corp",
 		},
+		{
+			name: "date from Now",
+			config: Config{
+				Copyright: Copyright{
+					Holder: "Acme Corp",
+					Format: `Copyright {{.Holder}}, updated {{.Now.Format "2 January 2006"}}`,
+					Now:    time.Date(2025, time.March, 7, 0, 0, 0, 0, time.UTC),
+				},
+				Files: Files{
+					CommentStyles: map[string]string{".go": "//"},
+				},
+			},
+			ext:      ".go",
+			expected: "// Copyright Acme Corp, updated 7 March 2025",
+		},
+		{
+			name: "current year defaults to the year of Now",
+			config: Config{
+				Copyright: Copyright{
+					Holder:    "Acme Corp",
+					StartYear: 2014,
+					Format:    "Copyright {{.Holder}} {{.StartYear}}, {{.CurrentYear}}",
+					Now:       time.Date(2031, time.January, 2, 0, 0, 0, 0, time.UTC),
+				},
+			},
+			ext:      ".go",
+			expected: "// Copyright Acme Corp 2014, 2031",
+		},
+		{
+			name: "configured current year overrides Now",
+			config: Config{
+				Copyright: Copyright{
+					Holder:      "Acme Corp",
+					StartYear:   2014,
+					CurrentYear: 2025,
+					Format:      "Copyright {{.Holder}} {{.StartYear}}, {{.CurrentYear}}",
+					Now:         time.Date(2031, time.January, 2, 0, 0, 0, 0, time.UTC),
+				},
+			},
+			ext:      ".go",
+			expected: "// Copyright Acme Corp 2014, 2025",
+		},
+		{
+			name: "block comment continuation",
+			config: Config{
+				Copyright: Copyright{
+					Holder:      "Acme Corp",
+					CurrentYear: 2025,
+					Format:      "Copyright {{.CurrentYear}} {{.Holder}}",
+				},
+				Files: Files{
+					CommentStyles: map[string]string{"c": "//"},
+					BlockCommentStyles: map[string]BlockCommentStyle{
+						"c": {Open: "/*", Continuation: " * ", Close: " */"},
+					},
+				},
+			},
+			ext:      ".c",
+			expected: " * Copyright 2025 Acme Corp",
+		},
+		{
+			name: "paired comment style",
+			config: Config{
+				Copyright: Copyright{
+					Holder:      "Acme Corp",
+					CurrentYear: 2025,
+					Format:      "Copyright {{.CurrentYear}} {{.Holder}}",
+				},
+				Files: Files{
+					CommentStyles: map[string]string{"css": "/* */"},
+				},
+			},
+			ext:      ".css",
+			expected: " * Copyright 2025 Acme Corp",
+		},
 	}
 
 	for _, tt := range tests {
@@ -74,6 +160,143 @@ func TestGetCopyrightHeader(t *testing.T) {
 	}
 }
 
+func TestGetNoticeHeader(t *testing.T) {
+	tests := []struct {
+		name     string
+		license  License
+		ext      string
+		expected string
+	}{
+		{
+			name:     "no notice configured",
+			license:  License{Enabled: true, Identifier: "Apache-2.0"},
+			ext:      ".go",
+			expected: "",
+		},
+		{
+			name:     "go notice",
+			license:  License{Enabled: true, Identifier: "Apache-2.0", Notice: "See NOTICE file"},
+			ext:      ".go",
+			expected: "// See NOTICE file",
+		},
+		{
+			name:     "markdown notice with template",
+			license:  License{Enabled: true, Identifier: "Apache-2.0", Notice: "{{.Identifier}} - see NOTICE file"},
+			ext:      ".md",
+			expected: "<!-- Apache-2.0 - see NOTICE file -->",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := Config{License: tt.license}
+			result, err := config.GetNoticeHeader(tt.ext)
+			if err != nil {
+				t.Fatalf("GetNoticeHeader() error = %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("GetNoticeHeader() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestShebangInterpreter(t *testing.T) {
+	tests := []struct {
+		line     string
+		expected string
+	}{
+		{"#!/bin/bash", "bash"},
+		{"#!/usr/bin/env python3", "python3"},
+		{"#!/usr/bin/env -S node --no-warnings", "node"},
+		{"#! /usr/bin/perl -w", "perl"},
+		{"package main", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			if result := ShebangInterpreter(tt.line); result != tt.expected {
+				t.Errorf("ShebangInterpreter(%q) = %q, want %q", tt.line, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestForShebang(t *testing.T) {
+	config := &Config{
+		Copyright: Copyright{Holder: "Acme Corp", Format: "Copyright {{.Holder}}"},
+		Files: Files{
+			CommentStyles:        map[string]string{"py": "#"},
+			ShebangCommentStyles: map[string]string{"lua": "--"},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		shebang  string
+		ext      string
+		expected string
+	}{
+		{"node default", "#!/usr/bin/env node", ".sh", "// Copyright Acme Corp"},
+		{"python version suffix", "#!/usr/bin/python3.12", ".cgi", "# Copyright Acme Corp"},
+		{"configured interpreter", "#!/usr/bin/env lua", ".sh", "-- Copyright Acme Corp"},
+		{"configured extension wins", "#!/usr/bin/env node", ".py", "# Copyright Acme Corp"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := config.ForShebang(tt.shebang, tt.ext).GetCopyrightHeader(tt.ext)
+			if err != nil {
+				t.Fatalf("GetCopyrightHeader() error = %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("GetCopyrightHeader() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+
+	if _, ok := config.Files.CommentStyles["sh"]; ok {
+		t.Error("ForShebang() must not modify the original config")
+	}
+}
+
+func TestIsWrongSyntaxHeaderLine(t *testing.T) {
+	config := Config{
+		Copyright: Copyright{Holder: "IBM Corp."},
+		Files: Files{
+			CommentStyles: map[string]string{"go": "//", "yaml": "#", "js": "/**", "md": "<!--"},
+		},
+	}
+
+	tests := []struct {
+		line     string
+		ext      string
+		expected bool
+	}{
+		{"# Copyright IBM Corp. 2014, 2025", ".go", true},
+		{"# SPDX-License-Identifier: MPL-2.0", ".go", true},
+		{"/* Copyright IBM Corp. 2014, 2025 */", ".go", true},
+		{"<!-- SPDX-License-Identifier: MPL-2.0 -->", ".yaml", true},
+		{"// Copyright IBM Corp. 2014, 2025", ".go", false},
+		{"# \"SPDX-License-Identifier: MPL-2.0\"", ".yaml", false},
+		{" * Copyright IBM Corp. 2014, 2025", ".js", false},
+		{"# Copyright (c) Oracle and/or its affiliates.", ".go", false},
+		{"# Installation", ".go", false},
+		{" * Copyright IBM Corp. 2014, 2025", ".go", true},
+		{"// Copyright IBM Corp. 2014, 2025", ".md", true},
+		{"* Copyright IBM Corp. 2014, 2025", ".md", false},
+		{"# Copyright IBM Corp. 2014, 2025", ".md", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			if result := config.IsWrongSyntaxHeaderLine(tt.line, tt.ext); result != tt.expected {
+				t.Errorf("IsWrongSyntaxHeaderLine(%q, %q) = %v, want %v", tt.line, tt.ext, result, tt.expected)
+			}
+		})
+	}
+}
+
 func TestShouldReplace(t *testing.T) {
 	config := Config{
 		Detection: Detection{
@@ -107,6 +330,7 @@ func TestIsGenerated(t *testing.T) {
 				"Code generated",
 				"DO NOT EDIT",
 			},
+			MaxScanLines: 4,
 		},
 	}
 
@@ -125,11 +349,21 @@ func TestIsGenerated(t *testing.T) {
 			lines:    []string{"package main", "// DO NOT EDIT"},
 			expected: true,
 		},
+		{
+			name:     "generated marker below build constraints",
+			lines:    []string{"//go:build linux", "", "// Code generated by stringer. DO NOT EDIT.", "package main"},
+			expected: true,
+		},
 		{
 			name:     "normal file",
 			lines:    []string{"package main", "import \"fmt\""},
 			expected: false,
 		},
+		{
+			name:     "marker beyond scan window",
+			lines:    []string{"package main", "", "import \"fmt\"", "", "// DO NOT EDIT"},
+			expected: false,
+		},
 		{
 			name:     "empty file",
 			lines:    []string{},
@@ -147,6 +381,64 @@ func TestIsGenerated(t *testing.T) {
 	}
 }
 
+func TestIsSkippedContent(t *testing.T) {
+	config := Config{
+		Detection: Detection{
+			GeneratedPatterns:   []string{"Code generated"},
+			SkipContentPatterns: []string{`AUTO-GENERATED DO NOT MODIFY`, `^// vendored from `},
+			MaxScanLines:        3,
+		},
+	}
+
+	tests := []struct {
+		name          string
+		lines         []string
+		skipGenerated bool
+		expected      bool
+	}{
+		{
+			name:     "skip content pattern",
+			lines:    []string{"package main", "// AUTO-GENERATED DO NOT MODIFY"},
+			expected: true,
+		},
+		{
+			name:     "anchored pattern",
+			lines:    []string{"// vendored from github.com/example/lib", "package lib"},
+			expected: true,
+		},
+		{
+			name:     "pattern beyond scan window",
+			lines:    []string{"package main", "", "import \"fmt\"", "// AUTO-GENERATED DO NOT MODIFY"},
+			expected: false,
+		},
+		{
+			name:     "generated without skip_generated",
+			lines:    []string{"// Code generated by stringer", "package main"},
+			expected: false,
+		},
+		{
+			name:          "generated",
+			lines:         []string{"// Code generated by stringer", "package main"},
+			skipGenerated: true,
+			expected:      true,
+		},
+		{
+			name:     "normal file",
+			lines:    []string{"package main", "import \"fmt\""},
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config.Detection.SkipGenerated = tt.skipGenerated
+			if got := config.IsSkippedContent(tt.lines); got != tt.expected {
+				t.Errorf("IsSkippedContent() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
 func TestIsThirdPartyCopyright_Precedence(t *testing.T) {
 	config := Config{
 		Detection: Detection{
@@ -183,11 +475,16 @@ func TestIsThirdPartyCopyright_Precedence(t *testing.T) {
 			line:     "package main",
 			expected: false,
 		},
+		{
+			name:     "copyright outside a comment, e.g. in a docstring",
+			line:     "Copyright Oracle Corp.",
+			expected: false,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := config.IsThirdPartyCopyright(tt.line)
+			result := config.IsThirdPartyCopyright(tt.line, ".go")
 			if result != tt.expected {
 				t.Errorf("IsThirdPartyCopyright(%q) = %v, want %v", tt.line, result, tt.expected)
 			}
@@ -287,6 +584,44 @@ func TestConfig_shouldProcessPath(t *testing.T) {
 	}
 }
 
+func TestGeneratedFilePatterns(t *testing.T) {
+	c := &Config{
+		Files: Files{Extensions: []string{".go", ".py"}},
+		Detection: Detection{
+			SkipGenerated:         true,
+			GeneratedFilePatterns: []string{"*_gen.go", "*.pb.go", "zz_generated.*", "internal/gen/**"},
+		},
+	}
+
+	tests := []struct {
+		file string
+		want bool
+	}{
+		{"internal/api/types_gen.go", false},
+		{"api/v1/service.pb.go", false},
+		{"apis/zz_generated.deepcopy.go", false},
+		{"internal/gen/client.py", false},
+		{"internal/api/types.go", true},
+		{"internal/generate.go", true},
+		{"gen/client.py", true},
+	}
+	for _, tt := range tests {
+		if got := c.ShouldProcess(tt.file); got != tt.want {
+			t.Errorf("ShouldProcess(%q) = %v, want %v", tt.file, got, tt.want)
+		}
+	}
+
+	if got, want := c.SkipReason("service.pb.go"), `generated file name matches "*.pb.go" (detection.generated_file_patterns)`; got != want {
+		t.Errorf("SkipReason() = %q, want %q", got, want)
+	}
+
+	// Like generated_patterns, the patterns only apply with skip_generated
+	c.Detection.SkipGenerated = false
+	if !c.ShouldProcess("service.pb.go") {
+		t.Error("ShouldProcess() = false without skip_generated, want true")
+	}
+}
+
 func TestDetectSmartExtensionType(t *testing.T) {
 	config := &Config{}
 
@@ -379,3 +714,673 @@ func TestDetectSmartExtensionType(t *testing.T) {
 		})
 	}
 }
+
+func TestDetectSmartExtensionType_TieBreak(t *testing.T) {
+	config := &Config{
+		Files: Files{
+			SmartExtensionIndicators: []SmartExtensionIndicators{
+				{Extension: ".tf", Patterns: []string{"resource \"", "variable \""}},
+				{Extension: ".md", Patterns: []string{"# ", "]("}},
+				{Extension: ".yml", Patterns: []string{"---", ": "}},
+			},
+		},
+	}
+
+	// Structurally similar templates that score one point for every indicator
+	templates := map[string]string{
+		"a.gtpl": "# {{ .Name }}\nresource \"x\"\n---\n",
+		"b.gtpl": "# {{ .Title }}\nresource \"y\"\n---\n",
+	}
+
+	for name, content := range templates {
+		for range 50 {
+			if got := config.DetectSmartExtensionType([]byte(content), name); got != ".tf" {
+				t.Fatalf("DetectSmartExtensionType(%s) = %v, want .tf, the first indicator listed", name, got)
+			}
+		}
+	}
+
+	// A higher score still beats an earlier indicator
+	if got := config.DetectSmartExtensionType([]byte("# Title\n[link](url)\nresource \"x\"\n"), "c.gtpl"); got != ".md" {
+		t.Errorf("DetectSmartExtensionType() = %v, want .md", got)
+	}
+}
+
+func TestForcedSmartExtensionType(t *testing.T) {
+	config := &Config{
+		Files: Files{
+			SmartExtensionOverrides: []SmartExtensionOverride{
+				{Pattern: "internal/service/**/*.tmpl", Extension: ".go"},
+				{Pattern: "*.md.tmpl", Extension: ".md"},
+				{Pattern: "*.tmpl", Extension: ".tf"},
+			},
+		},
+	}
+
+	tests := []struct {
+		filename string
+		expected string
+		forced   bool
+	}{
+		{"internal/service/ec2/resource.tmpl", ".go", true},
+		{"internal/service/ec2/README.md.tmpl", ".go", true},
+		{"website/docs/README.md.tmpl", ".md", true},
+		{"examples/main.tmpl", ".tf", true},
+		{"templates/main.gtpl", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.filename, func(t *testing.T) {
+			ext, forced := config.ForcedSmartExtensionType(tt.filename)
+			if ext != tt.expected || forced != tt.forced {
+				t.Errorf("ForcedSmartExtensionType(%q) = %q, %v; want %q, %v", tt.filename, ext, forced, tt.expected, tt.forced)
+			}
+		})
+	}
+}
+
+func TestIsOwnCopyrightLine_LegacyFormats(t *testing.T) {
+	config := &Config{
+		Copyright: Copyright{
+			Holder: "IBM Corp.",
+			Format: "Copyright {{.Holder}} {{.StartYear}}, {{.CurrentYear}}",
+			LegacyFormats: []string{
+				"Copyright (c) {{.StartYear}}-{{.CurrentYear}} {{.Holder}} All rights reserved.",
+				"(C) {{.Holder}} {{.CurrentYear}}",
+			},
+		},
+		Files: Files{
+			CommentStyles: map[string]string{"go": "//"},
+		},
+	}
+
+	tests := []struct {
+		line     string
+		expected bool
+	}{
+		{"// Copyright IBM Corp. 2014, 2025", true},
+		{"// Copyright (c) 2014-2023 IBM Corp. All rights reserved.", true},
+		{"//   Copyright (c)  2014-2023 IBM Corp.  All rights reserved.", true},
+		{"// (C) IBM Corp. 2020", true},
+		{"// Copyright (c) 2014-2023 Oracle. All rights reserved.", false},
+		{"// Copyright (c) 2014-2023 IBM Corp. All rights reserved. Extra", false},
+		{"// (C) IBM Corp. twenty", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			if got := config.IsOwnCopyrightLine(tt.line, ".go"); got != tt.expected {
+				t.Errorf("IsOwnCopyrightLine(%q) = %v, want %v", tt.line, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestIsOwnCopyrightLine_StripTrailingText(t *testing.T) {
+	config := &Config{
+		Copyright: Copyright{
+			Holder:            "IBM Corp.",
+			StripTrailingText: true,
+		},
+		Files: Files{
+			CommentStyles: map[string]string{"go": "//"},
+		},
+	}
+
+	tests := []struct {
+		line     string
+		expected bool
+	}{
+		{"// Copyright IBM Corp. 2014, 2025", true},
+		{"// Copyright IBM Corp. 2014, 2025. All rights reserved.", true},
+		{"// Copyright (c) 2020 IBM Corp. All rights reserved.", true},
+		{"// Copyright © IBM Corp.", true},
+		{"// Copyright 2020 Oracle. All rights reserved.", false},
+		{"// This file mentions Copyright IBM Corp. in passing", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			if got := config.IsOwnCopyrightLine(tt.line, ".go"); got != tt.expected {
+				t.Errorf("IsOwnCopyrightLine(%q) = %v, want %v", tt.line, got, tt.expected)
+			}
+		})
+	}
+
+	config.Copyright.StripTrailingText = false
+	if config.IsOwnCopyrightLine("// Copyright IBM Corp. 2014, 2025. All rights reserved.", ".go") {
+		t.Error("trailing text should not match when strip_trailing_text is off")
+	}
+}
+
+func TestIsOwnCopyrightLine_HolderAliases(t *testing.T) {
+	config := &Config{
+		Copyright: Copyright{
+			Holder:        "IBM Corp.",
+			HolderAliases: []string{"IBM Corporation", `International Business Machines( Corp(oration|\.))?`, "IBM (Inc"},
+			LegacyFormats: []string{"Copyright (c) {{.StartYear}}-{{.CurrentYear}} {{.Holder}}"},
+		},
+		Files: Files{
+			CommentStyles: map[string]string{"go": "//"},
+		},
+	}
+
+	tests := []struct {
+		line     string
+		expected bool
+	}{
+		{"// Copyright IBM Corp. 2014, 2025", true},
+		{"// Copyright IBM Corporation 2014, 2025", true},
+		{"// Copyright International Business Machines Corporation 2020", true},
+		{"// Copyright International Business Machines 2020", true},
+		{"// Copyright (c) 2014-2020 IBM Corporation", true},
+		{"// Copyright IBM (Inc 2020", true}, // invalid regex alias is matched literally
+		{"// Copyright IBM Corporations 2020", false},
+		{"// Copyright Oracle 2020", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			if got := config.IsOwnCopyrightLine(tt.line, ".go"); got != tt.expected {
+				t.Errorf("IsOwnCopyrightLine(%q) = %v, want %v", tt.line, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestIsOwnCopyrightLine_NowFormat(t *testing.T) {
+	config := &Config{
+		Copyright: Copyright{
+			Holder: "Acme Corp",
+			Format: `Copyright {{.Holder}}, updated {{.Now.Format "Jan 2, 2006"}}`,
+		},
+		Files: Files{
+			CommentStyles: map[string]string{"go": "//"},
+		},
+	}
+
+	tests := []struct {
+		line     string
+		expected bool
+	}{
+		{"// Copyright Acme Corp, updated Mar 7, 2025", true},
+		{"// Copyright Acme Corp, updated Nov 30, 2019", true},
+		{"// Copyright Acme Corp, updated recently", false},
+		{"// Copyright Oracle, updated Mar 7, 2025", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			if got := config.IsOwnCopyrightLine(tt.line, ".go"); got != tt.expected {
+				t.Errorf("IsOwnCopyrightLine(%q) = %v, want %v", tt.line, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestCommentPrefix_Defaults(t *testing.T) {
+	c := &Config{Files: Files{CommentStyles: map[string]string{"rs": "#"}}}
+
+	tests := map[string]string{
+		".kt":            "//",
+		".swift":         "//",
+		".ts":            "//",
+		".cpp":           "//",
+		".rb":            "#",
+		".yaml":          "#",
+		".html.markdown": "<!--",
+		".rst":           "..",
+		".unknown":       "//",
+		".rs":            "#", // configured styles take precedence
+	}
+	for ext, want := range tests {
+		if got := c.CommentPrefix(ext); got != want {
+			t.Errorf("CommentPrefix(%q) = %q, want %q", ext, got, want)
+		}
+	}
+}
+
+func TestCommentDelimiters_HeadersAgree(t *testing.T) {
+	c := &Config{
+		Copyright: Copyright{Holder: "Acme Corp", Format: "Copyright {{.Holder}}"},
+		License:   License{Enabled: true, Identifier: "MIT", Format: "SPDX-License-Identifier: {{.Identifier}}"},
+	}
+
+	exts := append(slices.Sorted(maps.Keys(defaultCommentStyles)), ".unknown", "")
+	for _, ext := range exts {
+		open, closer := c.commentDelimiters(ext)
+		if open != c.CommentPrefix(ext) {
+			t.Errorf("commentDelimiters(%q) opens with %q, want the comment prefix %q", ext, open, c.CommentPrefix(ext))
+		}
+
+		copyright, err := c.GetCopyrightHeader(ext)
+		if err != nil {
+			t.Fatalf("GetCopyrightHeader(%q) error = %v", ext, err)
+		}
+		license, err := c.GetLicenseHeader(ext)
+		if err != nil {
+			t.Fatalf("GetLicenseHeader(%q) error = %v", ext, err)
+		}
+		for _, header := range []string{copyright, license} {
+			if !strings.HasPrefix(header, open+" ") || !strings.HasSuffix(header, closer) {
+				t.Errorf("header %q for %q is not delimited by %q and %q", header, ext, open, closer)
+			}
+		}
+	}
+}
+
+func TestGetCopyrightHeader_TemplateFuncs(t *testing.T) {
+	tests := []struct {
+		name      string
+		format    string
+		startYear int
+		expected  string
+	}{
+		{"year range", "Copyright {{.Holder}} {{yearRange .StartYear .CurrentYear}}", 2014, "// Copyright Acme Corp 2014-2025"},
+		{"year range of one year", "Copyright {{.Holder}} {{yearRange .StartYear .CurrentYear}}", 2025, "// Copyright Acme Corp 2025"},
+		{"year range without start year", "Copyright {{.Holder}} {{yearRange .StartYear .CurrentYear}}", 0, "// Copyright Acme Corp 2025"},
+		{"upper", "Copyright {{upper .Holder}} {{.CurrentYear}}", 2014, "// Copyright ACME CORP 2025"},
+		{"lower", "Copyright {{lower .Holder}} {{.CurrentYear}}", 2014, "// Copyright acme corp 2025"},
+		{
+			name:      "conditional",
+			format:    "Copyright {{.Holder}} {{if ne .StartYear .CurrentYear}}{{.StartYear}}, {{end}}{{.CurrentYear}}",
+			startYear: 2025,
+			expected:  "// Copyright Acme Corp 2025",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &Config{
+				Copyright: Copyright{
+					Holder:      "Acme Corp",
+					StartYear:   tt.startYear,
+					CurrentYear: 2025,
+					Format:      tt.format,
+				},
+			}
+			got, err := config.GetCopyrightHeader(".go")
+			if err != nil {
+				t.Fatalf("GetCopyrightHeader() error = %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("GetCopyrightHeader() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestIsOwnCopyrightLine_TemplateFuncs(t *testing.T) {
+	tests := []struct {
+		format   string
+		line     string
+		expected bool
+	}{
+		{"Copyright {{.Holder}} {{yearRange .StartYear .CurrentYear}}", "// Copyright Acme Corp 2014-2020", true},
+		{"Copyright {{.Holder}} {{yearRange .StartYear .CurrentYear}}", "// Copyright Acme Corp 2020", true},
+		{"Copyright {{.Holder}} {{yearRange .StartYear .CurrentYear}}", "// Copyright Oracle 2014-2020", false},
+		{"Copyright {{upper .Holder}} {{.CurrentYear}}", "// Copyright ACME CORP 2020", true},
+		{"Copyright {{lower .Holder}} {{.CurrentYear}}", "// Copyright acme corp 2020", true},
+		{"Copyright {{.Holder}} {{if ne .StartYear .CurrentYear}}{{.StartYear}}, {{end}}{{.CurrentYear}}", "// Copyright Acme Corp 2014, 2020", true},
+		{"Copyright {{.Holder}} {{if ne .StartYear .CurrentYear}}{{.StartYear}}, {{end}}{{.CurrentYear}}", "// Copyright Acme Corp 2020", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format+" "+tt.line, func(t *testing.T) {
+			config := &Config{
+				Copyright: Copyright{
+					Holder: "Acme Corp",
+					Format: tt.format,
+				},
+			}
+			if got := config.IsOwnCopyrightLine(tt.line, ".go"); got != tt.expected {
+				t.Errorf("IsOwnCopyrightLine(%q) = %v, want %v", tt.line, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestGetCopyrightHeader_CollapseYears(t *testing.T) {
+	tests := []struct {
+		name      string
+		format    string
+		startYear int
+		collapse  bool
+		expected  string
+		variants  []string
+	}{
+		{
+			name:      "not collapsed by default",
+			format:    "Copyright {{.Holder}} {{.StartYear}}, {{.CurrentYear}}",
+			startYear: 2025,
+			expected:  "// Copyright Acme Corp 2025, 2025",
+		},
+		{
+			name:      "collapsed",
+			format:    "Copyright {{.Holder}} {{.StartYear}}, {{.CurrentYear}}",
+			startYear: 2025,
+			collapse:  true,
+			expected:  "// Copyright Acme Corp 2025",
+			variants:  []string{"// Copyright Acme Corp 2025, 2025"},
+		},
+		{
+			name:      "collapsed hyphen range",
+			format:    "Copyright {{.Holder}} {{.StartYear}}-{{.CurrentYear}}",
+			startYear: 2025,
+			collapse:  true,
+			expected:  "// Copyright Acme Corp 2025",
+			variants:  []string{"// Copyright Acme Corp 2025-2025"},
+		},
+		{
+			name:      "range of several years",
+			format:    "Copyright {{.Holder}} {{.StartYear}}, {{.CurrentYear}}",
+			startYear: 2014,
+			collapse:  true,
+			expected:  "// Copyright Acme Corp 2014, 2025",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &Config{
+				Copyright: Copyright{
+					Holder:        "Acme Corp",
+					StartYear:     tt.startYear,
+					CurrentYear:   2025,
+					Format:        tt.format,
+					CollapseYears: tt.collapse,
+				},
+			}
+			got, err := config.GetCopyrightHeader(".go")
+			if err != nil {
+				t.Fatalf("GetCopyrightHeader() error = %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("GetCopyrightHeader() = %q, want %q", got, tt.expected)
+			}
+
+			variants, err := config.CopyrightHeaderVariants(".go")
+			if err != nil {
+				t.Fatalf("CopyrightHeaderVariants() error = %v", err)
+			}
+			if !slices.Equal(variants, tt.variants) {
+				t.Errorf("CopyrightHeaderVariants() = %q, want %q", variants, tt.variants)
+			}
+
+			// Headers from earlier years are ours in either form
+			for _, line := range []string{"// Copyright Acme Corp 2020", "// Copyright Acme Corp 2014, 2020"} {
+				if !config.IsOwnCopyrightLine(line, ".go") {
+					t.Errorf("IsOwnCopyrightLine(%q) = false, want true", line)
+				}
+			}
+		})
+	}
+}
+
+func TestUpdateCopyrightYear(t *testing.T) {
+	config := &Config{
+		Copyright: Copyright{
+			Holder:      "IBM Corp.",
+			StartYear:   2014,
+			CurrentYear: 2025,
+			Format:      "Copyright {{.Holder}} {{.StartYear}}, {{.CurrentYear}}",
+		},
+		Detection: Detection{
+			ReplacePatterns: []string{`Copyright IBM Corp\. \d{4}, \d{4}\. All rights reserved\.`, `Copyright OldCo`},
+		},
+	}
+
+	tests := []struct {
+		line     string
+		expected string
+		ok       bool
+	}{
+		{"// Copyright IBM Corp. 2014, 2023", "// Copyright IBM Corp. 2014, 2025", true},
+		{"//Copyright  IBM Corp. 2016,2023", "//Copyright  IBM Corp. 2016,2025", true},
+		{"// Copyright IBM Corp. 2014, 2025", "// Copyright IBM Corp. 2014, 2025", true},
+		{"// Copyright IBM Corp. 2014, 2023. All rights reserved.", "// Copyright IBM Corp. 2014, 2025. All rights reserved.", true},
+		{"// Copyright OldCo 2014, 2023", "", false},
+		{"// Copyright Oracle 2014, 2023", "", false},
+		{"// Copyright (c) IBM Corp.", "", false},
+		{"// Copyright IBM Corp. 2014", "// Copyright IBM Corp. 2014, 2025", true},
+		{"// Copyright IBM Corp. 2025", "// Copyright IBM Corp. 2025", true},
+		{"// Copyright IBM Corp. 2020, 2024 All rights reserved.", "// Copyright IBM Corp. 2020, 2025 All rights reserved.", true},
+		{"// Copyright IBM Corp. 2019 - 2024, portions 2010", "// Copyright IBM Corp. 2019 - 2025, portions 2010", true},
+		{"// Portions Copyright IBM Corp. 2014, 2023", "", false},
+		{"Copyright IBM Corp. 2014, 2023", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			got, ok := config.UpdateCopyrightYear(tt.line, ".go")
+			if got != tt.expected || ok != tt.ok {
+				t.Errorf("UpdateCopyrightYear(%q) = %q, %v, want %q, %v", tt.line, got, ok, tt.expected, tt.ok)
+			}
+		})
+	}
+}
+
+func TestFormatsByExt(t *testing.T) {
+	config := &Config{
+		Copyright: Copyright{
+			Holder:      "Acme Corp",
+			StartYear:   2014,
+			CurrentYear: 2025,
+			Format:      "Copyright {{.Holder}} {{.StartYear}}, {{.CurrentYear}}",
+			FormatsByExt: map[string]string{
+				"sh":            "Copyright (c) {{.CurrentYear}} {{.Holder}}. All rights reserved.",
+				"html_markdown": "Copyright {{.Holder}}",
+			},
+		},
+		License: License{
+			Enabled:    true,
+			Identifier: "MPL-2.0",
+			Format:     "SPDX-License-Identifier: {{.Identifier}}",
+			FormatsByExt: map[string]string{
+				"sh": "Licensed under {{.Identifier}}; see LICENSE",
+			},
+		},
+	}
+
+	tests := []struct {
+		ext       string
+		copyright string
+		license   string
+	}{
+		{".go", "// Copyright Acme Corp 2014, 2025", "// SPDX-License-Identifier: MPL-2.0"},
+		{".sh", "# Copyright (c) 2025 Acme Corp. All rights reserved.", "# Licensed under MPL-2.0; see LICENSE"},
+		{".html.markdown", "<!-- Copyright Acme Corp -->", "<!-- SPDX-License-Identifier: MPL-2.0 -->"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ext, func(t *testing.T) {
+			copyright, err := config.GetCopyrightHeader(tt.ext)
+			if err != nil {
+				t.Fatalf("GetCopyrightHeader() error = %v", err)
+			}
+			if copyright != tt.copyright {
+				t.Errorf("GetCopyrightHeader(%q) = %q, want %q", tt.ext, copyright, tt.copyright)
+			}
+
+			license, err := config.GetLicenseHeader(tt.ext)
+			if err != nil {
+				t.Fatalf("GetLicenseHeader() error = %v", err)
+			}
+			if license != tt.license {
+				t.Errorf("GetLicenseHeader(%q) = %q, want %q", tt.ext, license, tt.license)
+			}
+		})
+	}
+
+	// Headers from earlier years in the format of any extension are ours
+	for line, ext := range map[string]string{
+		"# Copyright (c) 2020 Acme Corp. All rights reserved.":  ".sh",
+		"// Copyright (c) 2020 Acme Corp. All rights reserved.": ".go",
+		"# Copyright Acme Corp 2014, 2020":                      ".sh",
+	} {
+		if !config.IsOwnCopyrightLine(line, ext) {
+			t.Errorf("IsOwnCopyrightLine(%q, %q) = false, want true", line, ext)
+		}
+	}
+}
+
+func TestUnspacedComments(t *testing.T) {
+	config := &Config{
+		Copyright: Copyright{
+			Holder:      "Acme Corp",
+			StartYear:   2014,
+			CurrentYear: 2025,
+			Format:      "Copyright {{.Holder}} {{.StartYear}}, {{.CurrentYear}}",
+		},
+		License: License{
+			Enabled:    true,
+			Identifier: "MPL-2.0",
+			Format:     "SPDX-License-Identifier: {{.Identifier}}",
+		},
+		Files: Files{
+			CommentStyles:    map[string]string{"go": "//", "yaml": "#", "md": "<!--"},
+			UnspacedComments: []string{"go", "yaml", "md"},
+		},
+	}
+
+	tests := []struct {
+		ext       string
+		copyright string
+		license   string
+	}{
+		{".go", "//Copyright Acme Corp 2014, 2025", "//SPDX-License-Identifier: MPL-2.0"},
+		{".yaml", "#Copyright Acme Corp 2014, 2025", `#"SPDX-License-Identifier: MPL-2.0"`},
+		{".md", "<!-- Copyright Acme Corp 2014, 2025 -->", "<!-- SPDX-License-Identifier: MPL-2.0 -->"},
+		{".sh", "# Copyright Acme Corp 2014, 2025", "# SPDX-License-Identifier: MPL-2.0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ext, func(t *testing.T) {
+			copyright, err := config.GetCopyrightHeader(tt.ext)
+			if err != nil {
+				t.Fatalf("GetCopyrightHeader() error = %v", err)
+			}
+			if copyright != tt.copyright {
+				t.Errorf("GetCopyrightHeader(%q) = %q, want %q", tt.ext, copyright, tt.copyright)
+			}
+
+			license, err := config.GetLicenseHeader(tt.ext)
+			if err != nil {
+				t.Fatalf("GetLicenseHeader() error = %v", err)
+			}
+			if license != tt.license {
+				t.Errorf("GetLicenseHeader(%q) = %q, want %q", tt.ext, license, tt.license)
+			}
+
+			if !config.HasCommentSpacing(copyright, tt.ext) {
+				t.Errorf("HasCommentSpacing(%q) = false, want true", copyright)
+			}
+		})
+	}
+
+	for line, want := range map[string]bool{
+		"//Copyright Acme Corp 2014, 2025":  true,
+		"// Copyright Acme Corp 2014, 2025": false,
+		"///Copyright Acme Corp 2014, 2025": true,
+		"package main":                      true,
+	} {
+		if got := config.HasCommentSpacing(line, ".go"); got != want {
+			t.Errorf("HasCommentSpacing(%q) = %v, want %v", line, got, want)
+		}
+	}
+	if config.HasCommentSpacing("#Copyright Acme Corp 2014, 2025", ".sh") {
+		t.Error("HasCommentSpacing() = true for an unspaced line of a spaced comment style")
+	}
+}
+
+func TestGetCopyrightHeader_MultiLine(t *testing.T) {
+	config := &Config{
+		Copyright: Copyright{
+			Holder:      "Acme Corp",
+			StartYear:   2014,
+			CurrentYear: 2025,
+			Format:      "Copyright {{.Holder}} {{.StartYear}}, {{.CurrentYear}}\n\n123 Main St: Springfield\n",
+		},
+		Detection: Detection{
+			MaxScanLines: 10,
+		},
+	}
+
+	tests := []struct {
+		ext      string
+		expected string
+	}{
+		{".go", "// Copyright Acme Corp 2014, 2025\n//\n// 123 Main St: Springfield"},
+		{".yml", "# Copyright Acme Corp 2014, 2025\n#\n# \"123 Main St: Springfield\""},
+		{".md", "<!-- Copyright Acme Corp 2014, 2025 -->\n<!--  -->\n<!-- 123 Main St: Springfield -->"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ext, func(t *testing.T) {
+			got, err := config.GetCopyrightHeader(tt.ext)
+			if err != nil {
+				t.Fatalf("GetCopyrightHeader() error = %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("GetCopyrightHeader(%q) = %q, want %q", tt.ext, got, tt.expected)
+			}
+		})
+	}
+
+	if got := config.CopyrightLines(); got != 3 {
+		t.Errorf("CopyrightLines() = %d, want 3", got)
+	}
+	if got := config.ScanLines(); got != 12 {
+		t.Errorf("ScanLines() = %d, want 12", got)
+	}
+
+	// Every line of the block, with any years, is ours; its blank line is not
+	for line, expected := range map[string]bool{
+		"// Copyright Acme Corp 2014, 2020": true,
+		"// 123 Main St: Springfield":       true,
+		"//":                                false,
+		"// 456 Elm St: Springfield":        false,
+	} {
+		if got := config.IsOwnCopyrightLine(line, ".go"); got != expected {
+			t.Errorf("IsOwnCopyrightLine(%q) = %v, want %v", line, got, expected)
+		}
+	}
+}
+
+func BenchmarkConfig_Patterns(b *testing.B) {
+	config := Config{
+		Detection: Detection{
+			SkipGenerated:     true,
+			GeneratedPatterns: []string{"Code generated", "DO NOT EDIT", `@generated\b`},
+			ReplacePatterns:   []string{`Copyright \d{4} OldCompany`, `Copyright \(c\) .* Legacy Inc\.`},
+			MaxScanLines:      20,
+		},
+		ThirdParty: ThirdParty{
+			Patterns: []string{`Copyright.*Oracle`, `Copyright.*Microsoft`, `Copyright.*Google LLC`},
+		},
+		Copyright: Copyright{
+			Holder:        "IBM Corp.",
+			HolderAliases: []string{"International Business Machines", "IBM (Corp|Corporation)"},
+			Format:        `Copyright {{.Holder}} {{yearRange .StartYear .CurrentYear}}`,
+			LegacyFormats: []string{"Copyright (c) {{.CurrentYear}} {{.Holder}}"},
+		},
+	}
+
+	// Header lines of a typical source file, as scanned once per file
+	lines := make([]string, 0, 20)
+	for i := range cap(lines) {
+		lines = append(lines, fmt.Sprintf("var v%d = %d", i, i))
+	}
+
+	b.ReportAllocs()
+	for b.Loop() {
+		if config.IsGenerated(lines) {
+			b.Fatal("IsGenerated() = true, want false")
+		}
+		for _, line := range lines {
+			if config.ShouldReplace(line) || config.IsThirdPartyCopyright(line, ".go") || config.IsOwnCopyrightLine(line, ".go") {
+				b.Fatalf("%q matched, want no match", line)
+			}
+		}
+	}
+}
@@ -0,0 +1,104 @@
+// Copyright IBM Corp. 2014, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package config
+
+import (
+	"bufio"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ReadIgnoreFiles returns the .copyplopignore files that apply to path: those in the
+// directories from the current directory down to path, and those nested anywhere below it.
+// Each line of a file is a pattern; blank lines and lines starting with # are skipped.
+func ReadIgnoreFiles(path string) ([]IgnoreFile, error) {
+	root, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	if info, err := os.Stat(root); err == nil && !info.IsDir() {
+		root = filepath.Dir(root)
+	}
+
+	var ignored []IgnoreFile
+	for _, dir := range ancestorDirs(root) {
+		ignore, err := readIgnoreFile(dir)
+		if err != nil {
+			return nil, err
+		}
+		if ignore != nil {
+			ignored = append(ignored, *ignore)
+		}
+	}
+
+	err = filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() || p == root {
+			return nil
+		}
+		if d.Name() == ".git" {
+			return filepath.SkipDir
+		}
+		ignore, err := readIgnoreFile(p)
+		if ignore != nil {
+			ignored = append(ignored, *ignore)
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ignored, nil
+}
+
+// ancestorDirs returns the directories from the current directory down to dir, or just dir
+// when it is outside the current directory
+func ancestorDirs(dir string) []string {
+	dirs := []string{dir}
+	cwd, err := os.Getwd()
+	if err != nil {
+		return dirs
+	}
+	rel, err := filepath.Rel(cwd, dir)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return dirs
+	}
+	for dir != cwd {
+		dir = filepath.Dir(dir)
+		dirs = append([]string{dir}, dirs...)
+	}
+	return dirs
+}
+
+// readIgnoreFile reads the .copyplopignore file in dir, returning nil when there is none
+func readIgnoreFile(dir string) (*IgnoreFile, error) {
+	f, err := os.Open(filepath.Join(dir, IgnoreFileName))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	ignore := &IgnoreFile{Dir: dir}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		// A leading slash anchors gitignore patterns to the directory; patterns here always are
+		ignore.Patterns = append(ignore.Patterns, strings.TrimSuffix(strings.TrimPrefix(line, "/"), "/"))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return ignore, nil
+}
@@ -0,0 +1,58 @@
+// Copyright IBM Corp. 2014, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestReadIgnoreFiles(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+
+	write := func(name, content string) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(name, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(IgnoreFileName, "# vendored code\n\n/vendor/\n**/*.pb.go\n")
+	write(filepath.Join("web", IgnoreFileName), "dist\n")
+	write(filepath.Join(".git", IgnoreFileName), "**\n")
+
+	ignored, err := ReadIgnoreFiles("web")
+	if err != nil {
+		t.Fatalf("ReadIgnoreFiles() error = %v", err)
+	}
+	if len(ignored) != 2 {
+		t.Fatalf("ReadIgnoreFiles() = %+v, want the root and web ignore files", ignored)
+	}
+
+	c := &Config{Files: Files{Extensions: []string{".js"}, Ignored: ignored}}
+	tests := []struct {
+		file string
+		want bool
+	}{
+		{"vendor/lib/lib.go", false},
+		{"api/service.pb.go", false},
+		{"web/dist/app.js", false},
+		{"web/src/app.js", true},
+		{"dist/app.js", true},
+		{"main.go", true},
+	}
+	for _, tt := range tests {
+		if got := c.shouldProcessPath(tt.file); got != tt.want {
+			t.Errorf("shouldProcessPath(%q) = %v, want %v", tt.file, got, tt.want)
+		}
+	}
+
+	if got, want := c.SkipReason("web/dist/app.js"), `matches "dist" in `+filepath.Join(dir, "web", IgnoreFileName); got != want {
+		t.Errorf("SkipReason() = %q, want %q", got, want)
+	}
+}
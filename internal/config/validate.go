@@ -0,0 +1,150 @@
+// Copyright IBM Corp. 2014, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/bmatcuk/doublestar/v4"
+)
+
+// Validate reports settings that would otherwise be ignored or fail later, such as an
+// unknown third_party.action or a replace pattern that is not a valid regular expression.
+// Each problem names the field as written in the config file.
+func (c *Config) Validate() error {
+	var errs []error
+
+	switch c.ThirdParty.Action {
+	case "", "leave", "above", "below", "replace":
+	default:
+		errs = append(errs, fmt.Errorf("third_party.action: unknown action %q (expected leave, above, below or replace)", c.ThirdParty.Action))
+	}
+
+	switch c.Detection.Minified {
+	case "", "skip", "block":
+	default:
+		errs = append(errs, fmt.Errorf("detection.minified: unknown value %q (expected skip or block)", c.Detection.Minified))
+	}
+
+	for _, pattern := range c.Detection.GeneratedFilePatterns {
+		if !doublestar.ValidatePattern(pattern) {
+			errs = append(errs, fmt.Errorf("detection.generated_file_patterns: invalid glob %q", pattern))
+		}
+	}
+
+	for _, format := range c.Files.PlacementExceptions.FrontmatterFormats {
+		switch format {
+		case "yaml", "toml", "json":
+		default:
+			errs = append(errs, fmt.Errorf("files.placement_exceptions.frontmatter_formats: unknown format %q (expected yaml, toml or json)", format))
+		}
+	}
+
+	if n := c.Files.BlankLinesAfterHeader; n != nil && *n < 0 {
+		errs = append(errs, fmt.Errorf("files.blank_lines_after_header: must not be negative, got %d", *n))
+	}
+	for _, ext := range slices.Sorted(maps.Keys(c.Files.BlankLinesAfterHeaderByExt)) {
+		if n := c.Files.BlankLinesAfterHeaderByExt[ext]; n < 0 {
+			errs = append(errs, fmt.Errorf("files.blank_lines_after_header_by_ext.%s: must not be negative, got %d", ext, n))
+		}
+	}
+
+	if c.Detection.MaxScanLines < 0 {
+		errs = append(errs, fmt.Errorf("detection.max_scan_lines: must not be negative, got %d", c.Detection.MaxScanLines))
+	}
+
+	for field, patterns := range map[string][]string{
+		"detection.generated_patterns":    c.Detection.GeneratedPatterns,
+		"detection.replace_patterns":      c.Detection.ReplacePatterns,
+		"detection.skip_content_patterns": c.Detection.SkipContentPatterns,
+		"third_party.patterns":            c.ThirdParty.Patterns,
+	} {
+		for _, pattern := range patterns {
+			if _, err := regexp.Compile(pattern); err != nil {
+				errs = append(errs, fmt.Errorf("%s: invalid regular expression %q: %w", field, pattern, err))
+			}
+		}
+	}
+
+	formats := map[string]string{
+		"copyright.format": c.Copyright.Format,
+		"license.format":   c.License.Format,
+		"license.notice":   c.License.Notice,
+	}
+	for i, format := range c.Copyright.LegacyFormats {
+		formats[fmt.Sprintf("copyright.legacy_formats[%d]", i)] = format
+	}
+	for ext, format := range c.Copyright.FormatsByExt {
+		formats["copyright.formats_by_ext."+ext] = format
+	}
+	for ext, format := range c.License.FormatsByExt {
+		formats["license.formats_by_ext."+ext] = format
+	}
+	for field, format := range formats {
+		if _, err := parseFormat(field, format); err != nil {
+			errs = append(errs, fmt.Errorf("%s: invalid template: %w", field, err))
+		}
+	}
+
+	// Map iteration order varies; report problems in a stable order
+	slices.SortFunc(errs, func(a, b error) int { return strings.Compare(a.Error(), b.Error()) })
+	return errors.Join(errs...)
+}
+
+// UnknownKeys returns the keys, as dotted paths such as "copyright.holdr", that do not name
+// a config field, e.g. because of a typo. Keys below map fields such as
+// files.comment_styles are free-form and always known.
+func UnknownKeys(keys []string) []string {
+	var unknown []string
+	for _, key := range keys {
+		if !isKnownKey(reflect.TypeFor[Config](), strings.Split(key, ".")) {
+			unknown = append(unknown, key)
+		}
+	}
+	slices.Sort(unknown)
+	return unknown
+}
+
+// isKnownKey reports whether path names a field of t or lies below a map field
+func isKnownKey(t reflect.Type, path []string) bool {
+	switch t.Kind() {
+	case reflect.Map:
+		return true
+	case reflect.Struct:
+	default:
+		// Scalars and lists have no fields of their own
+		return len(path) == 0
+	}
+	if len(path) == 0 {
+		return true
+	}
+
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if field.Tag.Get("mapstructure") == "-" {
+			continue
+		}
+		if strings.EqualFold(configKey(field), path[0]) {
+			return isKnownKey(field.Type, path[1:])
+		}
+	}
+	return false
+}
+
+// configKey is the key field is configured with
+func configKey(field reflect.StructField) string {
+	if name, _, _ := strings.Cut(field.Tag.Get("mapstructure"), ","); name != "" {
+		return name
+	}
+	if name, _, _ := strings.Cut(field.Tag.Get("yaml"), ","); name != "" {
+		return name
+	}
+	return field.Name
+}
@@ -0,0 +1,130 @@
+// Copyright IBM Corp. 2014, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package config
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name   string
+		config Config
+		want   []string // fields named in the error; none for a valid config
+	}{
+		{
+			name: "valid",
+			config: Config{
+				Copyright:  Copyright{Format: "Copyright {{.Holder}} {{.StartYear}}, {{.CurrentYear}}", LegacyFormats: []string{"Copyright {{.Holder}}"}},
+				License:    License{Format: "SPDX-License-Identifier: {{.Identifier}}"},
+				Detection:  Detection{GeneratedPatterns: []string{"Code generated", "DO NOT EDIT"}, ReplacePatterns: []string{`Copyright \d{4} Old`}, MaxScanLines: 20, Minified: "skip"},
+				ThirdParty: ThirdParty{Action: "above", Patterns: []string{"Apache"}},
+			},
+		},
+		{
+			name: "template functions",
+			config: Config{
+				Copyright: Copyright{Format: "Copyright {{upper .Holder}} {{yearRange .StartYear .CurrentYear}}"},
+				License:   License{Format: "SPDX-License-Identifier: {{lower .Identifier}}"},
+			},
+		},
+		{
+			name: "empty",
+		},
+		{
+			name:   "unknown third-party action",
+			config: Config{ThirdParty: ThirdParty{Action: "abov"}},
+			want:   []string{"third_party.action"},
+		},
+		{
+			name:   "unknown minified handling",
+			config: Config{Detection: Detection{Minified: "strip"}},
+			want:   []string{"detection.minified"},
+		},
+		{
+			name:   "negative blank lines after header",
+			config: Config{Files: Files{BlankLinesAfterHeaderByExt: map[string]int{"go": 1, "sh": -1}}},
+			want:   []string{"files.blank_lines_after_header_by_ext.sh"},
+		},
+		{
+			name:   "invalid generated file pattern",
+			config: Config{Detection: Detection{GeneratedFilePatterns: []string{"*.pb.go", "[a-"}}},
+			want:   []string{"detection.generated_file_patterns"},
+		},
+		{
+			name:   "unknown frontmatter format",
+			config: Config{Files: Files{PlacementExceptions: PlacementExceptions{FrontmatterFormats: []string{"toml", "xml"}}}},
+			want:   []string{"files.placement_exceptions.frontmatter_formats"},
+		},
+		{
+			name:   "negative max scan lines",
+			config: Config{Detection: Detection{MaxScanLines: -1}},
+			want:   []string{"detection.max_scan_lines"},
+		},
+		{
+			name: "invalid patterns",
+			config: Config{
+				Detection:  Detection{GeneratedPatterns: []string{"("}, ReplacePatterns: []string{"ok", "[a-"}, SkipContentPatterns: []string{"+"}},
+				ThirdParty: ThirdParty{Patterns: []string{"*"}},
+			},
+			want: []string{"detection.generated_patterns", "detection.replace_patterns", "detection.skip_content_patterns", "third_party.patterns"},
+		},
+		{
+			name: "invalid templates",
+			config: Config{
+				Copyright: Copyright{Format: "Copyright {{.Holder}", LegacyFormats: []string{"ok", "{{if}}"}, FormatsByExt: map[string]string{"sh": "{{.Holder"}},
+				License:   License{Format: "{{.Identifier", Notice: "{{end}}", FormatsByExt: map[string]string{"py": "{{"}},
+			},
+			want: []string{"copyright.format", "copyright.formats_by_ext.sh", "copyright.legacy_formats[1]", "license.format", "license.formats_by_ext.py", "license.notice"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.Validate()
+			if len(tt.want) == 0 {
+				if err != nil {
+					t.Errorf("Validate() error = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("Validate() error = nil, want errors for %v", tt.want)
+			}
+			lines := strings.Split(err.Error(), "\n")
+			if len(lines) != len(tt.want) {
+				t.Errorf("Validate() reported %d problems, want %d:\n%v", len(lines), len(tt.want), err)
+			}
+			for _, field := range tt.want {
+				if !slices.ContainsFunc(lines, func(line string) bool { return strings.HasPrefix(line, field+": ") }) {
+					t.Errorf("Validate() error does not name %s:\n%v", field, err)
+				}
+			}
+		})
+	}
+}
+
+func TestUnknownKeys(t *testing.T) {
+	keys := []string{
+		"copyright.holder",
+		"copyright.holdr", // typo
+		"license.enabled",
+		"files.comment_styles.go", // map keys are free-form
+		"files.block_comment_styles.css.open",
+		"files.placement_exceptions.leading_lines",
+		"detection.max_scan_lines",
+		"detection.max_scan_lines.extra", // below a scalar
+		"third_party.action",
+		"thirdparty.action", // not the configured name
+		"extra",
+		"copyright.now", // not configurable
+	}
+
+	want := []string{"copyright.holdr", "copyright.now", "detection.max_scan_lines.extra", "extra", "thirdparty.action"}
+	if got := UnknownKeys(keys); !slices.Equal(got, want) {
+		t.Errorf("UnknownKeys() = %v, want %v", got, want)
+	}
+}
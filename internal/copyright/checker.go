@@ -4,132 +4,437 @@
 package copyright
 
 import (
+	"context"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/YakDriver/copyplop/internal/config"
-	"github.com/schollz/progressbar/v3"
 )
 
 type Checker struct {
 	config *config.Config
+
+	// Modified restricts processing to files modified or untracked in the git working tree
+	Modified bool
+
+	// FailFast stops Check at the first file with an issue
+	FailFast bool
+
+	// OnlyMissing reports only files without any copyright header, ignoring headers that are
+	// present but outdated or malformed
+	OnlyMissing bool
+
+	// Jobs is the number of files checked concurrently (0 = one per CPU); with FailFast files
+	// are checked one at a time
+	Jobs int
+	// Quiet suppresses the progress bar
+	Quiet bool
+
+	// Suggest adds the header each file should have to its issue, to copy into the file
+	Suggest bool
+
+	summary CheckSummary // counts of the last check
+}
+
+// CheckSummary counts the files a check scanned, those without a reported issue, and the
+// issues per file extension and kind
+type CheckSummary struct {
+	Files      int
+	Passed     int
+	Extensions []ExtensionSummary // sorted by extension
+}
+
+// ExtensionSummary counts the scanned files with one extension and their issues per kind
+type ExtensionSummary struct {
+	Extension string
+	Files     int
+	Issues    map[string]int
+}
+
+// Summary returns the counts of the last Check, CheckFiles or CheckRevision
+func (c *Checker) Summary() CheckSummary {
+	return c.summary
+}
+
+// summarize records the counts of a check that scanned files and reported issues
+func (c *Checker) summarize(files []string, issues []Issue) {
+	byExt := map[string]*ExtensionSummary{}
+	extension := func(file string) *ExtensionSummary {
+		ext := filepath.Ext(file)
+		stats := byExt[ext]
+		if stats == nil {
+			stats = &ExtensionSummary{Extension: ext, Issues: map[string]int{}}
+			byExt[ext] = stats
+		}
+		return stats
+	}
+	for _, file := range files {
+		extension(file).Files++
+	}
+	for _, issue := range issues {
+		extension(issue.File).Issues[issue.Kind]++
+	}
+
+	c.summary = CheckSummary{Files: len(files), Passed: len(files) - len(issues)}
+	for _, stats := range byExt {
+		c.summary.Extensions = append(c.summary.Extensions, *stats)
+	}
+	slices.SortFunc(c.summary.Extensions, func(a, b ExtensionSummary) int { return strings.Compare(a.Extension, b.Extension) })
 }
 
 func NewChecker(cfg *config.Config) *Checker {
 	return &Checker{config: cfg}
 }
 
-func (c *Checker) Check(path string) ([]Issue, error) {
-	files, err := getTrackedFiles(path, c.config)
+// Check reports header issues in the files under path. It stops between files when ctx is
+// cancelled, returning the issues found so far along with the context's error.
+func (c *Checker) Check(ctx context.Context, path string) ([]Issue, error) {
+	filesToProcess, err := getFilesToProcess(path, c.config, c.Modified)
 	if err != nil {
 		return nil, err
 	}
+	return c.checkFiles(ctx, filesToProcess)
+}
 
-	// Filter files to process
-	var filesToProcess []string
-	for _, file := range files {
-		if c.config.ShouldProcess(file) {
-			filesToProcess = append(filesToProcess, file)
-		}
-	}
+// CheckFiles checks the given files, e.g. those a pre-commit hook passes, skipping any that
+// the config does not select for processing
+func (c *Checker) CheckFiles(ctx context.Context, files []string) ([]Issue, error) {
+	return c.checkFiles(ctx, filterFiles(files, c.config))
+}
 
+func (c *Checker) checkFiles(ctx context.Context, filesToProcess []string) ([]Issue, error) {
 	if len(filesToProcess) == 0 {
+		c.summarize(nil, nil)
 		return nil, nil
 	}
 
-	bar := progressbar.Default(int64(len(filesToProcess)), "Checking files")
-	var issues []Issue
+	bar := newProgressBar(len(filesToProcess), "Checking files", c.Quiet)
+	// Fail-fast stops at the first file with an issue, so files are then checked one at a time
+	jobs := c.Jobs
+	if c.FailFast {
+		jobs = 1
+	}
+
+	found := make([]*Issue, len(filesToProcess))
+	checked := make([]bool, len(filesToProcess))
+	err := forEachFile(ctx, filesToProcess, jobs, bar, func(i int, file string) bool {
+		checked[i] = true
+		if issue := c.checkFile(file); c.reports(issue) {
+			found[i] = issue
+			return !c.FailFast
+		}
+		return true
+	})
 
-	for _, file := range filesToProcess {
-		if issue := c.checkFile(file); issue != nil {
+	// Issues are reported in file order whichever worker found them
+	var issues []Issue
+	var scanned []string
+	for i, issue := range found {
+		if checked[i] {
+			scanned = append(scanned, filesToProcess[i])
+		}
+		if issue != nil {
 			issues = append(issues, *issue)
 		}
-		_ = bar.Add(1)
 	}
 
-	return issues, nil
+	c.summarize(scanned, issues)
+	return issues, err
+}
+
+// reports tells whether issue is one Check should report
+func (c *Checker) reports(issue *Issue) bool {
+	return issue != nil && (!c.OnlyMissing || issue.Kind == KindMissing)
 }
 
 func (c *Checker) checkFile(file string) *Issue {
 	content, err := os.ReadFile(file)
 	if err != nil {
-		return &Issue{File: file, Problem: "could not read file"}
+		return &Issue{File: file, Kind: KindError, Problem: "could not read file"}
+	}
+	return c.checkContent(file, content)
+}
+
+// checkContent is checkFile for content already read, e.g. from a git revision
+func (c *Checker) checkContent(file string, content []byte) *Issue {
+	// Resolve the extension as fix does, so that smart extensions are checked as their content type
+	ext, isSmartExt, ok := resolveExtension(c.config, file, content)
+	if !ok {
+		return nil
 	}
+	return c.checkResolved(file, content, ext, isSmartExt)
+}
 
-	lines := strings.Split(string(content), "\n")
+// headerSetup is what checking a file's header starts from: its lines, the config that applies
+// to it and the header rendered for it
+type headerSetup struct {
+	cfg          *config.Config
+	ext          string
+	lines        []string
+	copyright    string
+	variants     []string // copyright headers as current as copyright
+	license      string
+	notice       string
+	bannerBefore string
+	bannerAfter  string
+	startLine    int // index of the line the header starts at, after any placement exceptions
+}
+
+// block returns the header block the file should have
+func (h *headerSetup) block() []string {
+	return headerBlock(h.cfg, h.ext, h.bannerBefore, h.copyright, h.license, h.notice, h.bannerAfter)
+}
+
+// setupHeader prepares content, whose extension has been resolved to ext, for checking its
+// header. It returns a nil setup when the file is left alone, along with the issue to report
+// for it, if any: an empty file or a config error.
+func (c *Checker) setupHeader(file string, content []byte, ext string, isSmartExt bool) (*headerSetup, *Issue) {
+	content, _ = cutBOM(content)
+	lines, _ := splitLines(string(content))
 	if len(lines) == 0 {
-		return &Issue{File: file, Problem: "empty file"}
+		return nil, &Issue{File: file, Kind: KindMissing, Problem: "empty file"}
 	}
 
-	if c.config.IsGenerated(lines) {
-		return nil
+	if c.config.IsSkippedContent(lines) {
+		return nil, nil
 	}
 
-	ext := filepath.Ext(file)
-	expectedHeader, err := c.config.GetCopyrightHeader(ext)
-	if err != nil {
-		return &Issue{File: file, Problem: "config error: " + err.Error()}
+	// Shebang scripts without a configured comment style use the interpreter's style
+	cfg := c.config
+	if hasShebang(lines) {
+		cfg = cfg.ForShebang(lines[0], ext)
 	}
 
-	expectedLicense, err := c.config.GetLicenseHeader(ext)
-	if err != nil {
-		return &Issue{File: file, Problem: "config error: " + err.Error()}
+	cfg, skip := forMinified(cfg, ext, lines)
+	if skip {
+		return nil, nil
 	}
+	cfg = withGitStartYear(cfg, file)
 
-	startLine := 0
-	if hasShebang(lines) {
-		startLine = 1
+	h := &headerSetup{cfg: cfg, ext: ext, lines: lines}
+	var err error
+	if h.copyright, err = cfg.GetCopyrightHeader(ext); err != nil {
+		return nil, &Issue{File: file, Kind: KindError, Problem: "config error: " + err.Error()}
+	}
+	if h.variants, err = cfg.CopyrightHeaderVariants(ext); err != nil {
+		return nil, &Issue{File: file, Kind: KindError, Problem: "config error: " + err.Error()}
 	}
+	if h.license, err = cfg.GetLicenseHeader(ext); err != nil {
+		return nil, &Issue{File: file, Kind: KindError, Problem: "config error: " + err.Error()}
+	}
+	if h.notice, err = cfg.GetNoticeHeader(ext); err != nil {
+		return nil, &Issue{File: file, Kind: KindError, Problem: "config error: " + err.Error()}
+	}
+	h.bannerBefore, h.bannerAfter = cfg.GetBannerLines(ext)
 
-	// Handle XML declaration
-	if startLine < len(lines) && c.config.Files.PlacementExceptions.XMLDeclaration && hasXMLDeclaration(lines[startLine:]) {
-		startLine++
+	headerFile := file
+	if isSmartExt {
+		headerFile = "dummy" + ext
 	}
+	h.startLine = headerStartLine(lines, cfg, headerFile)
+	return h, nil
+}
 
-	frontmatterEnd := getFrontmatterEndNew(lines, c.config, file)
-	if frontmatterEnd > startLine {
-		startLine = frontmatterEnd
+// checkResolved is checkContent for a file whose extension has been resolved to ext
+func (c *Checker) checkResolved(file string, content []byte, ext string, isSmartExt bool) (issue *Issue) {
+	if isNotebook(file) {
+		return c.checkNotebook(file, content)
 	}
 
-	// Handle markdown heading - only for markdown files
-	isMarkdown := strings.HasSuffix(file, ".md") || strings.HasSuffix(file, ".markdown")
-	if startLine < len(lines) && isMarkdown && c.config.Files.PlacementExceptions.MarkdownHeading && hasMarkdownHeading(lines[startLine:]) {
-		startLine++
+	setup, issue := c.setupHeader(file, content, ext, isSmartExt)
+	if setup == nil {
+		return issue
+	}
+	cfg, lines, startLine := setup.cfg, setup.lines, setup.startLine
+	expectedHeader, expectedLicense, expectedNotice := setup.copyright, setup.license, setup.notice
+	bannerBefore, bannerAfter := setup.bannerBefore, setup.bannerAfter
+
+	if c.Suggest {
+		defer func() {
+			if issue != nil && issue.Kind != KindError {
+				issue.Suggestion = strings.Join(setup.block(), "\n")
+			}
+		}()
 	}
 
 	if startLine >= len(lines) {
-		return &Issue{File: file, Problem: "missing copyright header"}
+		return &Issue{File: file, Kind: KindMissing, Problem: "missing copyright header"}
 	}
 
-	// Determine scan limit
-	maxScan := len(lines)
-	if c.config.Detection.MaxScanLines > 0 {
-		maxScan = min(startLine+c.config.Detection.MaxScanLines, len(lines))
+	if _, changed := extractDocstringHeader(cfg, ext, lines, startLine); changed {
+		return &Issue{File: file, Kind: KindIncorrect, Problem: "copyright header is inside the module docstring"}
 	}
 
+	// Determine scan limit
+	maxScan := headerScanEnd(cfg, ext, lines, startLine)
+
 	// Check if copyright and license exist in header area
 	foundCopyright := false
 	foundLicense := false
+	foundLicenseVariant := false // an SPDX line differing from ours in casing or spacing
+	foundNotice := false
+	bannerCount := 0
+	copyrightLine := -1
+	copyrightYearOnly := false // the copyright line is current by update_year_only
+	licenseLine := -1
+	lastHeaderLine := -1
+	// Match the copyright text itself, whatever the length of the comment prefix. A multi-line
+	// copyright header is found where its first line is followed by all the others.
+	copyrightLines := strings.Split(expectedHeader, "\n")
+	expectedText := normalizeWhitespace(strings.TrimPrefix(copyrightLines[0], cfg.CommentPrefix(ext)))
+	// Copyright lines as current as the expected one, e.g. "2025, 2025" with collapse_years
+	currentLines := []string{copyrightLines[0]}
+	for _, variant := range setup.variants {
+		currentLines = append(currentLines, strings.Split(variant, "\n")[0])
+	}
+	// The copyright line is at the top with only the header's block comment opener or banner above
+	// it, below any third-party notices that fix keeps above the header
+	topLine := thirdPartyEnd(cfg, ext, lines, startLine, maxScan)
+	if style, ok := cfg.BlockComment(ext); ok && topLine < maxScan && strings.TrimSpace(lines[topLine]) == strings.TrimSpace(style.Open) {
+		topLine++
+	}
+	if bannerBefore != "" && topLine < maxScan && isSameHeaderLine(lines[topLine], bannerBefore) {
+		topLine++
+	}
 	for i := startLine; i < maxScan; i++ {
-		if strings.Contains(lines[i], strings.TrimSpace(expectedHeader[2:])) {
+		if isKeptLine(lines, i) {
+			continue
+		}
+		if cfg.IsWrongSyntaxHeaderLine(lines[i], ext) {
+			return &Issue{File: file, Kind: KindIncorrect, Problem: "copyright header uses wrong comment syntax"}
+		}
+
+		line := normalizeWhitespace(lines[i])
+		// With update_year_only a copyright line naming the holder is current once its last year is
+		updated, ok := updateYearOnly(cfg, ext, lines[i], len(copyrightLines) > 1)
+		yearCurrent := ok && updated == lines[i]
+		if yearCurrent || strings.Contains(line, expectedText) && (len(copyrightLines) == 1 || hasHeaderLines(lines[i+1:maxScan], copyrightLines[1:])) {
 			foundCopyright = true
-			if c.config.Detection.RequireAtTop && i != startLine {
-				return &Issue{File: file, Problem: "copyright not at top of file"}
+			if copyrightLine < 0 {
+				copyrightLine = i
+				copyrightYearOnly = yearCurrent
+			}
+			if cfg.Copyright.StripTrailingText && !yearCurrent && !isAnyHeaderLine(lines[i], currentLines) && cfg.IsOwnCopyrightLine(lines[i], ext) {
+				return &Issue{File: file, Kind: KindIncorrect, Problem: "copyright line has trailing text"}
 			}
+			if cfg.Detection.RequireAtTop && i != topLine {
+				return &Issue{File: file, Kind: KindIncorrect, Problem: "copyright not at top of file"}
+			}
+			lastHeaderLine = i + len(copyrightLines) - 1
 		}
-		if expectedLicense != "" && strings.Contains(lines[i], strings.TrimSpace(expectedLicense[2:])) {
+		if expectedLicense != "" && isSameLicenseLine(lines[i], expectedLicense) {
 			foundLicense = true
+			if licenseLine < 0 {
+				licenseLine = i
+			}
+			lastHeaderLine = i
+		} else if expectedLicense != "" && isSPDXHeaderLine(cfg, ext, lines[i]) &&
+			strings.EqualFold(spdxLicenseID(lines[i]), cfg.License.Identifier) {
+			foundLicenseVariant = true
+		}
+		if expectedNotice != "" && line == normalizeWhitespace(expectedNotice) {
+			foundNotice = true
+			lastHeaderLine = i
+		}
+		if (bannerBefore != "" && line == normalizeWhitespace(bannerBefore)) ||
+			(bannerAfter != "" && line == normalizeWhitespace(bannerAfter)) {
+			bannerCount++
+			lastHeaderLine = i
 		}
 	}
 
 	if !foundCopyright {
-		return &Issue{File: file, Problem: "missing or incorrect copyright header"}
+		return &Issue{File: file, Kind: missingCopyrightKind(cfg, ext, lines[startLine:maxScan]), Problem: "missing or incorrect copyright header"}
+	}
+
+	if expectedLicense != "" && !foundLicense && foundLicenseVariant {
+		return &Issue{File: file, Kind: KindIncorrect, Problem: "license line is not in canonical form"}
 	}
 
 	if expectedLicense != "" && !foundLicense {
-		return &Issue{File: file, Problem: "missing license header"}
+		return &Issue{File: file, Kind: KindLicenseMissing, Problem: "missing license header"}
+	}
+
+	if foundLicense && licenseLine < copyrightLine {
+		return &Issue{File: file, Kind: KindIncorrect, Problem: "license line comes before copyright line"}
+	}
+
+	// update_year_only leaves the rest of the copyright line as written, spacing included
+	if (!copyrightYearOnly && !cfg.HasCommentSpacing(lines[copyrightLine], ext)) ||
+		(foundLicense && !cfg.HasCommentSpacing(lines[licenseLine], ext)) {
+		return &Issue{File: file, Kind: KindIncorrect, Problem: "header comment spacing does not match the comment style"}
+	}
+
+	if cfg.Files.BlankAfterShebang && hasShebang(lines) && len(lines) > 1 && strings.TrimSpace(lines[1]) != "" {
+		return &Issue{File: file, Kind: KindIncorrect, Problem: "missing blank line after shebang"}
+	}
+
+	if expectedNotice != "" && !foundNotice {
+		return &Issue{File: file, Kind: KindIncorrect, Problem: "missing notice line"}
+	}
+
+	if bannerCount < countNonEmpty(bannerBefore, bannerAfter) {
+		return &Issue{File: file, Kind: KindIncorrect, Problem: "missing header banner"}
+	}
+
+	if ext == ".go" && runsIntoPackageClause(lines[lastHeaderLine+1:]) {
+		return &Issue{File: file, Kind: KindIncorrect, Problem: "copyright header is part of the package doc comment"}
+	}
+
+	if blankLines, exact := cfg.HeaderBlankLines(ext); exact && hasWrongBlankRun(cfg, ext, lines, lastHeaderLine+1, blankLines) {
+		return &Issue{File: file, Kind: KindIncorrect, Problem: "wrong number of blank lines after copyright header"}
+	} else if !exact && cfg.Detection.CollapseBlankLines && hasExtraBlankLines(lines, lastHeaderLine+1) {
+		return &Issue{File: file, Kind: KindIncorrect, Problem: "multiple blank lines after copyright header"}
 	}
 
 	return nil
 }
+
+// thirdPartyEnd returns the index past the third-party notices starting at index start, and the
+// blank lines around them, that fix keeps above the header: with any third_party.action but
+// replace, which removes them. It returns start when no notice starts there. As in fix, header
+// lines of ours are not notices, whatever third-party patterns they match.
+func thirdPartyEnd(cfg *config.Config, ext string, lines []string, start, maxScan int) int {
+	if cfg.ThirdParty.Action == "replace" {
+		return start
+	}
+	isNotice := func(line string) bool {
+		_, yearOnly := updateYearOnly(cfg, ext, line, false)
+		return cfg.IsThirdPartyCopyright(line, ext) && !yearOnly && !cfg.IsOwnCopyrightLine(line, ext) && !isSPDXHeaderLine(cfg, ext, line)
+	}
+	end := start
+	for i := start; i < maxScan; i++ {
+		if isNotice(lines[i]) {
+			end = i + 1
+		} else if strings.TrimSpace(lines[i]) != "" {
+			break
+		}
+	}
+	if end == start {
+		return start
+	}
+	for end < maxScan && strings.TrimSpace(lines[end]) == "" {
+		end++
+	}
+	return end
+}
+
+// missingCopyrightKind tells apart why the expected copyright was not found in the header
+// area: an outdated copyright of ours, only a third-party copyright, or no copyright at all
+func missingCopyrightKind(cfg *config.Config, ext string, headerArea []string) string {
+	kind := KindMissing
+	for i, line := range headerArea {
+		if isKeptLine(headerArea, i) {
+			continue
+		}
+		if cfg.IsOwnCopyrightLine(line, ext) {
+			return KindIncorrect
+		}
+		if cfg.IsThirdPartyCopyright(line, ext) {
+			kind = KindThirdParty
+		}
+	}
+	return kind
+}
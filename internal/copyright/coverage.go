@@ -0,0 +1,79 @@
+// Copyright IBM Corp. 2014, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package copyright
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// Coverage is how many of the files under a path have correct headers, overall and per
+// resolved extension (compound and smart extensions are counted as what they resolve to)
+type Coverage struct {
+	Files      int                 `json:"files"`
+	Compliant  int                 `json:"compliant"`
+	Extensions []ExtensionCoverage `json:"extensions,omitempty"`
+}
+
+// ExtensionCoverage is the coverage of the files with one resolved extension
+type ExtensionCoverage struct {
+	Extension string `json:"extension"`
+	Files     int    `json:"files"`
+	Compliant int    `json:"compliant"`
+}
+
+// Coverage checks the files under path and counts those without issues. Extensions are
+// sorted by extension. It stops between files when ctx is cancelled.
+func (c *Checker) Coverage(ctx context.Context, path string) (*Coverage, error) {
+	filesToProcess, err := getFilesToProcess(path, c.config, c.Modified)
+	if err != nil {
+		return nil, err
+	}
+
+	coverage := &Coverage{}
+	if len(filesToProcess) == 0 {
+		return coverage, nil
+	}
+
+	bar := newProgressBar(len(filesToProcess), "Checking files", c.Quiet)
+	byExt := map[string]*ExtensionCoverage{}
+
+	for _, file := range filesToProcess {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		ext := filepath.Ext(file)
+		var issue *Issue
+		content, err := os.ReadFile(file)
+		if err != nil {
+			issue = &Issue{File: file, Kind: KindError, Problem: "could not read file"}
+		} else if resolved, isSmartExt, ok := resolveExtension(c.config, file, content); ok {
+			ext = resolved
+			issue = c.checkResolved(file, content, ext, isSmartExt)
+		}
+
+		stats := byExt[ext]
+		if stats == nil {
+			stats = &ExtensionCoverage{Extension: ext}
+			byExt[ext] = stats
+		}
+		stats.Files++
+		coverage.Files++
+		if issue == nil {
+			stats.Compliant++
+			coverage.Compliant++
+		}
+		_ = bar.Add(1)
+	}
+
+	for _, stats := range byExt {
+		coverage.Extensions = append(coverage.Extensions, *stats)
+	}
+	slices.SortFunc(coverage.Extensions, func(a, b ExtensionCoverage) int { return strings.Compare(a.Extension, b.Extension) })
+	return coverage, nil
+}
@@ -0,0 +1,78 @@
+// Copyright IBM Corp. 2014, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package copyright
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/YakDriver/copyplop/internal/config"
+)
+
+func TestChecker_Coverage(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	files := map[string]string{
+		"good.go":      "// Copyright IBM Corp. 2014, 2025\n\npackage main\n",
+		"also_ok.go":   "// Copyright IBM Corp. 2014, 2025\n\npackage main\n",
+		"bad.go":       "package main\n",
+		"good.sh":      "#!/bin/sh\n# Copyright IBM Corp. 2014, 2025\n\necho ok\n",
+		"bad.sh":       "#!/bin/sh\necho bad\n",
+		"page.html.md": "<!-- Copyright IBM Corp. 2014, 2025 -->\n\n# Page\n",
+		// A smart-extension file is counted, and checked, as what it resolves to
+		"install.sh.tmpl": "# Copyright IBM Corp. 2014, 2025\n\necho {{ .Name }}\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cfg := &config.Config{
+		Copyright: config.Copyright{
+			Holder:      "IBM Corp.",
+			StartYear:   2014,
+			CurrentYear: 2025,
+			Format:      "Copyright {{.Holder}} {{.StartYear}}, {{.CurrentYear}}",
+		},
+		Files: config.Files{
+			Extensions:      []string{".go", ".sh", ".html.md"},
+			SmartExtensions: []string{".tmpl"},
+			SmartExtensionOverrides: []config.SmartExtensionOverride{
+				{Pattern: "*.sh.tmpl", Extension: ".sh"},
+			},
+			CommentStyles: map[string]string{"go": "//", "sh": "#", "html_md": "<!--"},
+		},
+		Detection: config.Detection{
+			MaxScanLines: 20,
+		},
+	}
+
+	coverage, err := NewChecker(cfg).Coverage(context.Background(), tmpDir)
+	if err != nil {
+		t.Fatalf("Coverage() error = %v", err)
+	}
+
+	expected := []ExtensionCoverage{
+		{Extension: ".go", Files: 3, Compliant: 2},
+		{Extension: ".html.md", Files: 1, Compliant: 1},
+		{Extension: ".sh", Files: 3, Compliant: 2},
+	}
+	if !slices.Equal(coverage.Extensions, expected) {
+		t.Errorf("Coverage() extensions = %+v, want %+v", coverage.Extensions, expected)
+	}
+
+	// The breakdown adds up to the totals
+	total, compliant := 0, 0
+	for _, ext := range coverage.Extensions {
+		total += ext.Files
+		compliant += ext.Compliant
+	}
+	if coverage.Files != 7 || coverage.Compliant != 5 || total != coverage.Files || compliant != coverage.Compliant {
+		t.Errorf("Coverage() totals = %d/%d, breakdown sums to %d/%d, want 5/7", coverage.Compliant, coverage.Files, compliant, total)
+	}
+}
@@ -0,0 +1,222 @@
+// Copyright IBM Corp. 2014, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package copyright
+
+import (
+	"context"
+	"os"
+	"strings"
+
+	"github.com/YakDriver/copyplop/internal/config"
+)
+
+// Dedupe removes duplicate canonical header lines and conflicting old headers from the
+// header area of each file. Files that already have a single correct header are untouched.
+// Files that could not be read or written are returned as errors, in processing order. It
+// stops between files when ctx is cancelled, returning the results so far along with the
+// context's error.
+func (f *Fixer) Dedupe(ctx context.Context, path string) ([]DedupeResult, []FileError, error) {
+	filesToProcess, err := getFilesToProcess(path, f.config, f.Modified)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(filesToProcess) == 0 {
+		return nil, nil, nil
+	}
+
+	bar := newProgressBar(len(filesToProcess), "Deduplicating files", f.Quiet)
+	removed := make([]int, len(filesToProcess))
+	fileErrs := make([]error, len(filesToProcess))
+	ctxErr := forEachFile(ctx, filesToProcess, f.Jobs, bar, func(i int, file string) bool {
+		removed[i], fileErrs[i] = f.dedupeFile(file)
+		return true
+	})
+
+	// Results are assembled in file order whichever worker deduplicated each file
+	var results []DedupeResult
+	var errs []FileError
+	for i, file := range filesToProcess {
+		if fileErrs[i] != nil {
+			errs = append(errs, FileError{File: file, Err: fileErrs[i]})
+		} else if removed[i] > 0 {
+			results = append(results, DedupeResult{File: file, Removed: removed[i]})
+		}
+	}
+
+	return results, errs, ctxErr
+}
+
+// dedupeFile removes repeated canonical header lines, and old copyright/SPDX lines that
+// conflict with a canonical one, returning the number of lines removed
+func (f *Fixer) dedupeFile(file string) (int, error) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return 0, err
+	}
+
+	content, bom := cutBOM(content)
+	lines, eol := splitLines(string(content))
+	if len(lines) == 0 || f.config.IsSkippedContent(lines) {
+		return 0, nil
+	}
+
+	ext, isSmartExt, ok := resolveExtension(f.config, file, content)
+	if !ok {
+		return 0, nil
+	}
+
+	cfg := f.config
+	if hasShebang(lines) {
+		cfg = cfg.ForShebang(lines[0], ext)
+	}
+
+	cfg, skip := forMinified(cfg, ext, lines)
+	if skip {
+		return 0, nil
+	}
+
+	copyrightHeader, err := cfg.GetCopyrightHeader(ext)
+	if err != nil {
+		return 0, err
+	}
+
+	licenseHeader, err := cfg.GetLicenseHeader(ext)
+	if err != nil {
+		return 0, err
+	}
+
+	noticeHeader, err := cfg.GetNoticeHeader(ext)
+	if err != nil {
+		return 0, err
+	}
+
+	headerFile := file
+	if isSmartExt {
+		headerFile = "dummy" + ext
+	}
+	startLine := headerStartLine(lines, cfg, headerFile)
+
+	maxScan := headerScanEnd(cfg, ext, lines, startLine)
+
+	// Canonical headers are matched as sequences of lines, so that a multi-line copyright is
+	// found, and removed, as a whole
+	canonical := [][]string{strings.Split(copyrightHeader, "\n")}
+	if licenseHeader != "" {
+		canonical = append(canonical, []string{licenseHeader})
+	}
+	if noticeHeader != "" {
+		canonical = append(canonical, []string{noticeHeader})
+	}
+	fillerLines := copyrightFillerLines(cfg, ext, canonical[0])
+
+	// canonicalAt returns which canonical header starts at lines[i], or -1
+	canonicalAt := func(i int) int {
+		for idx, header := range canonical {
+			if hasHeaderLines(lines[i:maxScan], header) {
+				return idx
+			}
+		}
+		return -1
+	}
+
+	// Old headers are only conflicting when the canonical line they compete with is present
+	present := make([]bool, len(canonical))
+	for i := startLine; i < maxScan; i++ {
+		if idx := canonicalAt(i); idx >= 0 {
+			present[idx] = true
+			i += len(canonical[idx]) - 1
+		}
+	}
+
+	isConflicting := func(i int) bool {
+		line := lines[i]
+		if present[0] && (cfg.ShouldReplace(line) || cfg.IsOwnCopyrightLine(line, ext) || cfg.IsWrongSyntaxHeaderLine(line, ext) ||
+			isOwnBlockFiller(cfg, ext, lines, i, fillerLines)) {
+			return true
+		}
+		return licenseHeader != "" && present[1] && isSPDXHeaderLine(cfg, ext, line)
+	}
+
+	result := append([]string{}, lines[:startLine]...)
+	seen := make([]bool, len(canonical))
+	removed := 0
+	skipBlank := false
+	for i := startLine; i < len(lines); i++ {
+		line := lines[i]
+
+		if i < maxScan && !isKeptLine(lines, i) {
+			remove := false
+			span := 1
+			if idx := canonicalAt(i); idx >= 0 {
+				remove = seen[idx]
+				seen[idx] = true
+				span = len(canonical[idx])
+			} else {
+				remove = isConflicting(i)
+			}
+
+			if remove {
+				removed += span
+				i += span - 1
+				// Drop a following blank line if it would leave a doubled or leading blank
+				skipBlank = len(result) == startLine || strings.TrimSpace(result[len(result)-1]) == ""
+				continue
+			}
+
+			if span > 1 {
+				result = append(result, lines[i:i+span]...)
+				i += span - 1
+				skipBlank = false
+				continue
+			}
+
+			if skipBlank && strings.TrimSpace(line) == "" {
+				skipBlank = false
+				continue
+			}
+		}
+
+		skipBlank = false
+		result = append(result, line)
+	}
+
+	if removed == 0 {
+		return 0, nil
+	}
+
+	result = removeEmptyCommentBlocks(cfg, ext, result, startLine, maxScan-removed)
+	output := strings.Join(result, eol)
+	if bom {
+		output = utf8BOM + output
+	}
+	if err := writeFileAtomic(file, []byte(output)); err != nil {
+		return 0, err
+	}
+	return removed, nil
+}
+
+// removeEmptyCommentBlocks drops block comment wrappers (/* */, /** */, <!-- --> or the block
+// style of ext) between startLine and endLine that were left empty after their header lines were
+// removed, along with a blank line that follows them
+func removeEmptyCommentBlocks(cfg *config.Config, ext string, lines []string, startLine, endLine int) []string {
+	style, isBlock := cfg.BlockComment(ext)
+	result := append([]string{}, lines[:startLine]...)
+	for i := startLine; i < len(lines); i++ {
+		trimmed := strings.TrimSpace(lines[i])
+		if i < endLine && i+1 < len(lines) {
+			next := strings.TrimSpace(lines[i+1])
+			if ((trimmed == "/*" || trimmed == "/**") && next == "*/") || (trimmed == "<!--" && next == "-->") ||
+				(isBlock && trimmed == strings.TrimSpace(style.Open) && next == strings.TrimSpace(style.Close)) {
+				i++
+				if i+1 < len(lines) && strings.TrimSpace(lines[i+1]) == "" {
+					i++
+				}
+				continue
+			}
+		}
+		result = append(result, lines[i])
+	}
+	return result
+}
@@ -0,0 +1,289 @@
+// Copyright IBM Corp. 2014, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package copyright
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/YakDriver/copyplop/internal/config"
+)
+
+func TestFixer_dedupeFile(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	cfg := &config.Config{
+		Copyright: config.Copyright{
+			Holder:      "IBM Corp.",
+			StartYear:   2014,
+			CurrentYear: 2025,
+			Format:      "Copyright {{.Holder}} {{.StartYear}}, {{.CurrentYear}}",
+		},
+		License: config.License{
+			Enabled:    true,
+			Identifier: "MPL-2.0",
+			Format:     "SPDX-License-Identifier: {{.Identifier}}",
+		},
+		Files: config.Files{
+			CommentStyles: map[string]string{"go": "//", "js": "/**"},
+		},
+		Detection: config.Detection{
+			ReplacePatterns: []string{"Copyright.*HashiCorp"},
+			MaxScanLines:    20,
+		},
+	}
+
+	tests := []struct {
+		name            string
+		filename        string
+		input           string
+		expectedOutput  string
+		expectedRemoved int
+	}{
+		{
+			name:     "two canonical headers",
+			filename: "dup.go",
+			input: `// Copyright IBM Corp. 2014, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+// Copyright IBM Corp. 2014, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package main`,
+			expectedOutput: `// Copyright IBM Corp. 2014, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package main`,
+			expectedRemoved: 2,
+		},
+		{
+			name:     "conflicting old headers",
+			filename: "conflict.go",
+			input: `// Copyright IBM Corp. 2014, 2025
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) HashiCorp, Inc.
+// Copyright IBM Corp. 2014, 2024
+// SPDX-License-Identifier: Apache-2.0
+
+package main`,
+			expectedOutput: `// Copyright IBM Corp. 2014, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package main`,
+			expectedRemoved: 3,
+		},
+		{
+			name:     "duplicate block comment",
+			filename: "dup.js",
+			input: `/**
+ * Copyright IBM Corp. 2014, 2025
+ * SPDX-License-Identifier: MPL-2.0
+ */
+
+/**
+ * Copyright IBM Corp. 2014, 2025
+ * SPDX-License-Identifier: MPL-2.0
+ */
+
+function hello() {}`,
+			expectedOutput: `/**
+ * Copyright IBM Corp. 2014, 2025
+ * SPDX-License-Identifier: MPL-2.0
+ */
+
+function hello() {}`,
+			expectedRemoved: 2,
+		},
+		{
+			name:     "wrong syntax header in a block comment",
+			filename: "block.go",
+			input: `// Copyright IBM Corp. 2014, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+/*
+ * Copyright IBM Corp. 2014, 2024
+ */
+
+package main`,
+			expectedOutput: `// Copyright IBM Corp. 2014, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package main`,
+			expectedRemoved: 1,
+		},
+		{
+			name:     "single correct header untouched",
+			filename: "correct.go",
+			input: `// Copyright IBM Corp. 2014, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package main`,
+			expectedOutput: `// Copyright IBM Corp. 2014, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package main`,
+			expectedRemoved: 0,
+		},
+		{
+			name:     "old header without canonical is left for fix",
+			filename: "old.go",
+			input: `// Copyright (c) HashiCorp, Inc.
+
+package main`,
+			expectedOutput: `// Copyright (c) HashiCorp, Inc.
+
+package main`,
+			expectedRemoved: 0,
+		},
+	}
+
+	fixer := NewFixer(cfg)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filePath := filepath.Join(tmpDir, tt.filename)
+			if err := os.WriteFile(filePath, []byte(tt.input), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			removed, err := fixer.dedupeFile(filePath)
+			if err != nil {
+				t.Fatalf("dedupeFile() error = %v", err)
+			}
+			if removed != tt.expectedRemoved {
+				t.Errorf("dedupeFile() removed = %d, want %d", removed, tt.expectedRemoved)
+			}
+
+			content, err := os.ReadFile(filePath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(content) != tt.expectedOutput {
+				t.Errorf("Expected:\n%s\n\nGot:\n%s", tt.expectedOutput, string(content))
+			}
+		})
+	}
+}
+
+func TestFixer_dedupeFileMultiLineCopyright(t *testing.T) {
+	cfg := &config.Config{
+		Copyright: config.Copyright{
+			Holder:      "IBM Corp.",
+			StartYear:   2014,
+			CurrentYear: 2025,
+			Format:      "Copyright {{.Holder}} {{.StartYear}}, {{.CurrentYear}}\n\nAll rights reserved.",
+		},
+		License: config.License{
+			Enabled:    true,
+			Identifier: "MPL-2.0",
+			Format:     "SPDX-License-Identifier: {{.Identifier}}",
+		},
+		Files: config.Files{
+			CommentStyles: map[string]string{"go": "//"},
+		},
+		Detection: config.Detection{
+			MaxScanLines: 20,
+		},
+	}
+
+	header := "// Copyright IBM Corp. 2014, 2025\n//\n// All rights reserved.\n"
+	tests := []struct {
+		name            string
+		input           string
+		expectedOutput  string
+		expectedRemoved int
+	}{
+		{
+			name:            "two canonical blocks",
+			input:           header + header + "// SPDX-License-Identifier: MPL-2.0\n\npackage main\n",
+			expectedOutput:  header + "// SPDX-License-Identifier: MPL-2.0\n\npackage main\n",
+			expectedRemoved: 3,
+		},
+		{
+			name:            "two complete headers",
+			input:           header + "// SPDX-License-Identifier: MPL-2.0\n\n" + header + "// SPDX-License-Identifier: MPL-2.0\n\npackage main\n",
+			expectedOutput:  header + "// SPDX-License-Identifier: MPL-2.0\n\npackage main\n",
+			expectedRemoved: 4,
+		},
+		{
+			name:            "outdated block next to the canonical one",
+			input:           header + "// SPDX-License-Identifier: MPL-2.0\n// Copyright IBM Corp. 2014, 2024\n//\n// All rights reserved.\n\npackage main\n",
+			expectedOutput:  header + "// SPDX-License-Identifier: MPL-2.0\n\npackage main\n",
+			expectedRemoved: 3,
+		},
+	}
+
+	fixer := NewFixer(cfg)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filePath := filepath.Join(t.TempDir(), "main.go")
+			if err := os.WriteFile(filePath, []byte(tt.input), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			removed, err := fixer.dedupeFile(filePath)
+			if err != nil {
+				t.Fatalf("dedupeFile() error = %v", err)
+			}
+			if removed != tt.expectedRemoved {
+				t.Errorf("dedupeFile() removed = %d, want %d", removed, tt.expectedRemoved)
+			}
+
+			content, err := os.ReadFile(filePath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(content) != tt.expectedOutput {
+				t.Errorf("Expected:\n%q\n\nGot:\n%q", tt.expectedOutput, string(content))
+			}
+		})
+	}
+}
+
+func TestFixer_DedupeErrors(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	cfg := &config.Config{
+		Copyright: config.Copyright{
+			Holder:      "IBM Corp.",
+			StartYear:   2014,
+			CurrentYear: 2025,
+			Format:      "Copyright {{.Holder}} {{.StartYear}}, {{.CurrentYear}}",
+		},
+		Files: config.Files{
+			Extensions:    []string{".go"},
+			CommentStyles: map[string]string{"go": "//"},
+		},
+		Detection: config.Detection{
+			MaxScanLines: 20,
+		},
+	}
+
+	duplicated := "// Copyright IBM Corp. 2014, 2025\n// Copyright IBM Corp. 2014, 2025\n\npackage main\n"
+	if err := os.WriteFile(filepath.Join(tmpDir, "a.go"), []byte(duplicated), 0644); err != nil {
+		t.Fatal(err)
+	}
+	readOnly := filepath.Join(tmpDir, "b.go")
+	if err := os.WriteFile(readOnly, []byte(duplicated), 0444); err != nil {
+		t.Fatal(err)
+	}
+
+	results, errs, err := NewFixer(cfg).Dedupe(context.Background(), tmpDir)
+	if err != nil {
+		t.Fatalf("Dedupe() error = %v", err)
+	}
+	want := []DedupeResult{{File: filepath.Join(tmpDir, "a.go"), Removed: 1}}
+	if !slices.Equal(results, want) {
+		t.Errorf("Dedupe() results = %+v, want %+v", results, want)
+	}
+	// The file that could not be written is reported rather than counted as deduplicated
+	if len(errs) != 1 || errs[0].File != readOnly || !errors.Is(errs[0], fs.ErrPermission) {
+		t.Errorf("Dedupe() errors = %v, want a permission error for b.go", errs)
+	}
+}
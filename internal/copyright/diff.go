@@ -0,0 +1,174 @@
+// Copyright IBM Corp. 2014, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package copyright
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// diffContext is the number of unchanged lines shown around each change
+const diffContext = 3
+
+// diffLine is a line of a diff: ' ' kept, '-' removed or '+' added
+type diffLine struct {
+	kind byte
+	text string
+}
+
+// noNewline ends the last line of content without a final newline while diffing, so that it
+// differs from the same line followed by one; no line split on newlines can contain it
+const noNewline = "\n"
+
+// unifiedDiff renders the changes from before to after, each split on newlines, as a unified
+// diff of file, as git diff would show them, or returns "" when nothing changed. A last line
+// without a final newline is marked "\ No newline at end of file". Carriage returns are not
+// printed, so CRLF files diff like LF files while a changed line ending still shows as a
+// changed line.
+func unifiedDiff(file string, before, after []string) string {
+	ops := diffLines(diffableLines(before), diffableLines(after))
+
+	var b strings.Builder
+	for start := 0; start < len(ops); {
+		// Find the next change and the end of its hunk, merging changes whose context overlaps
+		first := start
+		for first < len(ops) && ops[first].kind == ' ' {
+			first++
+		}
+		if first == len(ops) {
+			break
+		}
+		last := first
+		for i := first; i < len(ops) && i <= last+2*diffContext; i++ {
+			if ops[i].kind != ' ' {
+				last = i
+			}
+		}
+
+		from := max(first-diffContext, start)
+		to := min(last+diffContext+1, len(ops))
+		if b.Len() == 0 {
+			fmt.Fprintf(&b, "--- %s\n+++ %s\n", filepath.ToSlash(filepath.Join("a", file)), filepath.ToSlash(filepath.Join("b", file)))
+		}
+		writeHunk(&b, ops, from, to)
+		start = to
+	}
+	return b.String()
+}
+
+// diffableLines returns content split on newlines as the lines to diff: the empty element
+// after a final newline is not a line of its own, and without one the last line is marked
+func diffableLines(content []string) []string {
+	if len(content) == 0 {
+		return content
+	}
+	last := len(content) - 1
+	if content[last] == "" {
+		return content[:last]
+	}
+	return append(slices.Clone(content[:last]), content[last]+noNewline)
+}
+
+// writeHunk writes ops[from:to] as a hunk with its "@@ -l,s +l,s @@" range header
+func writeHunk(b *strings.Builder, ops []diffLine, from, to int) {
+	beforeStart, afterStart := 1, 1
+	for _, op := range ops[:from] {
+		if op.kind != '+' {
+			beforeStart++
+		}
+		if op.kind != '-' {
+			afterStart++
+		}
+	}
+	beforeCount, afterCount := 0, 0
+	for _, op := range ops[from:to] {
+		if op.kind != '+' {
+			beforeCount++
+		}
+		if op.kind != '-' {
+			afterCount++
+		}
+	}
+	// An empty range names the line before it
+	if beforeCount == 0 {
+		beforeStart--
+	}
+	if afterCount == 0 {
+		afterStart--
+	}
+
+	fmt.Fprintf(b, "@@ -%d,%d +%d,%d @@\n", beforeStart, beforeCount, afterStart, afterCount)
+	for _, op := range ops[from:to] {
+		text, missingNewline := strings.CutSuffix(op.text, noNewline)
+		b.WriteByte(op.kind)
+		b.WriteString(strings.TrimSuffix(text, "\r"))
+		b.WriteByte('\n')
+		if missingNewline {
+			b.WriteString("\\ No newline at end of file\n")
+		}
+	}
+}
+
+// diffLines returns the edit script turning a into b, based on their longest common
+// subsequence. Common leading and trailing lines are matched up front, so only the changed
+// header region is compared line by line.
+func diffLines(a, b []string) []diffLine {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	var ops []diffLine
+	for _, line := range a[:prefix] {
+		ops = append(ops, diffLine{' ', line})
+	}
+
+	ma, mb := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+	lcs := make([][]int, len(ma)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(mb)+1)
+	}
+	for i := len(ma) - 1; i >= 0; i-- {
+		for j := len(mb) - 1; j >= 0; j-- {
+			if ma[i] == mb[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(ma) && j < len(mb) {
+		switch {
+		case ma[i] == mb[j]:
+			ops = append(ops, diffLine{' ', ma[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			ops = append(ops, diffLine{'-', ma[i]})
+			i++
+		default:
+			ops = append(ops, diffLine{'+', mb[j]})
+			j++
+		}
+	}
+	for ; i < len(ma); i++ {
+		ops = append(ops, diffLine{'-', ma[i]})
+	}
+	for ; j < len(mb); j++ {
+		ops = append(ops, diffLine{'+', mb[j]})
+	}
+
+	for _, line := range a[len(a)-suffix:] {
+		ops = append(ops, diffLine{' ', line})
+	}
+	return ops
+}
@@ -0,0 +1,188 @@
+// Copyright IBM Corp. 2014, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package copyright
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/YakDriver/copyplop/internal/config"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+	tests := []struct {
+		name   string
+		before string
+		after  string
+		want   string
+	}{
+		{
+			name:   "unchanged",
+			before: "// Copyright IBM Corp. 2014, 2025\npackage main\n",
+			after:  "// Copyright IBM Corp. 2014, 2025\npackage main\n",
+			want:   "",
+		},
+		{
+			name:   "header added",
+			before: "package main\n\nfunc main() {}\n",
+			after:  "// Copyright IBM Corp. 2014, 2025\n\npackage main\n\nfunc main() {}\n",
+			want: "--- a/main.go\n+++ b/main.go\n@@ -1,3 +1,5 @@\n" +
+				"+// Copyright IBM Corp. 2014, 2025\n+\n package main\n \n func main() {}\n",
+		},
+		{
+			name:   "year updated with context",
+			before: "// Copyright IBM Corp. 2014, 2024\n// SPDX-License-Identifier: MPL-2.0\n\npackage main\n\nimport \"fmt\"\n\nfunc main() {}\n",
+			after:  "// Copyright IBM Corp. 2014, 2025\n// SPDX-License-Identifier: MPL-2.0\n\npackage main\n\nimport \"fmt\"\n\nfunc main() {}\n",
+			want: "--- a/main.go\n+++ b/main.go\n@@ -1,4 +1,4 @@\n" +
+				"-// Copyright IBM Corp. 2014, 2024\n+// Copyright IBM Corp. 2014, 2025\n" +
+				" // SPDX-License-Identifier: MPL-2.0\n \n package main\n",
+		},
+		{
+			name:   "crlf",
+			before: "// Copyright IBM Corp. 2014, 2024\r\npackage main\r\n",
+			after:  "// Copyright IBM Corp. 2014, 2025\r\npackage main\r\n",
+			want: "--- a/main.go\n+++ b/main.go\n@@ -1,2 +1,2 @@\n" +
+				"-// Copyright IBM Corp. 2014, 2024\n+// Copyright IBM Corp. 2014, 2025\n package main\n",
+		},
+		{
+			name:   "header removed from file end",
+			before: "package main\n// Copyright IBM Corp. 2014, 2025",
+			after:  "package main",
+			want: "--- a/main.go\n+++ b/main.go\n@@ -1,2 +1,1 @@\n" +
+				"-package main\n-// Copyright IBM Corp. 2014, 2025\n\\ No newline at end of file\n" +
+				"+package main\n\\ No newline at end of file\n",
+		},
+		{
+			name:   "no newline at end of file in context",
+			before: "// Copyright IBM Corp. 2014, 2024\npackage main",
+			after:  "// Copyright IBM Corp. 2014, 2025\npackage main",
+			want: "--- a/main.go\n+++ b/main.go\n@@ -1,2 +1,2 @@\n" +
+				"-// Copyright IBM Corp. 2014, 2024\n+// Copyright IBM Corp. 2014, 2025\n package main\n\\ No newline at end of file\n",
+		},
+		{
+			name:   "final newline added",
+			before: "package main",
+			after:  "// Copyright IBM Corp. 2014, 2025\n\npackage main\n",
+			want: "--- a/main.go\n+++ b/main.go\n@@ -1,1 +1,3 @@\n" +
+				"-package main\n\\ No newline at end of file\n+// Copyright IBM Corp. 2014, 2025\n+\n+package main\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := unifiedDiff("main.go", strings.Split(tt.before, "\n"), strings.Split(tt.after, "\n"))
+			if got != tt.want {
+				t.Errorf("unifiedDiff() =\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFixer_Diff(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	cfg := &config.Config{
+		Copyright: config.Copyright{
+			Holder:      "IBM Corp.",
+			StartYear:   2014,
+			CurrentYear: 2025,
+			Format:      "Copyright {{.Holder}} {{.StartYear}}, {{.CurrentYear}}",
+		},
+		Files: config.Files{
+			Extensions:    []string{".go"},
+			CommentStyles: map[string]string{"go": "//"},
+		},
+		Detection: config.Detection{
+			MaxScanLines: 20,
+		},
+	}
+
+	files := map[string]string{
+		"current.go": "// Copyright IBM Corp. 2014, 2025\n\npackage main\n",
+		"crlf.go":    "// Copyright IBM Corp. 2014, 2024\r\n\r\npackage main\r\n",
+		"eof.go":     "// Copyright IBM Corp. 2014, 2024\n\npackage main",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var out bytes.Buffer
+	fixer := NewFixer(cfg)
+	fixer.DryRun = true
+	fixer.Diff = &out
+	if _, err := fixer.Fix(context.Background(), tmpDir); err != nil {
+		t.Fatalf("Fix() error = %v", err)
+	}
+
+	crlfPath := filepath.Join(tmpDir, "crlf.go")
+	eofPath := filepath.Join(tmpDir, "eof.go")
+	want := "--- a" + filepath.ToSlash(crlfPath) + "\n+++ b" + filepath.ToSlash(crlfPath) + "\n@@ -1,3 +1,3 @@\n" +
+		"-// Copyright IBM Corp. 2014, 2024\n+// Copyright IBM Corp. 2014, 2025\n \n package main\n" +
+		"--- a" + filepath.ToSlash(eofPath) + "\n+++ b" + filepath.ToSlash(eofPath) + "\n@@ -1,3 +1,3 @@\n" +
+		"-// Copyright IBM Corp. 2014, 2024\n+// Copyright IBM Corp. 2014, 2025\n \n package main\n\\ No newline at end of file\n"
+	if out.String() != want {
+		t.Errorf("Diff output =\n%s\nwant:\n%s", out.String(), want)
+	}
+
+	// A dry run leaves the files as they were
+	for name, content := range files {
+		got, err := os.ReadFile(filepath.Join(tmpDir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != content {
+			t.Errorf("Expected %s unchanged in a dry run, got %q", name, string(got))
+		}
+	}
+}
+
+func TestFixer_DiffOrder(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	cfg := &config.Config{
+		Copyright: config.Copyright{
+			Holder:      "IBM Corp.",
+			StartYear:   2014,
+			CurrentYear: 2025,
+			Format:      "Copyright {{.Holder}} {{.StartYear}}, {{.CurrentYear}}",
+		},
+		Files: config.Files{
+			Extensions:    []string{".go"},
+			CommentStyles: map[string]string{"go": "//"},
+		},
+		Detection: config.Detection{
+			MaxScanLines: 20,
+		},
+	}
+
+	var want strings.Builder
+	for i := range 20 {
+		path := filepath.Join(tmpDir, fmt.Sprintf("f%02d.go", i))
+		if err := os.WriteFile(path, []byte("package main\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		want.WriteString("--- a" + filepath.ToSlash(path) + "\n+++ b" + filepath.ToSlash(path) + "\n@@ -1,1 +1,3 @@\n" +
+			"+// Copyright IBM Corp. 2014, 2025\n+\n package main\n")
+	}
+
+	// Diffs are printed in file order whichever worker fixed each file
+	var out bytes.Buffer
+	fixer := NewFixer(cfg)
+	fixer.Jobs = 8
+	fixer.Quiet = true
+	fixer.Diff = &out
+	if _, err := fixer.Fix(context.Background(), tmpDir); err != nil {
+		t.Fatalf("Fix() error = %v", err)
+	}
+	if out.String() != want.String() {
+		t.Errorf("Diff output =\n%s\nwant:\n%s", out.String(), want.String())
+	}
+}
@@ -0,0 +1,118 @@
+// Copyright IBM Corp. 2014, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package copyright
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/YakDriver/copyplop/internal/config"
+)
+
+// extractDocstringHeader moves header lines (copyright, SPDX, notice or replace-pattern
+// lines) out of a Python module docstring starting after lines[start] and into comments above it,
+// where the rest of the header is maintained. The remaining docstring text is kept and a
+// docstring left empty is removed. Only done with detection.python_docstrings set.
+// Returns the new lines and whether anything changed.
+func extractDocstringHeader(cfg *config.Config, ext string, lines []string, start int) ([]string, bool) {
+	if !cfg.Detection.PythonDocstrings || ext != ".py" {
+		return lines, false
+	}
+
+	prefix := cfg.CommentPrefix(ext)
+	noticeHeader, _ := cfg.GetNoticeHeader(ext)
+	isHeaderContent := func(content string) bool {
+		line := prefix + " " + content
+		return content != "" && (cfg.IsOwnCopyrightLine(line, ext) || cfg.ShouldReplace(content) ||
+			isSPDXHeaderLine(cfg, ext, line) || (noticeHeader != "" && isSameHeaderLine(line, noticeHeader)))
+	}
+
+	// The module docstring is the first statement: only comments and blank lines may precede it
+	openLine := -1
+	for i := start; i < len(lines); i++ {
+		trimmed := strings.TrimSpace(lines[i])
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		openLine = i
+		break
+	}
+	if openLine < 0 {
+		return lines, false
+	}
+
+	opening := strings.TrimSpace(lines[openLine])
+	delim := ""
+	for _, d := range []string{`"""`, `'''`} {
+		if strings.HasPrefix(opening, d) {
+			delim = d
+		}
+	}
+	if delim == "" {
+		return lines, false
+	}
+
+	// Collect the docstring body up to the closing delimiter
+	var body []string
+	closeLine := -1
+	closedAlone := false
+	for j := openLine; j < len(lines); j++ {
+		text := lines[j]
+		if j == openLine {
+			text = opening[len(delim):]
+		}
+		if before, tail, found := strings.Cut(text, delim); found {
+			if strings.TrimSpace(tail) != "" {
+				return lines, false // code after the docstring on the same line
+			}
+			if strings.TrimSpace(before) != "" {
+				body = append(body, before)
+			} else {
+				closedAlone = j > openLine
+			}
+			closeLine = j
+			break
+		}
+		body = append(body, text)
+	}
+	if closeLine < 0 || !slices.ContainsFunc(body, func(line string) bool { return isHeaderContent(strings.TrimSpace(line)) }) {
+		return lines, false
+	}
+	openedAlone := closeLine > openLine && strings.TrimSpace(opening[len(delim):]) == ""
+	if openedAlone {
+		body = body[1:]
+	}
+
+	var header, rest []string
+	for _, line := range body {
+		if content := strings.TrimSpace(line); isHeaderContent(content) {
+			header = append(header, prefix+" "+content)
+		} else {
+			rest = append(rest, line)
+		}
+	}
+	for len(rest) > 0 && strings.TrimSpace(rest[0]) == "" {
+		rest = rest[1:]
+	}
+	for len(rest) > 0 && strings.TrimSpace(rest[len(rest)-1]) == "" {
+		rest = rest[:len(rest)-1]
+	}
+
+	out := append(slices.Clone(lines[:openLine]), header...)
+	if len(rest) > 0 {
+		out = append(out, "")
+		if openedAlone {
+			out = append(out, delim)
+		} else {
+			rest[0] = delim + strings.TrimSpace(rest[0])
+		}
+		out = append(out, rest...)
+		if closedAlone {
+			out = append(out, delim)
+		} else {
+			out[len(out)-1] += delim
+		}
+	}
+	return append(out, lines[closeLine+1:]...), true
+}
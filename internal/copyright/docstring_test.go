@@ -0,0 +1,110 @@
+// Copyright IBM Corp. 2014, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package copyright
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/YakDriver/copyplop/internal/config"
+)
+
+func TestFixer_PythonDocstring(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	cfg := &config.Config{
+		Copyright: config.Copyright{
+			Holder:      "IBM Corp.",
+			StartYear:   2014,
+			CurrentYear: 2025,
+			Format:      "Copyright {{.Holder}} {{.StartYear}}, {{.CurrentYear}}",
+		},
+		License: config.License{
+			Enabled:    true,
+			Identifier: "MPL-2.0",
+			Format:     "SPDX-License-Identifier: {{.Identifier}}",
+		},
+		Files: config.Files{
+			CommentStyles: map[string]string{"py": "#"},
+		},
+		Detection: config.Detection{
+			MaxScanLines:     20,
+			PythonDocstrings: true,
+		},
+	}
+
+	header := "# Copyright IBM Corp. 2014, 2025\n# SPDX-License-Identifier: MPL-2.0\n\n"
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "docstring holding only the header",
+			input:    "\"\"\"\nCopyright IBM Corp. 2014, 2020\nSPDX-License-Identifier: MPL-2.0\n\"\"\"\n\nimport os\n",
+			expected: header + "import os\n",
+		},
+		{
+			name:     "header above the module description",
+			input:    "\"\"\"\nCopyright IBM Corp. 2014, 2025\nSPDX-License-Identifier: MPL-2.0\n\nUtilities for parsing.\n\"\"\"\n\nimport os\n",
+			expected: header + "\"\"\"\nUtilities for parsing.\n\"\"\"\n\nimport os\n",
+		},
+		{
+			name:     "summary on the opening line",
+			input:    "\"\"\"Utilities for parsing.\n\nCopyright IBM Corp. 2014, 2025\n\"\"\"\n\nimport os\n",
+			expected: header + "\"\"\"Utilities for parsing.\n\"\"\"\n\nimport os\n",
+		},
+		{
+			name:     "single-line docstring",
+			input:    "'''Copyright IBM Corp. 2014, 2025'''\nimport os\n",
+			expected: header + "import os\n",
+		},
+		{
+			name:     "docstring without a header",
+			input:    "\"\"\"Utilities for parsing.\"\"\"\n\nimport os\n",
+			expected: header + "\"\"\"Utilities for parsing.\"\"\"\n\nimport os\n",
+		},
+	}
+
+	fixer := NewFixer(cfg)
+	checker := NewChecker(cfg)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filePath := filepath.Join(tmpDir, "module.py")
+			if err := os.WriteFile(filePath, []byte(tt.input), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			if issue := checker.checkFile(filePath); issue == nil {
+				t.Error("Expected an issue before fixing")
+			}
+
+			mustFixFile(t, fixer, filePath)
+
+			content, err := os.ReadFile(filePath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(content) != tt.expected {
+				t.Errorf("Expected:\n%q\n\nGot:\n%q", tt.expected, string(content))
+			}
+
+			if mustFixFile(t, fixer, filePath) {
+				t.Error("Expected second run to make no changes")
+			}
+			if issue := checker.checkFile(filePath); issue != nil {
+				t.Errorf("Expected no issue after fixing, got %q", issue.Problem)
+			}
+		})
+	}
+
+	// Without the option, docstrings are left alone
+	cfg.Detection.PythonDocstrings = false
+	lines := []string{`"""`, "Copyright IBM Corp. 2014, 2025", `"""`}
+	if _, changed := extractDocstringHeader(cfg, ".py", lines, 0); changed {
+		t.Errorf("extractDocstringHeader() changed %q with python_docstrings off", lines)
+	}
+}
@@ -4,37 +4,318 @@
 package copyright
 
 import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
+	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/YakDriver/copyplop/internal/config"
 )
 
+var (
+	// ErrGitNotFound is returned when files.git_tracked is set but git is not installed
+	ErrGitNotFound = errors.New("git not found in PATH (install git, or set files.git_tracked: false)")
+	// ErrNotGitRepository is returned when files.git_tracked is set but the path is not in a git repository
+	ErrNotGitRepository = errors.New("not a git repository (set files.git_tracked: false to process all files)")
+)
+
+// gitCommand is the git executable; tests override it to simulate a missing git
+var gitCommand = "git"
+
 func getTrackedFiles(path string, cfg *config.Config) ([]string, error) {
 	if cfg.Files.GitTracked {
-		return getGitFiles(path)
+		files, err := getGitFiles(path)
+		if cfg.Files.GitFallback && (errors.Is(err, ErrGitNotFound) || errors.Is(err, ErrNotGitRepository)) {
+			fmt.Fprintf(os.Stderr, "Warning: %v; processing all files instead\n", err)
+			return getAllFiles(path)
+		}
+		return files, err
 	}
 	return getAllFiles(path)
 }
 
-func getGitFiles(path string) ([]string, error) {
-	cmd := exec.Command("git", "ls-files", path)
-	output, err := cmd.Output()
+// getFilesToProcess returns the files under path that the config selects for processing,
+// sorted so that runs are reproducible regardless of git or filesystem walk order. With
+// modified set, only files modified or untracked in the git working tree are considered.
+func getFilesToProcess(path string, cfg *config.Config, modified bool) ([]string, error) {
+	files, err := getCandidateFiles(path, cfg, modified)
+	if err != nil {
+		return nil, err
+	}
+
+	filesToProcess := filterFiles(files, cfg)
+	slices.Sort(filesToProcess)
+	return filesToProcess, nil
+}
+
+// getCandidateFiles returns the files under path before the config's filters are applied:
+// with modified those modified or untracked in the git working tree, otherwise all files or,
+// with files.git_tracked, those tracked by git
+func getCandidateFiles(path string, cfg *config.Config, modified bool) ([]string, error) {
+	if modified {
+		return getModifiedFiles(path)
+	}
+	return getTrackedFiles(path, cfg)
+}
+
+// filterFiles returns the files that the config selects for processing
+func filterFiles(files []string, cfg *config.Config) []string {
+	var filesToProcess []string
+	for _, file := range files {
+		if skipReason(file, cfg) == "" {
+			filesToProcess = append(filesToProcess, file)
+		}
+	}
+	return filesToProcess
+}
+
+// skipReason returns why the config leaves file out of processing, or "" when it selects it
+func skipReason(file string, cfg *config.Config) string {
+	if reason := cfg.SkipReason(file); reason != "" {
+		return reason
+	}
+	if cfg.Files.SkipExecutable {
+		info, err := os.Stat(file)
+		if err != nil {
+			return err.Error()
+		}
+		if !cfg.ShouldProcessMode(info.Mode()) {
+			return "executable (files.skip_executable)"
+		}
+	}
+	return ""
+}
+
+// ListedFile is a file found under the path being processed
+type ListedFile struct {
+	File string `json:"file"`
+	// SkipReason tells why the config leaves the file out; empty for files that are processed
+	SkipReason string `json:"skip_reason,omitempty"`
+}
+
+// ListFiles returns every file found under path, tracked by git with files.git_tracked or
+// with modified only those modified or untracked, sorted, with the reason each file the
+// config does not select is skipped. File contents are not read.
+func ListFiles(path string, cfg *config.Config, modified bool) ([]ListedFile, error) {
+	files, err := getCandidateFiles(path, cfg, modified)
 	if err != nil {
 		return nil, err
 	}
 
+	slices.Sort(files)
+	listed := make([]ListedFile, 0, len(files))
+	for _, file := range files {
+		listed = append(listed, ListedFile{File: file, SkipReason: skipReason(file, cfg)})
+	}
+	return listed, nil
+}
+
+// getGitFiles lists git-tracked files under path. Git runs from the directory being
+// processed so it works when path is in a different repository or worktree than the CWD.
+func getGitFiles(path string) ([]string, error) {
+	dir, target := gitDirAndTarget(path)
+
+	if _, err := exec.LookPath(gitCommand); err != nil {
+		return nil, ErrGitNotFound
+	}
+
+	output, err := runGit(dir, "ls-files", target)
+	if err != nil {
+		return nil, gitError(path, err)
+	}
+
+	// git prints paths relative to its working directory; make them relative to the CWD again
 	var files []string
-	for line := range strings.SplitSeq(string(output), "\n") {
+	for line := range strings.SplitSeq(output, "\n") {
 		if line != "" {
-			files = append(files, line)
+			files = append(files, filepath.Join(dir, line))
 		}
 	}
 	return files, nil
 }
 
+// gitDirAndTarget returns the directory to run git in and the pathspec for path:
+// the directory itself, or a single file within its parent directory
+func gitDirAndTarget(path string) (dir, target string) {
+	if info, err := os.Stat(path); err == nil && !info.IsDir() {
+		return filepath.Dir(path), filepath.Base(path)
+	}
+	return path, "."
+}
+
+// runGit runs git with args in dir and returns its standard output
+func runGit(dir string, args ...string) (string, error) {
+	cmd := exec.Command(gitCommand, args...)
+	cmd.Dir = dir
+	output, err := cmd.Output()
+	return string(output), err
+}
+
+// gitStartYears caches the year each file was added to git, keyed by absolute path, so
+// that fixing and then verifying a file runs git once
+var gitStartYears sync.Map
+
+// gitStartYear returns the year file was first committed, following renames, or 0 when
+// it is not tracked or git is unavailable
+func gitStartYear(file string) int {
+	abs, err := filepath.Abs(file)
+	if err != nil {
+		return 0
+	}
+	if year, ok := gitStartYears.Load(abs); ok {
+		return year.(int)
+	}
+
+	year := 0
+	output, err := runGit(filepath.Dir(abs), "log", "--diff-filter=A", "--follow", "--format=%ad", "--date=format:%Y", "--", filepath.Base(abs))
+	if err == nil {
+		// With --follow each add along the rename history is listed, newest first
+		lines := strings.Fields(output)
+		if len(lines) > 0 {
+			year, _ = strconv.Atoi(lines[len(lines)-1])
+		}
+	}
+	gitStartYears.Store(abs, year)
+	return year
+}
+
+// withGitStartYear applies copyright.start_year_from_git: the header of file starts with
+// the year file was added to git, while files git does not know keep the configured year
+func withGitStartYear(cfg *config.Config, file string) *config.Config {
+	if !cfg.Copyright.StartYearFromGit {
+		return cfg
+	}
+	year := gitStartYear(file)
+	if year == 0 {
+		return cfg
+	}
+	clone := *cfg
+	clone.Copyright.StartYear = year
+	return &clone
+}
+
+// gitError turns a failed git invocation into an actionable error
+func gitError(path string, err error) error {
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		stderr := strings.TrimSpace(string(exitErr.Stderr))
+		if strings.Contains(stderr, "not a git repository") {
+			return fmt.Errorf("%s: %w", path, ErrNotGitRepository)
+		}
+		return fmt.Errorf("git failed: %s", stderr)
+	}
+	return err
+}
+
+// getModifiedFiles lists files under path that git status reports as modified, added or
+// untracked. Deleted files are skipped since there is nothing left to process.
+func getModifiedFiles(path string) ([]string, error) {
+	dir, target := gitDirAndTarget(path)
+
+	if _, err := exec.LookPath(gitCommand); err != nil {
+		return nil, ErrGitNotFound
+	}
+
+	// git status prints paths relative to the repository root; the prefix maps them back to dir
+	prefix, err := runGit(dir, "rev-parse", "--show-prefix")
+	if err != nil {
+		return nil, gitError(path, err)
+	}
+	prefix = strings.TrimSpace(prefix)
+
+	output, err := runGit(dir, "status", "--porcelain", "-z", "--untracked-files=all", "--", target)
+	if err != nil {
+		return nil, gitError(path, err)
+	}
+
+	// Entries are "XY path", NUL-terminated; renames and copies are followed by the original path
+	var files []string
+	entries := strings.Split(output, "\x00")
+	for i := 0; i < len(entries); i++ {
+		entry := entries[i]
+		if len(entry) < 4 {
+			continue
+		}
+		status, file := entry[:2], entry[3:]
+		if status[0] == 'R' || status[0] == 'C' {
+			i++
+		}
+		if strings.Contains(status, "D") {
+			continue
+		}
+		if rel, ok := strings.CutPrefix(file, prefix); ok {
+			files = append(files, filepath.Join(dir, filepath.FromSlash(rel)))
+		}
+	}
+	return files, nil
+}
+
+// getStagedFiles lists files under path added, copied, modified or renamed in the git
+// index, which is what a pre-commit hook is about to commit
+func getStagedFiles(path string) ([]string, error) {
+	return gitDiffFiles(path, "--cached", "--diff-filter=ACMR")
+}
+
+// getUnstagedFiles lists files under path with changes in the working tree that are not
+// staged in the git index
+func getUnstagedFiles(path string) ([]string, error) {
+	return gitDiffFiles(path)
+}
+
+// gitDiffFiles lists the files under path that git diff, with args, reports as changed
+func gitDiffFiles(path string, args ...string) ([]string, error) {
+	dir, target := gitDirAndTarget(path)
+
+	if _, err := exec.LookPath(gitCommand); err != nil {
+		return nil, ErrGitNotFound
+	}
+
+	// --relative prints paths relative to dir, as git ls-files does
+	args = append([]string{"diff", "--name-only", "-z", "--relative"}, args...)
+	output, err := runGit(dir, append(args, "--", target)...)
+	if err != nil {
+		return nil, gitError(path, err)
+	}
+
+	var files []string
+	for file := range strings.SplitSeq(output, "\x00") {
+		if file != "" {
+			files = append(files, filepath.Join(dir, filepath.FromSlash(file)))
+		}
+	}
+	return files, nil
+}
+
+// stageFiles adds files under path to the git index
+func stageFiles(path string, files []string) error {
+	if len(files) == 0 {
+		return nil
+	}
+	dir, _ := gitDirAndTarget(path)
+
+	args := []string{"add", "--"}
+	for _, file := range files {
+		abs, err := filepath.Abs(file)
+		if err != nil {
+			return err
+		}
+		args = append(args, abs)
+	}
+	if _, err := runGit(dir, args...); err != nil {
+		return gitError(path, err)
+	}
+	return nil
+}
+
 func getAllFiles(path string) ([]string, error) {
 	var files []string
 	err := filepath.Walk(path, func(p string, info os.FileInfo, err error) error {
@@ -49,8 +330,320 @@ func getAllFiles(path string) ([]string, error) {
 	return files, err
 }
 
+// writeFileAtomic replaces file with data by writing a temporary file in the same directory
+// and renaming it over the original, so an interrupted run never leaves a partial write.
+// The original file's permissions are kept, and symlinks are written through to their target.
+// A read-only file is not replaced, as writing it in place would fail: the rename would
+// otherwise bypass its permissions.
+func writeFileAtomic(file string, data []byte) error {
+	return writeFileAtomicFunc(file, func(w io.Writer) error {
+		_, err := w.Write(data)
+		return err
+	})
+}
+
+// writeFileAtomicFunc is writeFileAtomic with the new content produced by write
+func writeFileAtomicFunc(file string, write func(w io.Writer) error) error {
+	if target, err := filepath.EvalSymlinks(file); err == nil {
+		file = target
+	}
+
+	perm := os.FileMode(0644)
+	if info, err := os.Stat(file); err == nil {
+		perm = info.Mode().Perm()
+		if perm&0200 == 0 {
+			return &fs.PathError{Op: "write", Path: file, Err: fs.ErrPermission}
+		}
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(file), "."+filepath.Base(file)+".copyplop-*")
+	if err != nil {
+		return err
+	}
+	tmpName := tmp.Name()
+
+	buffered := bufio.NewWriter(tmp)
+	if err := write(buffered); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmpName)
+		return err
+	}
+	if err := buffered.Flush(); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmpName)
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmpName)
+		return err
+	}
+	if err := os.Chmod(tmpName, perm); err != nil {
+		_ = os.Remove(tmpName)
+		return err
+	}
+	if err := os.Rename(tmpName, file); err != nil {
+		_ = os.Remove(tmpName)
+		return err
+	}
+	return nil
+}
+
+// fileHead is the start of a file split into lines. Unless complete, the rest of the file
+// is left on disk and starts at offset, right after the newline ending the last line.
+// Lines are held without a leading UTF-8 byte order mark, and for CRLF files without the
+// "\r" of their line endings; bom and eol restore them when the lines are written back.
+type fileHead struct {
+	lines    []string
+	offset   int64
+	complete bool
+	bom      bool
+	eol      string
+	// newlineAtEOF is whether a complete head ended with a newline
+	newlineAtEOF bool
+}
+
+// utf8BOM is the UTF-8 byte order mark some editors start files with
+const utf8BOM = "\ufeff"
+
+// cutBOM returns content without a leading UTF-8 byte order mark and whether it had one
+func cutBOM(content []byte) ([]byte, bool) {
+	return bytes.CutPrefix(content, []byte(utf8BOM))
+}
+
+// readFileHead reads lines from the start of file until enough reports that the head covers
+// everything needed, or the whole file has been read. enough is consulted as the head doubles
+// in size. A complete head splits like splitLines.
+func readFileHead(file string, enough func(lines []string) bool) (*fileHead, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	head := &fileHead{}
+	reader := bufio.NewReader(f)
+	if prefix, _ := reader.Peek(len(utf8BOM)); string(prefix) == utf8BOM {
+		_, _ = reader.Discard(len(utf8BOM))
+		head.offset = int64(len(utf8BOM))
+		head.bom = true
+	}
+	nextCheck := 64
+	for {
+		line, err := reader.ReadString('\n')
+		if err == io.EOF {
+			head.eol = normalizeLineEndings(head.lines)
+			head.lines = append(head.lines, line)
+			head.complete = true
+			head.newlineAtEOF = line == "" && len(head.lines) > 1
+			return head, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		head.offset += int64(len(line))
+		head.lines = append(head.lines, strings.TrimSuffix(line, "\n"))
+		if len(head.lines) >= nextCheck {
+			if enough(head.lines) {
+				head.eol = normalizeLineEndings(head.lines)
+				return head, nil
+			}
+			nextCheck *= 2
+		}
+	}
+}
+
+// splitLines splits content into lines like strings.Split(content, "\n") and returns the
+// line ending most lines use, so fixed content can be joined with the ending it came with.
+// Lines of CRLF content are returned without their "\r".
+func splitLines(content string) ([]string, string) {
+	lines := strings.Split(content, "\n")
+	// The last line has no newline, so no line ending
+	return lines, normalizeLineEndings(lines[:len(lines)-1])
+}
+
+// joinLines joins lines split by splitLines with eol. Content that ended with a newline keeps
+// it; content that did not is joined as it is, so its last line is written unchanged.
+func joinLines(lines []string, eol string, newlineAtEOF bool) string {
+	content := strings.Join(lines, eol)
+	if newlineAtEOF && !strings.HasSuffix(content, "\n") {
+		content += eol
+	}
+	return content
+}
+
+// normalizeLineEndings returns "\r\n" when most of lines, each split off before a newline,
+// end in CRLF, stripping their "\r", and "\n" otherwise. Lines of LF content are left as
+// they are, so a stray "\r" before a newline is kept rather than mistaken for a line ending.
+func normalizeLineEndings(lines []string) string {
+	crlf := 0
+	for _, line := range lines {
+		if strings.HasSuffix(line, "\r") {
+			crlf++
+		}
+	}
+	if crlf*2 <= len(lines) {
+		return "\n"
+	}
+	for i, line := range lines {
+		lines[i] = strings.TrimSuffix(line, "\r")
+	}
+	return "\r\n"
+}
+
+// writeFileWithHead replaces file with lines followed by the unread remainder of the
+// original file, streaming the remainder instead of loading it into memory. Lines are
+// written with the byte order mark and line ending of the head; the remainder is copied as
+// it is.
+func writeFileWithHead(file string, lines []string, head *fileHead) error {
+	bom := ""
+	if head.bom {
+		bom = utf8BOM
+	}
+	if head.complete {
+		return writeFileAtomic(file, []byte(bom+joinLines(lines, head.eol, head.newlineAtEOF)))
+	}
+
+	src, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	if _, err := src.Seek(head.offset, io.SeekStart); err != nil {
+		return err
+	}
+
+	return writeFileAtomicFunc(file, func(w io.Writer) error {
+		if _, err := io.WriteString(w, bom); err != nil {
+			return err
+		}
+		for _, line := range lines {
+			if _, err := io.WriteString(w, line+head.eol); err != nil {
+				return err
+			}
+		}
+		_, err := io.Copy(w, src)
+		return err
+	})
+}
+
+// resolveExtension returns the extension that selects the comment style for file, handling
+// compound extensions like .html.markdown and detecting the content type of smart extensions.
+// ok is false when a smart-extension file looks binary and should be skipped.
+func resolveExtension(cfg *config.Config, file string, content []byte) (ext string, isSmartExt bool, ok bool) {
+	// Get extension, handling compound extensions like .html.markdown
+	ext = filepath.Ext(file)
+	for _, validExt := range cfg.Files.Extensions {
+		if strings.HasSuffix(file, validExt) && len(validExt) > len(ext) {
+			ext = validExt
+			break
+		}
+	}
+
+	// Check for smart extensions and detect actual content type
+	for _, smartExt := range cfg.Files.SmartExtensions {
+		if strings.HasSuffix(file, smartExt) && len(smartExt) >= len(ext) {
+			ext = smartExt
+			isSmartExt = true
+			break
+		}
+	}
+
+	// Forced mappings skip content detection, including the binary check
+	if isSmartExt {
+		if forcedExt, forced := cfg.ForcedSmartExtensionType(file); forced {
+			return forcedExt, true, true
+		}
+	}
+
+	// For smart extensions, detect the actual file type from content
+	if isSmartExt {
+		detectedExt := cfg.DetectSmartExtensionType(content, file)
+		if detectedExt == "" {
+			return "", true, false
+		}
+		ext = detectedExt
+	}
+
+	return ext, isSmartExt, true
+}
+
+// headerStartLine returns the line where the header belongs, after the shebang and any
+// configured placement exceptions (XML declaration, frontmatter, markdown heading)
+func headerStartLine(lines []string, cfg *config.Config, file string) int {
+	startLine := 0
+	if hasShebang(lines) {
+		startLine = 1
+
+		// Skip the blank line configured between shebang and header
+		if cfg.Files.BlankAfterShebang && startLine < len(lines) && strings.TrimSpace(lines[startLine]) == "" {
+			startLine++
+		}
+	}
+
+	// Handle XML declaration
+	if startLine < len(lines) && cfg.Files.PlacementExceptions.XMLDeclaration && hasXMLDeclaration(lines[startLine:]) {
+		startLine++
+	}
+
+	startLine = leadingLinesEnd(cfg, filepath.Ext(file), lines, startLine)
+
+	frontmatterEnd := getFrontmatterEndNew(lines, cfg, file)
+	if frontmatterEnd > startLine {
+		startLine = frontmatterEnd
+
+		// Skip the blank line configured between frontmatter and header
+		if cfg.Files.PlacementExceptions.BlankLineAfterFrontmatter && startLine < len(lines) && strings.TrimSpace(lines[startLine]) == "" {
+			startLine++
+		}
+	}
+
+	// Handle markdown heading - only for markdown files
+	isMarkdown := strings.HasSuffix(file, ".md") || strings.HasSuffix(file, ".markdown")
+	if startLine < len(lines) && isMarkdown && cfg.Files.PlacementExceptions.MarkdownHeading && hasMarkdownHeading(lines[startLine:]) {
+		startLine++
+	}
+
+	return startLine
+}
+
+// Minified files are recognized by a line longer than minifiedLineLength among their first
+// minifiedScanLines lines
+const (
+	minifiedLineLength = 1000
+	minifiedScanLines  = 20
+)
+
+// isMinified reports whether lines look like a minified (single huge line) file
+func isMinified(lines []string) bool {
+	for _, line := range lines[:min(len(lines), minifiedScanLines)] {
+		if len(line) > minifiedLineLength {
+			return true
+		}
+	}
+	return false
+}
+
+// forMinified applies detection.minified to a minified file: it reports whether the file is
+// to be skipped and otherwise returns the config to use, which for "block" puts the header
+// in a block comment so that nothing is commented out on the code line
+func forMinified(cfg *config.Config, ext string, lines []string) (*config.Config, bool) {
+	if cfg.Detection.Minified == "" || !isMinified(lines) {
+		return cfg, false
+	}
+	switch cfg.Detection.Minified {
+	case "skip":
+		return cfg, true
+	case "block":
+		return cfg.WithCommentStyle(ext, "/**"), false
+	}
+	return cfg, false
+}
+
+// hasShebang reports whether the first line is a shebang; a Rust inner attribute such as
+// #![allow(dead_code)] starts the same way but is not one
 func hasShebang(lines []string) bool {
-	return len(lines) > 0 && strings.HasPrefix(lines[0], "#!")
+	return len(lines) > 0 && strings.HasPrefix(lines[0], "#!") && !strings.HasPrefix(lines[0], "#![")
 }
 
 func hasXMLDeclaration(lines []string) bool {
@@ -61,19 +654,124 @@ func hasMarkdownHeading(lines []string) bool {
 	return len(lines) > 0 && strings.HasPrefix(strings.TrimSpace(lines[0]), "# ")
 }
 
+// leadingLinesEnd returns the index of the first line from startLine that is not a leading
+// line the header has to go below: a PHP opening tag or configured leading line, or for
+// Rust an inner attribute (#![...], which may span several lines)
+func leadingLinesEnd(cfg *config.Config, ext string, lines []string, startLine int) int {
+	for startLine < len(lines) {
+		switch {
+		case cfg.IsLeadingLine(lines[startLine]):
+			startLine++
+		case ext == ".rs" && strings.HasPrefix(strings.TrimSpace(lines[startLine]), "#!["):
+			startLine = rustAttributeEnd(lines, startLine)
+		default:
+			return startLine
+		}
+	}
+	return startLine
+}
+
+// rustAttributeEnd returns the index of the line after the attribute starting at start,
+// which ends at the line where its brackets balance
+func rustAttributeEnd(lines []string, start int) int {
+	depth := 0
+	for i := start; i < len(lines); i++ {
+		depth += strings.Count(lines[i], "[") - strings.Count(lines[i], "]")
+		if depth <= 0 {
+			return i + 1
+		}
+	}
+	return len(lines)
+}
+
+// headerScanEnd returns the end of the header area that starts at startLine: max_scan_lines
+// lines at most (0 = all lines), plus those of a multi-line copyright and the rest of a header
+// starting on the last of them, and, with header_block_only, no further than the leading
+// comment block
+func headerScanEnd(cfg *config.Config, ext string, lines []string, startLine int) int {
+	maxScan := len(lines)
+	if scanLines := cfg.ScanLines(); scanLines > 0 {
+		maxScan = min(startLine+scanLines, len(lines))
+		// A header is found when it starts within the limit, even if its license line falls
+		// outside it; otherwise fix would add a second license line below the first
+		for maxScan > startLine && maxScan < len(lines) && isHeaderAreaLine(cfg, ext, lines[maxScan-1]) && isHeaderAreaLine(cfg, ext, lines[maxScan]) {
+			maxScan++
+		}
+	}
+	if cfg.Detection.HeaderBlockOnly {
+		maxScan = min(maxScan, headerBlockEnd(cfg, ext, lines, startLine))
+	}
+	return maxScan
+}
+
+// isHeaderAreaLine reports whether line is a copyright or SPDX license line, ours or one
+// to be replaced
+func isHeaderAreaLine(cfg *config.Config, ext, line string) bool {
+	return cfg.IsOwnCopyrightLine(line, ext) || cfg.ShouldReplace(line) || isSPDXHeaderLine(cfg, ext, line)
+}
+
+// headerBlockEnd returns the index of the first line from startLine that is neither blank
+// nor part of a comment, so that copyright text further down (in docstrings, string
+// literals or comments after code) is not mistaken for the header
+func headerBlockEnd(cfg *config.Config, ext string, lines []string, startLine int) int {
+	prefix := cfg.CommentPrefix(ext)
+	style, _ := cfg.BlockComment(ext)
+	blockOpen, blockClose := strings.TrimSpace(style.Open), strings.TrimSpace(style.Close)
+	closeMarker := ""
+	for i := startLine; i < len(lines); i++ {
+		trimmed := strings.TrimSpace(lines[i])
+		switch {
+		case closeMarker != "":
+			if strings.Contains(trimmed, closeMarker) {
+				closeMarker = ""
+			}
+		case strings.HasPrefix(trimmed, "<!--"):
+			if !strings.Contains(trimmed[4:], "-->") {
+				closeMarker = "-->"
+			}
+		case strings.HasPrefix(trimmed, "/*"):
+			if !strings.Contains(trimmed[2:], "*/") {
+				closeMarker = "*/"
+			}
+		case blockOpen != "" && strings.HasPrefix(trimmed, blockOpen):
+			if !strings.Contains(trimmed[len(blockOpen):], blockClose) {
+				closeMarker = blockClose
+			}
+		case trimmed == "", strings.HasPrefix(trimmed, prefix), cfg.IsWrongSyntaxHeaderLine(lines[i], ext):
+		default:
+			return i
+		}
+	}
+	return len(lines)
+}
+
+// runsIntoPackageClause reports whether lines hold only comments up to a Go package clause,
+// with no blank line in between. A header directly above lines would then be read by godoc
+// as (part of) the package doc comment.
+func runsIntoPackageClause(lines []string) bool {
+	inBlock := false
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case inBlock:
+			inBlock = !strings.Contains(trimmed, "*/")
+		case strings.HasPrefix(trimmed, "package "):
+			return true
+		case strings.HasPrefix(trimmed, "//"):
+		case strings.HasPrefix(trimmed, "/*"):
+			inBlock = !strings.Contains(trimmed[2:], "*/")
+		default:
+			return false
+		}
+	}
+	return false
+}
+
 func getFrontmatterEnd(lines []string, cfg *config.Config, file string) int {
 	// Check for compound extensions (e.g., .html.markdown)
 	for _, belowExt := range cfg.Files.BelowFrontmatter {
 		if strings.HasSuffix(file, belowExt) {
-			if len(lines) > 0 && strings.TrimSpace(lines[0]) == "---" {
-				// Find closing ---
-				for i := 1; i < len(lines); i++ {
-					if strings.TrimSpace(lines[i]) == "---" {
-						return i + 1 // Return line after closing ---
-					}
-				}
-			}
-			break
+			return frontmatterEnd(lines, cfg)
 		}
 	}
 	return 0 // No frontmatter or not configured for this extension
@@ -83,13 +781,8 @@ func getFrontmatterEndNew(lines []string, cfg *config.Config, file string) int {
 	// Check new placement_exceptions.frontmatter configuration
 	for _, ext := range cfg.Files.PlacementExceptions.Frontmatter {
 		if strings.HasSuffix(file, ext) {
-			if len(lines) > 0 && strings.TrimSpace(lines[0]) == "---" {
-				// Find closing ---
-				for i := 1; i < len(lines); i++ {
-					if strings.TrimSpace(lines[i]) == "---" {
-						return i + 1 // Return line after closing ---
-					}
-				}
+			if end := frontmatterEnd(lines, cfg); end > 0 {
+				return end
 			}
 			break
 		}
@@ -98,3 +791,58 @@ func getFrontmatterEndNew(lines []string, cfg *config.Config, file string) int {
 	// Fallback to legacy BelowFrontmatter for backward compatibility
 	return getFrontmatterEnd(lines, cfg, file)
 }
+
+// frontmatterEnd returns the line after the frontmatter block lines start with, in one of the
+// configured frontmatter formats, or 0 when there is none or it is never closed. The block
+// may be all there is to the file.
+func frontmatterEnd(lines []string, cfg *config.Config) int {
+	delims, ok := frontmatterDelimiters(lines, cfg)
+	if !ok {
+		return 0
+	}
+	for i := 1; i < len(lines); i++ {
+		if delims.isClose(lines[i]) {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// frontmatterDelims are the lines opening and closing a frontmatter block
+type frontmatterDelims struct {
+	open, close string
+	// indented tells whether the close may be indented; a JSON object's closing brace is
+	// not, so that those of nested objects are passed over
+	indented bool
+}
+
+// isClose reports whether line closes the frontmatter block
+func (d frontmatterDelims) isClose(line string) bool {
+	if d.indented {
+		return strings.TrimSpace(line) == d.close
+	}
+	return strings.TrimRight(line, " \t") == d.close
+}
+
+// frontmatterFormats are the delimiters of each frontmatter format: YAML between "---"
+// lines, TOML between "+++" lines, as in Hugo, and a JSON object
+var frontmatterFormats = map[string]frontmatterDelims{
+	"yaml": {open: "---", close: "---", indented: true},
+	"toml": {open: "+++", close: "+++", indented: true},
+	"json": {open: "{", close: "}"},
+}
+
+// frontmatterDelimiters returns the delimiters of the configured frontmatter format that the
+// first of lines opens, if any
+func frontmatterDelimiters(lines []string, cfg *config.Config) (frontmatterDelims, bool) {
+	if len(lines) == 0 {
+		return frontmatterDelims{}, false
+	}
+	first := strings.TrimSpace(lines[0])
+	for _, format := range cfg.FrontmatterFormats() {
+		if delims, ok := frontmatterFormats[format]; ok && first == delims.open {
+			return delims, true
+		}
+	}
+	return frontmatterDelims{}, false
+}
@@ -0,0 +1,576 @@
+// Copyright IBM Corp. 2014, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package copyright
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/YakDriver/copyplop/internal/config"
+)
+
+func TestGetFilesToProcess_Sorted(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	cfg := &config.Config{
+		Files: config.Files{
+			Extensions: []string{".go"},
+		},
+	}
+
+	for _, name := range []string{"z.go", "m/b.go", "a.go", "m/a.go", "skip.txt"} {
+		filePath := filepath.Join(tmpDir, name)
+		if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filePath, []byte("package main\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	files, err := getFilesToProcess(tmpDir, cfg, false)
+	if err != nil {
+		t.Fatalf("getFilesToProcess() error = %v", err)
+	}
+
+	if len(files) != 4 {
+		t.Fatalf("getFilesToProcess() returned %d files, want 4: %v", len(files), files)
+	}
+
+	if !slices.IsSorted(files) {
+		t.Errorf("getFilesToProcess() order not sorted: %v", files)
+	}
+}
+
+func TestGetGitFiles_OutsideCWD(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	// A repository in a temp dir that is not the current working directory
+	repoDir := t.TempDir()
+	for _, name := range []string{"main.go", "sub/util.go"} {
+		filePath := filepath.Join(repoDir, name)
+		if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filePath, []byte("package main\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(repoDir, "untracked.go"), []byte("package main\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, args := range [][]string{{"init", "-q"}, {"add", "main.go", "sub/util.go"}} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repoDir
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, output)
+		}
+	}
+
+	files, err := getGitFiles(repoDir)
+	if err != nil {
+		t.Fatalf("getGitFiles() error = %v", err)
+	}
+
+	expected := []string{filepath.Join(repoDir, "main.go"), filepath.Join(repoDir, "sub", "util.go")}
+	slices.Sort(files)
+	if !slices.Equal(files, expected) {
+		t.Errorf("getGitFiles() = %v, want %v", files, expected)
+	}
+
+	// A single file path resolves against its own directory
+	files, err = getGitFiles(filepath.Join(repoDir, "sub", "util.go"))
+	if err != nil {
+		t.Fatalf("getGitFiles() error = %v", err)
+	}
+	if !slices.Equal(files, expected[1:]) {
+		t.Errorf("getGitFiles() = %v, want %v", files, expected[1:])
+	}
+}
+
+func TestGetTrackedFiles_GitUnavailable(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	// A temp dir outside any repository
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name        string
+		gitCommand  string
+		fallback    bool
+		expectedErr error
+	}{
+		{name: "git not installed", gitCommand: "copyplop-no-such-git", expectedErr: ErrGitNotFound},
+		{name: "not a repository", gitCommand: "git", expectedErr: ErrNotGitRepository},
+		{name: "git not installed with fallback", gitCommand: "copyplop-no-such-git", fallback: true},
+		{name: "not a repository with fallback", gitCommand: "git", fallback: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			original := gitCommand
+			gitCommand = tt.gitCommand
+			t.Cleanup(func() { gitCommand = original })
+
+			cfg := &config.Config{
+				Files: config.Files{GitTracked: true, GitFallback: tt.fallback},
+			}
+
+			files, err := getTrackedFiles(dir, cfg)
+			if tt.expectedErr != nil {
+				if !errors.Is(err, tt.expectedErr) {
+					t.Fatalf("getTrackedFiles() error = %v, want %v", err, tt.expectedErr)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("getTrackedFiles() error = %v", err)
+			}
+			expected := []string{filepath.Join(dir, "main.go")}
+			if !slices.Equal(files, expected) {
+				t.Errorf("getTrackedFiles() = %v, want %v", files, expected)
+			}
+		})
+	}
+}
+
+func TestGetModifiedFiles(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	repoDir := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		cmd.Dir = repoDir
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, output)
+		}
+	}
+	write := func(name, content string) {
+		t.Helper()
+		filePath := filepath.Join(repoDir, name)
+		if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, name := range []string{"clean.go", "modified.go", "deleted.go", "sub/modified.go", "sub/clean.go"} {
+		write(name, "package main\n")
+	}
+	git("init", "-q")
+	git("add", ".")
+	git("commit", "-q", "-m", "initial")
+
+	write("modified.go", "package main\n\nfunc main() {}\n")
+	write("sub/modified.go", "package sub\n")
+	write("untracked.go", "package main\n")
+	write("sub/new/untracked.go", "package new\n")
+	write("staged.go", "package main\n")
+	git("add", "staged.go")
+	if err := os.Remove(filepath.Join(repoDir, "deleted.go")); err != nil {
+		t.Fatal(err)
+	}
+
+	files, err := getModifiedFiles(repoDir)
+	if err != nil {
+		t.Fatalf("getModifiedFiles() error = %v", err)
+	}
+	slices.Sort(files)
+
+	expected := []string{
+		filepath.Join(repoDir, "modified.go"),
+		filepath.Join(repoDir, "staged.go"),
+		filepath.Join(repoDir, "sub", "modified.go"),
+		filepath.Join(repoDir, "sub", "new", "untracked.go"),
+		filepath.Join(repoDir, "untracked.go"),
+	}
+	if !slices.Equal(files, expected) {
+		t.Errorf("getModifiedFiles() = %v, want %v", files, expected)
+	}
+
+	// A subdirectory only reports its own files, relative to the subdirectory
+	files, err = getModifiedFiles(filepath.Join(repoDir, "sub"))
+	if err != nil {
+		t.Fatalf("getModifiedFiles() error = %v", err)
+	}
+	slices.Sort(files)
+	if !slices.Equal(files, expected[2:4]) {
+		t.Errorf("getModifiedFiles() = %v, want %v", files, expected[2:4])
+	}
+
+	// Selection is intersected with the configured extensions
+	cfg := &config.Config{Files: config.Files{Extensions: []string{".go"}}}
+	write("notes.txt", "dirty\n")
+	files, err = getFilesToProcess(repoDir, cfg, true)
+	if err != nil {
+		t.Fatalf("getFilesToProcess() error = %v", err)
+	}
+	if !slices.Equal(files, expected) {
+		t.Errorf("getFilesToProcess() = %v, want %v", files, expected)
+	}
+}
+
+func TestFixer_Staged(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	repoDir := t.TempDir()
+	git := func(args ...string) string {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		cmd.Dir = repoDir
+		output, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, output)
+		}
+		return string(output)
+	}
+	write := func(name, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(repoDir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, name := range []string{"committed.go", "partial.go", "deleted.go"} {
+		write(name, "package main\n")
+	}
+	git("init", "-q")
+	git("add", ".")
+	git("commit", "-q", "-m", "initial")
+
+	// staged.go is staged whole; partial.go also has a change that is not staged
+	write("staged.go", "package main\n")
+	write("partial.go", "package main\n\nfunc a() {}\n")
+	write("unstaged.go", "package main\n")
+	git("add", "staged.go", "partial.go")
+	write("partial.go", "package main\n\nfunc a() {}\n\nfunc b() {}\n")
+	git("rm", "-q", "deleted.go")
+
+	cfg := &config.Config{
+		Copyright: config.Copyright{
+			Holder:      "IBM Corp.",
+			StartYear:   2014,
+			CurrentYear: 2025,
+			Format:      "Copyright {{.Holder}} {{.StartYear}}, {{.CurrentYear}}",
+		},
+		Files: config.Files{
+			Extensions:    []string{".go"},
+			CommentStyles: map[string]string{"go": "//"},
+		},
+	}
+
+	fixer := NewFixer(cfg)
+	fixer.Staged = true
+	result, err := fixer.Fix(context.Background(), repoDir)
+	if err != nil {
+		t.Fatalf("Fix() error = %v", err)
+	}
+
+	expected := []string{filepath.Join(repoDir, "partial.go"), filepath.Join(repoDir, "staged.go")}
+	if !slices.Equal(result.Changed, expected) {
+		t.Errorf("Fix() changed %v, want %v", result.Changed, expected)
+	}
+	if !slices.Equal(result.Unstaged, expected[:1]) {
+		t.Errorf("Fix() left %v unstaged, want %v", result.Unstaged, expected[:1])
+	}
+
+	// The fixed staged.go is staged again, while partial.go keeps the header unstaged
+	if got := git("diff", "--name-only"); got != "partial.go\n" {
+		t.Errorf("unstaged changes = %q, want partial.go", got)
+	}
+	if got := git("show", ":staged.go"); !strings.HasPrefix(got, "// Copyright IBM Corp. 2014, 2025") {
+		t.Errorf("staged.go in the index = %q, want the fixed header", got)
+	}
+
+	content, err := os.ReadFile(filepath.Join(repoDir, "unstaged.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != "package main\n" {
+		t.Errorf("unstaged.go was fixed: %q", string(content))
+	}
+}
+
+func TestFixer_StartYearFromGit(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	repoDir := t.TempDir()
+	git := func(env []string, args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		cmd.Dir = repoDir
+		cmd.Env = append(os.Environ(), env...)
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, output)
+		}
+	}
+	write := func(name string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(repoDir, name), []byte("package main\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// old.go was added in 2019 and renamed to renamed.go in 2022; new.go is untracked
+	date := func(year string) []string {
+		return []string{"GIT_AUTHOR_DATE=" + year + "-06-01T12:00:00Z", "GIT_COMMITTER_DATE=" + year + "-06-01T12:00:00Z"}
+	}
+	write("old.go")
+	git(nil, "init", "-q")
+	git(nil, "add", "old.go")
+	git(date("2019"), "commit", "-q", "-m", "add")
+	git(nil, "mv", "old.go", "renamed.go")
+	git(date("2022"), "commit", "-q", "-m", "rename")
+	write("new.go")
+
+	cfg := &config.Config{
+		Copyright: config.Copyright{
+			Holder:           "IBM Corp.",
+			StartYear:        2014,
+			CurrentYear:      2025,
+			Format:           "Copyright {{.Holder}} {{.StartYear}}, {{.CurrentYear}}",
+			StartYearFromGit: true,
+		},
+		Files: config.Files{
+			Extensions:    []string{".go"},
+			CommentStyles: map[string]string{"go": "//"},
+		},
+		Detection: config.Detection{
+			MaxScanLines: 20,
+		},
+	}
+
+	fixer := NewFixer(cfg)
+	fixer.Verify = true
+	result, err := fixer.Fix(context.Background(), repoDir)
+	if err != nil {
+		t.Fatalf("Fix() error = %v", err)
+	}
+	if result.Added != 2 || len(result.Unverified) != 0 {
+		t.Errorf("Fix() = %+v, want 2 verified fixes", result)
+	}
+
+	for name, want := range map[string]string{
+		"renamed.go": "// Copyright IBM Corp. 2019, 2025\n\npackage main\n",
+		"new.go":     "// Copyright IBM Corp. 2014, 2025\n\npackage main\n",
+	} {
+		content, err := os.ReadFile(filepath.Join(repoDir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(content) != want {
+			t.Errorf("%s = %q, want %q", name, string(content), want)
+		}
+	}
+}
+
+func TestWriteFileAtomic(t *testing.T) {
+	tmpDir := t.TempDir()
+	file := filepath.Join(tmpDir, "script.sh")
+	if err := os.WriteFile(file, []byte("#!/bin/sh\necho old\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := writeFileAtomic(file, []byte("#!/bin/sh\necho new\n")); err != nil {
+		t.Fatalf("writeFileAtomic() error = %v", err)
+	}
+
+	content, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != "#!/bin/sh\necho new\n" {
+		t.Errorf("content = %q", string(content))
+	}
+
+	info, err := os.Stat(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0755 {
+		t.Errorf("mode = %v, want 0755", info.Mode().Perm())
+	}
+
+	entries, err := os.ReadDir(tmpDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("expected only the written file, found %d entries", len(entries))
+	}
+}
+
+func TestWriteFileAtomic_Symlink(t *testing.T) {
+	tmpDir := t.TempDir()
+	target := filepath.Join(tmpDir, "target.go")
+	link := filepath.Join(tmpDir, "link.go")
+	if err := os.WriteFile(target, []byte("package main\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(target, link); err != nil {
+		t.Skipf("symlinks not supported: %v", err)
+	}
+
+	if err := writeFileAtomic(link, []byte("// header\npackage main\n")); err != nil {
+		t.Fatalf("writeFileAtomic() error = %v", err)
+	}
+
+	if info, err := os.Lstat(link); err != nil || info.Mode()&os.ModeSymlink == 0 {
+		t.Errorf("link.go is no longer a symlink")
+	}
+	content, err := os.ReadFile(target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != "// header\npackage main\n" {
+		t.Errorf("target content = %q", string(content))
+	}
+}
+
+func TestGetFilesToProcess_SkipExecutable(t *testing.T) {
+	dir := t.TempDir()
+	regular := filepath.Join(dir, "main.go")
+	script := filepath.Join(dir, "build.sh")
+	if err := os.WriteFile(regular, []byte("package main\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(script, []byte("#!/bin/sh\necho hi\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name           string
+		skipExecutable bool
+		expected       []string
+	}{
+		{name: "default", expected: []string{script, regular}},
+		{name: "skip executable", skipExecutable: true, expected: []string{regular}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{
+				Files: config.Files{
+					Extensions:     []string{".go", ".sh"},
+					SkipExecutable: tt.skipExecutable,
+				},
+			}
+
+			files, err := getFilesToProcess(dir, cfg, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(files, tt.expected) {
+				t.Errorf("getFilesToProcess() = %v, want %v", files, tt.expected)
+			}
+		})
+	}
+}
+
+func TestListFiles(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"main.go", "README.txt", "vendor/lib.go", "build.sh"} {
+		file := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+			t.Fatal(err)
+		}
+		perm := os.FileMode(0644)
+		if strings.HasSuffix(name, ".sh") {
+			perm = 0755
+		}
+		if err := os.WriteFile(file, []byte("x\n"), perm); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cfg := &config.Config{
+		Files: config.Files{
+			Extensions:     []string{".go", ".sh"},
+			ExcludePaths:   []string{"**/vendor/**"},
+			SkipExecutable: true,
+		},
+	}
+
+	files, err := ListFiles(dir, cfg, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []ListedFile{
+		{File: filepath.Join(dir, "README.txt"), SkipReason: "extension not in files.extensions or files.smart_extensions"},
+		{File: filepath.Join(dir, "build.sh"), SkipReason: "executable (files.skip_executable)"},
+		{File: filepath.Join(dir, "main.go")},
+		{File: filepath.Join(dir, "vendor/lib.go"), SkipReason: `matches files.exclude_paths pattern "**/vendor/**"`},
+	}
+	if !slices.Equal(files, expected) {
+		t.Errorf("ListFiles() = %v, want %v", files, expected)
+	}
+
+	cfg.Files.SkipExecutable = false
+	cfg.Files.IncludePaths = []string{"**/*.go"}
+	files, err = ListFiles(dir, cfg, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected[1].SkipReason = "matches no files.include_paths pattern"
+	if !slices.Equal(files, expected) {
+		t.Errorf("ListFiles() = %v, want %v", files, expected)
+	}
+}
+
+func TestHeaderScanEnd(t *testing.T) {
+	tests := []struct {
+		name            string
+		ext             string
+		content         string
+		maxScanLines    int
+		headerBlockOnly bool
+		expected        int
+	}{
+		{name: "scan limit", ext: ".go", content: "// a\npackage main\n\nfunc f() {}\n", maxScanLines: 3, expected: 3},
+		{name: "no limit", ext: ".go", content: "// a\npackage main\n\nfunc f() {}\n", expected: 5},
+		{name: "stops at code", ext: ".go", content: "// a\n\n// b\npackage main\n// c\n", maxScanLines: 20, headerBlockOnly: true, expected: 3},
+		{name: "scan limit inside block", ext: ".go", content: "// a\n// b\n// c\npackage main\n", maxScanLines: 2, headerBlockOnly: true, expected: 2},
+		{name: "block comment", ext: ".js", content: "/*\n Copyright\n*/\nfunction f() {}\n", maxScanLines: 20, headerBlockOnly: true, expected: 3},
+		{name: "html comment", ext: ".md", content: "<!--\nCopyright\n-->\n# Title\n", maxScanLines: 20, headerBlockOnly: true, expected: 3},
+		{name: "hash comments", ext: ".py", content: "# a\n# b\nimport os\n", maxScanLines: 20, headerBlockOnly: true, expected: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{
+				Files:     config.Files{CommentStyles: map[string]string{"go": "//", "js": "//", "py": "#", "md": "<!--"}},
+				Detection: config.Detection{MaxScanLines: tt.maxScanLines, HeaderBlockOnly: tt.headerBlockOnly},
+			}
+			lines := strings.Split(tt.content, "\n")
+			if got := headerScanEnd(cfg, tt.ext, lines, 0); got != tt.expected {
+				t.Errorf("headerScanEnd() = %d, want %d", got, tt.expected)
+			}
+		})
+	}
+}
@@ -4,136 +4,557 @@
 package copyright
 
 import (
-	"os"
-	"path/filepath"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
 	"regexp"
+	"slices"
 	"strings"
+	"sync/atomic"
+	"time"
+	"unicode"
 
 	"github.com/YakDriver/copyplop/internal/config"
-	"github.com/schollz/progressbar/v3"
 )
 
 // isBlockCommentStyle returns true if the comment style requires wrapping
 func isBlockCommentStyle(cfg *config.Config, ext string) bool {
-	extKey := strings.TrimPrefix(ext, ".")
-	extKey = strings.ReplaceAll(extKey, ".", "_")
-	prefix := cfg.Files.CommentStyles[extKey]
-	return prefix == "/**"
+	_, ok := cfg.BlockComment(ext)
+	return ok
+}
+
+// isBlockDelimiter reports whether trimmed is a line opening or closing a /** */ comment or
+// the block comment style of ext
+func isBlockDelimiter(cfg *config.Config, ext, trimmed string) bool {
+	if trimmed == "/**" || trimmed == "*/" {
+		return true
+	}
+	style, ok := cfg.BlockComment(ext)
+	return ok && (trimmed == strings.TrimSpace(style.Open) || trimmed == strings.TrimSpace(style.Close))
+}
+
+// headerBlock returns the header lines to insert, skipping empty (disabled) headers and
+// wrapping them in the block comment delimiters when the extension uses block comments. A
+// header of several lines, such as a multi-line copyright, gives one entry per line.
+func headerBlock(cfg *config.Config, ext string, headers ...string) []string {
+	style, isBlock := cfg.BlockComment(ext)
+	var block []string
+	if isBlock {
+		block = append(block, style.Open)
+	}
+	for _, header := range headers {
+		if header != "" {
+			block = append(block, strings.Split(header, "\n")...)
+		}
+	}
+	if isBlock {
+		block = append(block, style.Close)
+	}
+	return block
 }
 
 type Fixer struct {
 	config *config.Config
+
+	// Limit stops Fix after this many files have been modified (0 = no limit)
+	Limit int
+
+	// Modified restricts processing to files modified or untracked in the git working tree
+	Modified bool
+
+	// Staged restricts processing to files staged in the git index and stages the files it
+	// changes again, so that a pre-commit hook commits the fixed headers
+	Staged bool
+
+	// FailFast stops Fix at the first file that cannot be fixed and returns its error;
+	// otherwise such files are collected in FixResult.Errors and the rest are fixed
+	FailFast bool
+
+	// Force rebuilds the header area even when the header is already correct, normalizing
+	// its layout; files are only written when the rebuilt content differs
+	Force bool
+
+	// DryRun reports which files would change without writing them
+	DryRun bool
+
+	// Verify checks each fixed file afterwards and records those the checker still reports
+	Verify bool
+
+	// Diff, when set, receives a unified diff of each change, including those of a dry run, in
+	// file order once all files have been processed
+	Diff io.Writer
+
+	// Jobs is the number of files fixed concurrently (0 = one per CPU); with Limit or FailFast
+	// files are fixed one at a time
+	Jobs int
+
+	// Quiet suppresses the progress bar
+	Quiet bool
 }
 
 func NewFixer(cfg *config.Config) *Fixer {
 	return &Fixer{config: cfg}
 }
 
-// isSPDXHeaderLine detects SPDX-License-Identifier lines that are in comment format
-func isSPDXHeaderLine(line, commentPrefix string) bool {
+var (
+	// spdxTagPattern matches an SPDX license tag at the start of a comment, tolerating any
+	// casing and spacing around the colon, e.g. "spdx-license-identifier : MIT"
+	spdxTagPattern = regexp.MustCompile(`(?i)^spdx-license-identifier\s*:|SPDX-License-Identifier:`)
+	// spdxIDPattern finds the tag anywhere in a line, ahead of the license expression
+	spdxIDPattern = regexp.MustCompile(`(?i)spdx-license-identifier\s*:`)
+	// spdxColonPattern matches the SPDX tag with whatever spacing around its colon
+	spdxColonPattern = regexp.MustCompile(`SPDX-License-Identifier\s*:\s*`)
+)
+
+// normalizeSPDXColon writes the colon of an SPDX tag in line as ": ", so that
+// "SPDX-License-Identifier:MIT" and "SPDX-License-Identifier : MIT" read as the same line
+func normalizeSPDXColon(line string) string {
+	return spdxColonPattern.ReplaceAllString(line, "SPDX-License-Identifier: ")
+}
+
+// isSameLicenseLine compares a line against the license header line as isSameHeaderLine
+// does, also ignoring the spacing around the colon of an SPDX tag
+func isSameLicenseLine(line, license string) bool {
+	return isSameHeaderLine(normalizeSPDXColon(line), normalizeSPDXColon(license))
+}
+
+// keepMarker marks a line to be left as it is, even one matching replace_patterns, when
+// written on the line or in a comment of its own right above it
+const keepMarker = "copyplop:keep"
+
+// isKeptLine reports whether lines[i] is marked with keepMarker, so that it is not taken for
+// a header line and never removed or rewritten
+func isKeptLine(lines []string, i int) bool {
+	if strings.Contains(lines[i], keepMarker) {
+		return true
+	}
+	if i == 0 {
+		return false
+	}
+	before, after, found := strings.Cut(lines[i-1], keepMarker)
+	if !found {
+		return false
+	}
+	// Nothing but the comment delimiters may surround the marker
+	isText := func(r rune) bool { return unicode.IsLetter(r) || unicode.IsDigit(r) }
+	return !strings.ContainsFunc(before+after, isText)
+}
+
+// isSPDXHeaderLine detects SPDX-License-Identifier lines in the comment format of ext
+func isSPDXHeaderLine(cfg *config.Config, ext, line string) bool {
 	var content string
+	trimmed := strings.TrimSpace(line)
 
-	// Handle block comment style - don't trim spaces first
-	if commentPrefix == "/**" {
-		if after, ok := strings.CutPrefix(line, " * "); ok {
-			content = strings.TrimSpace(after)
-		} else {
+	// Handle block comment style, where lines continue with e.g. " * " (or "*" when unspaced)
+	if style, ok := cfg.BlockComment(ext); ok {
+		closeMarker := strings.TrimSpace(style.Close)
+		after, ok := strings.CutPrefix(trimmed, strings.TrimSpace(style.Continuation))
+		if !ok || (closeMarker != "" && strings.HasPrefix(trimmed, closeMarker)) {
 			return false
 		}
+		content = strings.TrimSpace(after)
 	} else {
-		trimmed := strings.TrimSpace(line)
-		if after, ok := strings.CutPrefix(trimmed, commentPrefix); ok {
-			// Remove comment prefix and check for SPDX pattern
-			content = strings.TrimSpace(after)
-		} else {
+		after, ok := strings.CutPrefix(trimmed, cfg.CommentPrefix(ext))
+		if !ok {
 			return false
 		}
+		// Remove comment prefix and check for SPDX pattern
+		content = strings.TrimSpace(after)
+	}
+
+	return spdxTagPattern.MatchString(content)
+}
+
+// spdxLicenseID returns the license expression of an SPDX line, without quotes or a
+// trailing comment delimiter
+func spdxLicenseID(line string) string {
+	loc := spdxIDPattern.FindStringIndex(line)
+	if loc == nil {
+		return ""
+	}
+	id := strings.TrimSpace(line[loc[1]:])
+	id = strings.TrimSpace(strings.TrimSuffix(strings.TrimSuffix(id, "-->"), "*/"))
+	return strings.Trim(id, `"`)
+}
+
+// isSameHeaderLine compares a line against a canonical header line, ignoring
+// differences in surrounding and internal whitespace (e.g. tabs between tokens)
+func isSameHeaderLine(line, header string) bool {
+	return normalizeWhitespace(line) == normalizeWhitespace(header)
+}
+
+// hasHeaderLines reports whether lines start with the lines of header, compared as by
+// isSameHeaderLine
+func hasHeaderLines(lines, header []string) bool {
+	if len(lines) < len(header) {
+		return false
+	}
+	for i, h := range header {
+		if !isSameHeaderLine(lines[i], h) {
+			return false
+		}
+	}
+	return true
+}
+
+// isAnyHeaderLine reports whether line is the same header line as one of headers
+func isAnyHeaderLine(line string, headers []string) bool {
+	return slices.ContainsFunc(headers, func(header string) bool {
+		return isSameHeaderLine(line, header)
+	})
+}
+
+// copyrightFillerLines returns the lines of a multi-line copyright header that do not identify
+// it, i.e. the comments without text that blank lines of the format render as
+func copyrightFillerLines(cfg *config.Config, ext string, copyrightLines []string) []string {
+	if len(copyrightLines) < 2 {
+		return nil
+	}
+	var filler []string
+	for _, line := range copyrightLines {
+		if !cfg.IsOwnCopyrightLine(line, ext) {
+			filler = append(filler, line)
+		}
+	}
+	return filler
+}
+
+// isOwnBlockFiller reports whether lines[i] is a blank line of a multi-line copyright format
+// between two lines of our copyright, as in a block from an earlier year
+func isOwnBlockFiller(cfg *config.Config, ext string, lines []string, i int, fillerLines []string) bool {
+	if i == 0 || !isAnyHeaderLine(lines[i], fillerLines) {
+		return false
+	}
+	prev, next := i-1, i+1
+	for prev > 0 && isAnyHeaderLine(lines[prev], fillerLines) {
+		prev--
+	}
+	for next < len(lines) && isAnyHeaderLine(lines[next], fillerLines) {
+		next++
+	}
+	return next < len(lines) && cfg.IsOwnCopyrightLine(lines[prev], ext) && cfg.IsOwnCopyrightLine(lines[next], ext)
+}
+
+// updateYearOnly returns line with its last year updated when update_year_only applies to it:
+// a single-line copyright format and a copyright line naming the holder
+func updateYearOnly(cfg *config.Config, ext, line string, multiLine bool) (string, bool) {
+	if !cfg.Copyright.UpdateYearOnly || multiLine {
+		return "", false
+	}
+	return cfg.UpdateCopyrightYear(line, ext)
+}
+
+// countNonEmpty returns how many of values are not empty
+func countNonEmpty(values ...string) int {
+	n := 0
+	for _, v := range values {
+		if v != "" {
+			n++
+		}
+	}
+	return n
+}
+
+// normalizeWhitespace trims a line and collapses internal runs of whitespace to a single space
+func normalizeWhitespace(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// splitHTMLCommentHeaders rewrites <!-- --> comments in lines[start:end] that hold our header
+// lines into one self-contained "<!-- ... -->" line per header line. Existing headers may put
+// the closing delimiter on its own line, wrap several lines in one comment or have content
+// after "-->", none of which line-exact matching recognizes. Content after the closing
+// delimiter is kept on its own line. Returns the new lines and whether anything changed.
+func splitHTMLCommentHeaders(cfg *config.Config, ext string, lines []string, start, end int) ([]string, bool) {
+	if cfg.CommentPrefix(ext) != "<!--" {
+		return lines, false
+	}
+
+	isHeaderContent := func(content string) bool {
+		line := "<!-- " + content + " -->"
+		return cfg.IsOwnCopyrightLine(line, ext) || cfg.ShouldReplace(content) || isSPDXHeaderLine(cfg, ext, line)
+	}
+
+	out := append([]string{}, lines[:start]...)
+	changed := false
+	for i := start; i < end; {
+		rest, ok := strings.CutPrefix(strings.TrimSpace(lines[i]), "<!--")
+		if !ok {
+			out = append(out, lines[i])
+			i++
+			continue
+		}
+
+		// Collect the comment body up to the closing delimiter
+		var body []string
+		after := ""
+		closeLine := -1
+		for j := i; j < end; j++ {
+			text := rest
+			if j > i {
+				text = strings.TrimSpace(lines[j])
+			}
+			if before, tail, found := strings.Cut(text, "-->"); found {
+				body = append(body, strings.TrimSpace(before))
+				after = strings.TrimSpace(tail)
+				closeLine = j
+				break
+			}
+			body = append(body, strings.TrimSpace(text))
+		}
+
+		// Unterminated comments and well-formed single-line comments are left as they are
+		if closeLine < 0 || (closeLine == i && after == "") || !slices.ContainsFunc(body, isHeaderContent) {
+			out = append(out, lines[i])
+			i++
+			continue
+		}
+
+		for _, content := range body {
+			if content != "" {
+				out = append(out, "<!-- "+content+" -->")
+			}
+		}
+		if after != "" {
+			out = append(out, after)
+		}
+		changed = true
+		i = closeLine + 1
 	}
 
-	spdxPattern := `SPDX-License-Identifier:\s*"?[^"]*"?`
-	matched, _ := regexp.MatchString(spdxPattern, content)
-	return matched
+	if !changed {
+		return lines, false
+	}
+	return append(out, lines[end:]...), true
+}
+
+// Fix adds or updates headers in the files under path. It stops between files when ctx is
+// cancelled, returning the results so far along with the context's error.
+func (f *Fixer) Fix(ctx context.Context, path string) (*FixResult, error) {
+	if f.Staged {
+		return f.fixStaged(ctx, path)
+	}
+	filesToProcess, err := getFilesToProcess(path, f.config, f.Modified)
+	if err != nil {
+		return nil, err
+	}
+	return f.fixFiles(ctx, filesToProcess)
 }
 
-func (f *Fixer) Fix(path string) (*FixResult, error) {
-	files, err := getTrackedFiles(path, f.config)
+// fixStaged fixes the files under path staged in the git index and stages those it changed.
+// A file that also has unstaged changes is left for the user to stage, since adding it would
+// commit those changes too.
+func (f *Fixer) fixStaged(ctx context.Context, path string) (*FixResult, error) {
+	files, err := getStagedFiles(path)
+	if err != nil {
+		return nil, err
+	}
+	unstaged, err := getUnstagedFiles(path)
 	if err != nil {
 		return nil, err
 	}
 
-	// Filter files to process
-	var filesToProcess []string
-	for _, file := range files {
-		if f.config.ShouldProcess(file) {
-			filesToProcess = append(filesToProcess, file)
+	result, err := f.fixFiles(ctx, filterFiles(files, f.config))
+	if err != nil || f.DryRun {
+		return result, err
+	}
+
+	var stage []string
+	for _, file := range result.Changed {
+		if slices.Contains(unstaged, file) {
+			result.Unstaged = append(result.Unstaged, file)
+		} else {
+			stage = append(stage, file)
 		}
 	}
+	return result, stageFiles(path, stage)
+}
+
+// FixFiles fixes the given files, e.g. those a pre-commit hook passes, skipping any that the
+// config does not select for processing
+func (f *Fixer) FixFiles(ctx context.Context, files []string) (*FixResult, error) {
+	return f.fixFiles(ctx, filterFiles(files, f.config))
+}
 
+func (f *Fixer) fixFiles(ctx context.Context, filesToProcess []string) (*FixResult, error) {
 	if len(filesToProcess) == 0 {
 		return &FixResult{}, nil
 	}
 
-	bar := progressbar.Default(int64(len(filesToProcess)), "Fixing files")
-	result := &FixResult{}
+	bar := newProgressBar(len(filesToProcess), "Fixing files", f.Quiet)
+	checker := NewChecker(f.config)
+
+	// A limit or fail-fast stops at an exact file, so files are then fixed one at a time
+	jobs := f.Jobs
+	if f.Limit > 0 || f.FailFast {
+		jobs = 1
+	}
+
+	outcomes := make([]fixOutcome, len(filesToProcess))
+	var fixed atomic.Int64
+	ctxErr := forEachFile(ctx, filesToProcess, jobs, bar, func(i int, file string) bool {
+		if f.Limit > 0 && int(fixed.Load()) >= f.Limit {
+			return false
+		}
+		outcome := &outcomes[i]
+		outcome.change, outcome.err = f.applyHeader(file, file)
+		if outcome.err != nil && f.FailFast {
+			return false
+		}
+		if outcome.change.header != nil {
+			fixed.Add(1)
+			outcome.time = time.Now()
+			if f.Verify && !f.DryRun {
+				outcome.unverified = checker.checkFile(file)
+			}
+		}
+		return true
+	})
 
-	for _, file := range filesToProcess {
-		if f.fixFile(file) {
+	// Results and diffs are assembled in file order whichever worker fixed each file
+	result := &FixResult{}
+	for i, outcome := range outcomes {
+		file := filesToProcess[i]
+		if outcome.change.diff != "" {
+			fmt.Fprint(f.Diff, outcome.change.diff)
+		}
+		if outcome.err != nil {
+			if f.FailFast {
+				return result, fmt.Errorf("%s: %w", file, outcome.err)
+			}
+			result.Errors = append(result.Errors, FileError{File: file, Err: outcome.err})
+			continue
+		}
+		if outcome.change.header == nil {
+			continue
+		}
+		if outcome.change.added {
+			result.Added++
+		} else {
 			result.Fixed++
 		}
-		_ = bar.Add(1)
+		result.Changed = append(result.Changed, file)
+		result.Applied = append(result.Applied, AppliedHeader{
+			File:   file,
+			Header: strings.Join(outcome.change.header, "\n"),
+			Time:   outcome.time,
+		})
+		if outcome.unverified != nil {
+			result.Unverified = append(result.Unverified, *outcome.unverified)
+		}
 	}
 
-	return result, nil
+	return result, ctxErr
+}
+
+// fixOutcome is what fixing one file produced
+type fixOutcome struct {
+	change     headerChange
+	err        error
+	time       time.Time
+	unverified *Issue
+}
+
+// fixFile adds or updates the header in file and reports whether the file was changed.
+// Files that are skipped (generated, binary) are not an error.
+func (f *Fixer) fixFile(file string) (bool, error) {
+	return f.fixFileAt(file, file)
 }
 
-func (f *Fixer) fixFile(file string) bool {
-	content, err := os.ReadFile(file)
+// fixFileAt is fixFile for content stored at path: file decides extension, placement and
+// path filters while path is read and written, so a copy can be fixed as if it were file
+func (f *Fixer) fixFileAt(file, path string) (bool, error) {
+	change, err := f.applyHeader(file, path)
+	return change.header != nil, err
+}
+
+// headerChange is what applyHeader did to a file
+type headerChange struct {
+	header []string // the header block written to the file, nil when it was left unchanged
+	added  bool     // the file had no header of ours before, in any form
+	diff   string   // the unified diff of the change, with Fixer.Diff
+}
+
+// applyHeader is fixFileAt returning the change made to the file
+func (f *Fixer) applyHeader(file, path string) (headerChange, error) {
+	if isNotebook(file) {
+		return f.fixNotebook(file, path)
+	}
+
+	// Only the header area is held in memory; the rest of a large file is streamed on write
+	head, err := readFileHead(path, f.headCoversHeaderArea(file))
 	if err != nil {
-		return false
+		return headerChange{}, err
 	}
 
-	lines := strings.Split(string(content), "\n")
-	if len(lines) == 0 || f.config.IsGenerated(lines) {
-		return false
+	lines := head.lines
+	if len(lines) == 0 || f.config.IsSkippedContent(lines) {
+		return headerChange{}, nil
 	}
 
-	// Get extension, handling compound extensions like .html.markdown
-	ext := filepath.Ext(file)
-	for _, validExt := range f.config.Files.Extensions {
-		if strings.HasSuffix(file, validExt) && len(validExt) > len(ext) {
-			ext = validExt
-			break
+	ext, isSmartExt, ok := resolveExtension(f.config, file, []byte(strings.Join(lines, "\n")))
+	if isSmartExt && !head.complete {
+		// Content detection looks at the whole file
+		head, err = readFileHead(path, func([]string) bool { return false })
+		if err != nil {
+			return headerChange{}, err
 		}
+		lines = head.lines
+		ext, _, ok = resolveExtension(f.config, file, []byte(strings.Join(lines, "\n")))
+	}
+	if !ok {
+		// Binary file detected - skip processing
+		return headerChange{}, nil
 	}
 
-	// Check for smart extensions and detect actual content type
-	isSmartExt := false
-	for _, smartExt := range f.config.Files.SmartExtensions {
-		if strings.HasSuffix(file, smartExt) && len(smartExt) >= len(ext) {
-			ext = smartExt
-			isSmartExt = true
-			break
-		}
+	// Shebang scripts without a configured comment style use the interpreter's style
+	cfg := f.config
+	if hasShebang(lines) {
+		cfg = cfg.ForShebang(lines[0], ext)
 	}
 
-	// For smart extensions, detect the actual file type from content
-	if isSmartExt {
-		detectedExt := f.config.DetectSmartExtensionType(content, file)
-		if detectedExt == "" {
-			// Binary file detected - skip processing
-			return false
-		}
-		ext = detectedExt
+	cfg, skip := forMinified(cfg, ext, lines)
+	if skip {
+		return headerChange{}, nil
 	}
+	cfg = withGitStartYear(cfg, file)
 
-	copyrightHeader, err := f.config.GetCopyrightHeader(ext)
+	copyrightHeader, err := cfg.GetCopyrightHeader(ext)
 	if err != nil {
-		return false
+		return headerChange{}, err
 	}
 
-	licenseHeader, err := f.config.GetLicenseHeader(ext)
+	copyrightVariants, err := cfg.CopyrightHeaderVariants(ext)
 	if err != nil {
-		return false
+		return headerChange{}, err
+	}
+
+	licenseHeader, err := cfg.GetLicenseHeader(ext)
+	if err != nil {
+		return headerChange{}, err
+	}
+
+	noticeHeader, err := cfg.GetNoticeHeader(ext)
+	if err != nil {
+		return headerChange{}, err
+	}
+
+	bannerBefore, bannerAfter := cfg.GetBannerLines(ext)
+	wantBanners := countNonEmpty(bannerBefore, bannerAfter)
+	header := headerBlock(cfg, ext, bannerBefore, copyrightHeader, licenseHeader, noticeHeader, bannerAfter)
+
+	// A multi-line copyright header is recognized and replaced as a whole block
+	copyrightLines := strings.Split(copyrightHeader, "\n")
+	multiLine := len(copyrightLines) > 1
+	fillerLines := copyrightFillerLines(cfg, ext, copyrightLines)
+
+	// Headers as current as the canonical one, e.g. "2025, 2025" with collapse_years, are kept
+	currentCopyright := append([]string{copyrightHeader}, copyrightVariants...)
+	isCurrentCopyrightAt := func(i, end int) bool {
+		return slices.ContainsFunc(currentCopyright, func(header string) bool {
+			return hasHeaderLines(lines[i:end], strings.Split(header, "\n"))
+		})
 	}
 
 	var result []string
@@ -142,18 +563,33 @@ func (f *Fixer) fixFile(file string) bool {
 	hasCopyright := false
 	thirdPartyLines := []string{}
 
-	// Handle shebang (always)
+	// Handle shebang (always), optionally followed by a blank line before the header
+	missingShebangBlank := false
 	if hasShebang(lines) {
 		result = append(result, lines[0])
 		startLine = 1
+		if cfg.Files.BlankAfterShebang {
+			result = append(result, "")
+			if startLine < len(lines) && strings.TrimSpace(lines[startLine]) == "" {
+				startLine++
+			} else {
+				missingShebangBlank = true
+			}
+		}
 	}
 
 	// Handle XML declaration
-	if startLine < len(lines) && f.config.Files.PlacementExceptions.XMLDeclaration && hasXMLDeclaration(lines[startLine:]) {
+	if startLine < len(lines) && cfg.Files.PlacementExceptions.XMLDeclaration && hasXMLDeclaration(lines[startLine:]) {
 		result = append(result, lines[startLine])
 		startLine++
 	}
 
+	// Handle leading lines such as a PHP opening tag or Rust inner attributes
+	if end := leadingLinesEnd(cfg, ext, lines, startLine); end > startLine {
+		result = append(result, lines[startLine:end]...)
+		startLine = end
+	}
+
 	// Handle frontmatter - use detected extension for smart extensions
 	frontmatterFile := file
 	if isSmartExt {
@@ -161,96 +597,245 @@ func (f *Fixer) fixFile(file string) bool {
 		// Use a filename that will match the BelowFrontmatter config
 		frontmatterFile = "dummy" + ext
 	}
-	frontmatterEnd := getFrontmatterEndNew(lines, f.config, frontmatterFile)
+	frontmatterEnd := getFrontmatterEndNew(lines, cfg, frontmatterFile)
+	missingFrontmatterBlank := false
 	if frontmatterEnd > startLine {
 		// Add frontmatter to result
 		result = append(result, lines[startLine:frontmatterEnd]...)
 		startLine = frontmatterEnd
+
+		// Optionally separate the header from the frontmatter with a blank line
+		if cfg.Files.PlacementExceptions.BlankLineAfterFrontmatter {
+			result = append(result, "")
+			if startLine < len(lines) && strings.TrimSpace(lines[startLine]) == "" {
+				startLine++
+			} else {
+				missingFrontmatterBlank = true
+			}
+		}
 	}
 
 	// Handle markdown heading - only for markdown files
 	isMarkdown := strings.HasSuffix(file, ".md") || strings.HasSuffix(file, ".markdown")
-	if startLine < len(lines) && isMarkdown && f.config.Files.PlacementExceptions.MarkdownHeading && hasMarkdownHeading(lines[startLine:]) {
+	if startLine < len(lines) && isMarkdown && cfg.Files.PlacementExceptions.MarkdownHeading && hasMarkdownHeading(lines[startLine:]) {
 		result = append(result, lines[startLine])
 		startLine++
 	}
 
 	// Determine scan limit for header area
-	maxScan := len(lines)
-	if f.config.Detection.MaxScanLines > 0 {
-		maxScan = min(startLine+f.config.Detection.MaxScanLines, len(lines))
-	}
-
-	// Get comment prefix for SPDX detection
-	extKey := strings.TrimPrefix(ext, ".")
-	extKey = strings.ReplaceAll(extKey, ".", "_")
-	commentPrefix := f.config.Files.CommentStyles[extKey]
-	if commentPrefix == "" {
-		// Fallback to hardcoded values if not found in config
-		switch ext {
-		case ".go":
-			commentPrefix = "//"
-		case ".sh", ".py", ".hcl", ".tf", ".yml", ".yaml":
-			commentPrefix = "#"
-		case ".md", ".html.markdown":
-			commentPrefix = "<!--"
-		default:
-			commentPrefix = "//"
-		}
+	maxScan := headerScanEnd(cfg, ext, lines, startLine)
+
+	// Move header lines out of a Python module docstring into comments
+	if extracted, changed := extractDocstringHeader(cfg, ext, lines, startLine); changed {
+		lines = extracted
+		maxScan = headerScanEnd(cfg, ext, lines, startLine)
+		fixed = true
+	}
+
+	// Bring HTML comment headers split across lines into line-per-header form
+	if split, changed := splitHTMLCommentHeaders(cfg, ext, lines, startLine, maxScan); changed {
+		maxScan += len(split) - len(lines)
+		lines = split
+		fixed = true
 	}
 
 	// Scan for existing copyrights and third-party copyrights in header area only
 	hasCorrectCopyright := false
 	hasCorrectLicense := false
+	hasCorrectNotice := false
+	hasWrongSyntax := false
+	bannerCount := 0
+	outdatedCopyrightLine := -1          // our copyright line when only its years need updating
+	outdatedCopyright := copyrightHeader // what that line is updated to
+	yearOnlyCopyright := ""              // the first copyright line update_year_only applies to, updated
+	outdatedLicenseLine := -1            // an SPDX line that only needs its identifier updating
+	copyrightLine := -1                  // first copyright line of ours, current or outdated
+	licenseLine := -1                    // first license line, current or outdated
+	lastHeaderLine := -1
+	firstOtherLine := -1   // first line in the header area that is not part of a header
+	otherChanges := false  // changes beyond updating those two lines in place
+	var replaceLines []int // lines matching a replace pattern
 	for i := startLine; i < maxScan; i++ {
 		line := lines[i]
-		if f.config.ShouldReplace(line) {
+		if isKeptLine(lines, i) {
+			if firstOtherLine < 0 {
+				firstOtherLine = i
+			}
+			continue
+		}
+		if updated, ok := updateYearOnly(cfg, ext, line, multiLine); ok {
+			// A copyright line naming the holder only needs its years to be current
+			if copyrightLine < 0 {
+				copyrightLine = i
+				yearOnlyCopyright = updated
+			}
+			if updated == line {
+				otherChanges = otherChanges || hasCorrectCopyright
+				hasCorrectCopyright = true
+			} else {
+				hasCopyright = true
+				if outdatedCopyrightLine >= 0 {
+					otherChanges = true
+				}
+				outdatedCopyrightLine, outdatedCopyright = i, updated
+			}
+		} else if cfg.ShouldReplace(line) {
+			hasCopyright = true
+			replaceLines = append(replaceLines, i)
+		} else if multiLine && isCurrentCopyrightAt(i, maxScan) {
+			if copyrightLine < 0 {
+				copyrightLine = i
+			}
+			otherChanges = otherChanges || hasCorrectCopyright
+			hasCorrectCopyright = true
+			i += len(copyrightLines) - 1
+		} else if multiLine && cfg.IsOwnCopyrightLine(line, ext) {
+			// Part of an outdated or incomplete block: the block is rebuilt rather than
+			// updated line by line
+			if copyrightLine < 0 {
+				copyrightLine = i
+			}
 			hasCopyright = true
-		} else if f.config.IsOwnCopyrightLine(line, ext) {
+			otherChanges = true
+		} else if multiLine && lastHeaderLine == i-1 && isAnyHeaderLine(line, fillerLines) {
+			// A blank line of the format inside an outdated block
+			continue
+		} else if cfg.IsOwnCopyrightLine(line, ext) {
 			// Found our own copyright line - mark for replacement if not current
-			if strings.TrimSpace(line) != strings.TrimSpace(copyrightHeader) {
+			if copyrightLine < 0 {
+				copyrightLine = i
+			}
+			if !isCurrentCopyrightAt(i, i+1) {
 				hasCopyright = true
+				if outdatedCopyrightLine >= 0 {
+					otherChanges = true
+				}
+				outdatedCopyrightLine = i
 			} else {
+				otherChanges = otherChanges || hasCorrectCopyright
 				hasCorrectCopyright = true
 			}
-		} else if f.config.IsThirdPartyCopyright(line) {
+		} else if cfg.IsWrongSyntaxHeaderLine(line, ext) {
+			// Our header written in another comment syntax - always replace
+			hasCopyright = true
+			hasWrongSyntax = true
+		} else if cfg.IsThirdPartyCopyright(line, ext) {
 			thirdPartyLines = append(thirdPartyLines, line)
-		} else if strings.TrimSpace(line) == strings.TrimSpace(copyrightHeader) {
+		} else if isCurrentCopyrightAt(i, i+1) {
+			if copyrightLine < 0 {
+				copyrightLine = i
+			}
+			otherChanges = otherChanges || hasCorrectCopyright
 			hasCorrectCopyright = true
-		} else if licenseHeader != "" && strings.TrimSpace(line) == strings.TrimSpace(licenseHeader) {
+		} else if licenseHeader != "" && isSameLicenseLine(line, licenseHeader) {
+			if licenseLine < 0 {
+				licenseLine = i
+			}
+			otherChanges = otherChanges || hasCorrectLicense
 			hasCorrectLicense = true
-		} else if isSPDXHeaderLine(line, commentPrefix) {
+		} else if noticeHeader != "" && isSameHeaderLine(line, noticeHeader) {
+			otherChanges = otherChanges || hasCorrectNotice
+			hasCorrectNotice = true
+		} else if (bannerBefore != "" && isSameHeaderLine(line, bannerBefore)) ||
+			(bannerAfter != "" && isSameHeaderLine(line, bannerAfter)) {
+			bannerCount++
+		} else if isSPDXHeaderLine(cfg, ext, line) {
 			// Found an SPDX header line - we'll need to replace it if it's not exactly our format
-			if licenseHeader == "" || strings.TrimSpace(line) != strings.TrimSpace(licenseHeader) {
-				hasCopyright = true // Mark as needing replacement
+			hasCopyright = true // Mark as needing replacement
+			if licenseLine < 0 {
+				licenseLine = i
+			}
+			if licenseHeader == "" || outdatedLicenseLine >= 0 {
+				otherChanges = true
+			}
+			outdatedLicenseLine = i
+		} else {
+			trimmed := strings.TrimSpace(line)
+			if firstOtherLine < 0 && trimmed != "" && !isBlockDelimiter(cfg, ext, trimmed) {
+				firstOtherLine = i
 			}
+			continue
 		}
+		lastHeaderLine = i
 	}
 
-	// If both copyright and license (if enabled) are already correct, nothing to do
-	if hasCorrectCopyright && (licenseHeader == "" || hasCorrectLicense) {
-		return false
+	// A Go header must be separated from the package doc comment, or it becomes part of it
+	mergedIntoPackageDoc := ext == ".go" && lastHeaderLine >= 0 && runsIntoPackageClause(lines[lastHeaderLine+1:])
+
+	// Extra blank lines between the header and the code are collapsed when configured, and a
+	// configured number of blank lines is kept to
+	blankLines, exactBlankLines := cfg.HeaderBlankLines(ext)
+	extraBlankLines := lastHeaderLine >= 0 &&
+		(cfg.Detection.CollapseBlankLines && hasExtraBlankLines(lines, lastHeaderLine+1) ||
+			exactBlankLines && hasWrongBlankRun(cfg, ext, lines, lastHeaderLine+1, blankLines))
+
+	// The license line must follow the copyright line, as in the canonical header
+	misordered := copyrightLine >= 0 && licenseLine >= 0 && licenseLine < copyrightLine
+
+	// Everything besides the copyright and license lines is already as configured
+	restCorrect := (noticeHeader == "" || hasCorrectNotice) && bannerCount >= wantBanners &&
+		!hasWrongSyntax && !missingShebangBlank && !missingFrontmatterBlank && !mergedIntoPackageDoc && !misordered && !extraBlankLines && !fixed
+
+	// A file without any header line of ours, current or not, gets its header added rather than fixed
+	added := !hasCopyright && !hasCorrectCopyright && !hasCorrectLicense && !hasCorrectNotice && bannerCount == 0
+
+	// If copyright, license (if enabled), notice and banners (if configured) are already correct, nothing to do
+	if !f.Force && hasCorrectCopyright && (licenseHeader == "" || hasCorrectLicense) && restCorrect {
+		return headerChange{}, nil
+	}
+
+	// The header keeps the copyright line update_year_only applies to, with its text
+	if yearOnlyCopyright != "" {
+		header = headerBlock(cfg, ext, bannerBefore, yearOnlyCopyright, licenseHeader, noticeHeader, bannerAfter)
+	}
+
+	// A header inside a leading comment banner, e.g. line 5 of a "//" block describing the
+	// file, is updated where it is: rebuilding would move it above the banner and split it
+	headerAtTop := firstOtherLine < 0 || firstOtherLine > lastHeaderLine
+	inBanner := !headerAtTop && !cfg.Detection.RequireAtTop && lastHeaderLine >= 0 &&
+		lastHeaderLine < leadingCommentEnd(cfg, ext, lines, startLine)
+	if inBanner && !hasCorrectCopyright && outdatedCopyrightLine < 0 && len(replaceLines) == 1 {
+		// The copyright being replaced gives up its line in the banner
+		outdatedCopyrightLine, replaceLines = replaceLines[0], nil
+	}
+	// A banner holding just the copyright gets the license line right below it
+	insertLicense := inBanner && licenseHeader != "" && !hasCorrectLicense && outdatedLicenseLine < 0
+
+	// When only the copyright years and/or the license line of a header block at the top are
+	// outdated, rewrite just those lines in place. This keeps diffs minimal and leaves
+	// third-party notices above or below the header byte-identical and in their positions.
+	copyrightInPlace := hasCorrectCopyright != (outdatedCopyrightLine >= 0)
+	licenseInPlace := licenseHeader == "" || hasCorrectLicense != (outdatedLicenseLine >= 0) || insertLicense
+	if !f.Force && copyrightInPlace && licenseInPlace && (headerAtTop || inBanner) && restCorrect &&
+		!otherChanges && len(replaceLines) == 0 && (cfg.ThirdParty.Action != "replace" || len(thirdPartyLines) == 0) {
+		// Edit a copy so the original lines remain to diff against
+		lines = slices.Clone(lines)
+		if outdatedCopyrightLine >= 0 {
+			lines[outdatedCopyrightLine] = outdatedCopyright
+		}
+		if outdatedLicenseLine >= 0 {
+			lines[outdatedLicenseLine] = licenseHeader
+		}
+		if insertLicense {
+			at := max(outdatedCopyrightLine, copyrightLine) + len(copyrightLines)
+			lines = slices.Insert(lines, at, licenseHeader)
+			lastHeaderLine++
+		}
+		lines = trimHeaderOnlyBody(lines, lastHeaderLine+1)
+		diff, err := f.writeFixed(path, lines, head)
+		if err != nil {
+			return headerChange{}, err
+		}
+		return headerChange{header: header, added: added, diff: diff}, nil
 	}
 
 	// Helper to add copyright headers with proper block comment wrapping
 	addHeaders := func(r *[]string) {
-		if isBlockCommentStyle(f.config, ext) {
-			*r = append(*r, "/**")
-			*r = append(*r, copyrightHeader)
-			if licenseHeader != "" {
-				*r = append(*r, licenseHeader)
-			}
-			*r = append(*r, " */")
-		} else {
-			*r = append(*r, copyrightHeader)
-			if licenseHeader != "" {
-				*r = append(*r, licenseHeader)
-			}
-		}
+		*r = append(*r, header...)
 	}
 
 	// Handle third-party copyrights based on action
-	switch f.config.ThirdParty.Action {
+	switch cfg.ThirdParty.Action {
 	case "above":
 		// Add our copyright above third-party
 		addHeaders(&result)
@@ -271,44 +856,31 @@ func (f *Fixer) fixFile(file string) bool {
 		addBlankLineIfNeeded(&result, lines, startLine)
 	}
 
+	headerEnd := len(result)
+
 	// Process remaining content, only removing copyrights from header area
 	skipNext := false
 	inCopyrightBlock := false // Track if we're inside a multi-line comment with copyright
+	blockClose := ""          // Closing delimiter of that comment
 	skipNextBlank := false    // Skip blank line after copyright block removal
 	for i := startLine; i < len(lines); i++ {
 		line := lines[i]
 		trimmed := strings.TrimSpace(line)
-		inHeaderArea := i < maxScan
+		inHeaderArea := i < maxScan && !isKeptLine(lines, i)
 
 		// Only skip/remove copyright lines if in header area
 		if inHeaderArea {
-			// Detect start of multi-line comment block (<!-- or /**)
-			if trimmed == "<!--" || trimmed == "/**" {
-				closeMarker := "-->"
-				if trimmed == "/**" {
-					closeMarker = "*/"
-				}
-				// Look ahead to see if this block contains copyright
-				for j := i + 1; j < maxScan && j < len(lines); j++ {
-					checkLine := lines[j]
-					checkTrimmed := strings.TrimSpace(checkLine)
-					if checkTrimmed == closeMarker || strings.HasSuffix(checkTrimmed, closeMarker) {
-						break
-					}
-					if f.config.ShouldReplace(checkLine) || f.config.IsOwnCopyrightLine(checkLine, ext) || isSPDXHeaderLine(checkLine, commentPrefix) {
-						inCopyrightBlock = true
-						fixed = true
-						break
-					}
-				}
-				if inCopyrightBlock {
-					continue // Skip the opening marker
-				}
+			// Skip a multi-line comment block (<!--, /** or the block style of ext) holding a header
+			if closeMarker := headerCommentClose(cfg, ext, lines, i, maxScan, noticeHeader); closeMarker != "" {
+				inCopyrightBlock = true
+				blockClose = closeMarker
+				fixed = true
+				continue
 			}
 
 			// Skip lines inside a copyright block
 			if inCopyrightBlock {
-				if trimmed == "-->" || trimmed == "*/" || strings.HasSuffix(trimmed, "*/") {
+				if strings.HasSuffix(trimmed, blockClose) {
 					inCopyrightBlock = false
 					skipNextBlank = true
 				}
@@ -323,33 +895,48 @@ func (f *Fixer) fixFile(file string) bool {
 			skipNextBlank = false
 
 			// Remove old copyright/license lines if we're adding new ones
-			if strings.TrimSpace(line) == strings.TrimSpace(copyrightHeader) ||
-				(licenseHeader != "" && strings.TrimSpace(line) == strings.TrimSpace(licenseHeader)) {
+			if isSameHeaderLine(line, copyrightHeader) ||
+				(licenseHeader != "" && isSameLicenseLine(line, licenseHeader)) ||
+				(noticeHeader != "" && isSameHeaderLine(line, noticeHeader)) ||
+				(bannerBefore != "" && isSameHeaderLine(line, bannerBefore)) ||
+				(bannerAfter != "" && isSameHeaderLine(line, bannerAfter)) {
 				skipNext = true
 				continue
 			}
 
 			// Remove our own copyright lines that need updating
-			if f.config.IsOwnCopyrightLine(line, ext) {
+			if _, ok := updateYearOnly(cfg, ext, line, multiLine); ok || cfg.IsOwnCopyrightLine(line, ext) {
 				fixed = true
 				skipNext = true
 				continue
 			}
 
+			// Remove the blank lines of a multi-line copyright format between its lines
+			if isOwnBlockFiller(cfg, ext, lines, i, fillerLines) {
+				continue
+			}
+
 			// Remove any SPDX header line (handles duplicates and different formats)
-			if isSPDXHeaderLine(line, commentPrefix) {
+			if isSPDXHeaderLine(cfg, ext, line) {
 				fixed = true
 				skipNext = true
 				continue
 			}
 
-			if f.config.ShouldReplace(line) {
+			// Remove our headers written in the wrong comment syntax
+			if cfg.IsWrongSyntaxHeaderLine(line, ext) {
 				fixed = true
 				skipNext = true
 				continue
 			}
 
-			if f.config.IsThirdPartyCopyright(line) && f.config.ThirdParty.Action != "leave" {
+			if cfg.ShouldReplace(line) {
+				fixed = true
+				skipNext = true
+				continue
+			}
+
+			if cfg.IsThirdPartyCopyright(line, ext) && cfg.ThirdParty.Action != "leave" {
 				fixed = true
 				skipNext = true
 				continue
@@ -373,13 +960,213 @@ func (f *Fixer) fixFile(file string) bool {
 		fixed = true // Always fix when third-party copyright is present
 	}
 
-	if fixed {
-		newContent := strings.Join(result, "\n")
-		_ = os.WriteFile(file, []byte(newContent), 0644)
-		return true
+	if fixed || f.Force {
+		result = trimHeaderOnlyBody(result, headerEnd)
+		if exactBlankLines {
+			result = resizeBlankRun(result, headerEnd, blankLines)
+		} else if cfg.Detection.CollapseBlankLines {
+			result = collapseBlankRun(result, headerEnd)
+		}
+		// A rebuild can reproduce the file exactly, e.g. a forced rebuild of a canonical header
+		// or one around a third-party notice already in place; such files are not rewritten
+		if slices.Equal(result, head.lines) {
+			return headerChange{}, nil
+		}
+		diff, err := f.writeFixed(path, result, head)
+		if err != nil {
+			return headerChange{}, err
+		}
+		return headerChange{header: header, added: added, diff: diff}, nil
+	}
+
+	return headerChange{}, nil
+}
+
+// headerCommentClose returns the closing delimiter of the multi-line comment opening at
+// lines[i] (<!--, /*, /** or the block style of ext) when it holds only header lines, ours in any
+// comment syntax or third-party notices being replaced, and closes within the scan window, so the
+// whole comment is replaced by the new header; otherwise it returns ""
+func headerCommentClose(cfg *config.Config, ext string, lines []string, i, maxScan int, noticeHeader string) string {
+	trimmed := strings.TrimSpace(lines[i])
+	closeMarker := ""
+	if trimmed == "<!--" {
+		closeMarker = "-->"
+	} else if trimmed == "/**" || trimmed == "/*" {
+		closeMarker = "*/"
+	} else if style, ok := cfg.BlockComment(ext); ok && trimmed == strings.TrimSpace(style.Open) {
+		closeMarker = strings.TrimSpace(style.Close)
+	}
+	if closeMarker == "" {
+		return ""
+	}
+	hasHeader := false
+	for j := i + 1; j < maxScan && j < len(lines); j++ {
+		checkLine := lines[j]
+		checkTrimmed := strings.TrimSpace(checkLine)
+		if checkTrimmed == closeMarker {
+			if hasHeader {
+				return closeMarker
+			}
+			return ""
+		}
+		switch {
+		case isKeptLine(lines, j):
+			// A kept line keeps the comment it is in
+			return ""
+		case cfg.ShouldReplace(checkLine) || cfg.IsOwnCopyrightLine(checkLine, ext) || isSPDXHeaderLine(cfg, ext, checkLine) ||
+			cfg.IsWrongSyntaxHeaderLine(checkLine, ext) ||
+			(noticeHeader != "" && isSameHeaderLine(checkLine, noticeHeader)) ||
+			(cfg.ThirdParty.Action == "replace" && cfg.IsThirdPartyCopyright(checkLine, ext)):
+			hasHeader = true
+		case checkTrimmed == "" || checkTrimmed == "*":
+		default:
+			// Other text keeps the comment; only its header lines are removed
+			return ""
+		}
+	}
+	// An unclosed comment is left alone rather than removed along with the rest of the file
+	return ""
+}
+
+// writeFixed writes the fixed lines of file at path, unless this is a dry run, and returns
+// how they differ from the lines read when Diff is set
+func (f *Fixer) writeFixed(path string, lines []string, head *fileHead) (string, error) {
+	diff := ""
+	if f.Diff != nil {
+		before, after := head.lines, lines
+		if !head.complete {
+			// The rest of the file follows, so both end with a newline
+			before, after = append(slices.Clone(before), ""), append(slices.Clone(after), "")
+		} else if head.newlineAtEOF && after[len(after)-1] != "" {
+			// The final newline is kept when the lines are written
+			after = append(slices.Clone(after), "")
+		}
+		diff = unifiedDiff(path, before, after)
+	}
+	if f.DryRun {
+		return diff, nil
+	}
+	return diff, writeFileWithHead(path, lines, head)
+}
+
+// headCoversHeaderArea reports whether the first lines of file hold its whole header area:
+// the scan window after any placement exceptions, followed by a non-blank line so that
+// nothing after the head can change how the header is fixed. Without a scan limit, or
+// with frontmatter that has not been closed yet, the whole file is needed.
+func (f *Fixer) headCoversHeaderArea(file string) func(lines []string) bool {
+	return func(lines []string) bool {
+		maxScanLines := f.config.ScanLines()
+		if maxScanLines <= 0 {
+			return false
+		}
+		if _, ok := frontmatterDelimiters(lines, f.config); ok && getFrontmatterEndNew(lines, f.config, file) == 0 {
+			return false
+		}
+		startLine := headerStartLine(lines, f.config, file)
+		return len(lines) > startLine+maxScanLines+1 && strings.TrimSpace(lines[len(lines)-1]) != ""
+	}
+}
+
+// trimHeaderOnlyBody handles files whose only content is the header: when nothing but blank
+// lines follows the header block, they are dropped so the file ends with the header and a
+// single trailing newline, keeping repeated fixes stable
+func trimHeaderOnlyBody(result []string, headerEnd int) []string {
+	for _, line := range result[headerEnd:] {
+		if strings.TrimSpace(line) != "" {
+			return result
+		}
+	}
+
+	for len(result) > 0 && strings.TrimSpace(result[len(result)-1]) == "" {
+		result = result[:len(result)-1]
+	}
+	return append(result, "")
+}
+
+// leadingCommentEnd returns the index just past the comment block starting at index start:
+// a run of line comments, or a whole block comment for block comment styles. It returns
+// start when the line there does not open a comment.
+func leadingCommentEnd(cfg *config.Config, ext string, lines []string, start int) int {
+	if start >= len(lines) {
+		return start
 	}
+	commentPrefix := cfg.CommentPrefix(ext)
+	if commentPrefix == "<!--" {
+		return start
+	}
+	if style, ok := cfg.BlockComment(ext); ok {
+		if !strings.HasPrefix(strings.TrimSpace(lines[start]), strings.TrimSpace(style.Open)) {
+			return start
+		}
+		for i := start; i < len(lines); i++ {
+			if strings.Contains(lines[i], strings.TrimSpace(style.Close)) {
+				return i + 1
+			}
+		}
+		return start
+	}
+
+	end := start
+	for end < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[end]), commentPrefix) {
+		end++
+	}
+	return end
+}
+
+// blankRunLength counts the consecutive blank lines starting at index start
+func blankRunLength(lines []string, start int) int {
+	n := 0
+	for i := start; i < len(lines) && strings.TrimSpace(lines[i]) == ""; i++ {
+		n++
+	}
+	return n
+}
+
+// hasExtraBlankLines reports whether more than one blank line starting at index start separates
+// the header from following content; blank lines running to the end of the file are left to
+// trimHeaderOnlyBody
+func hasExtraBlankLines(lines []string, start int) bool {
+	n := blankRunLength(lines, start)
+	return n > 1 && start+n < len(lines)
+}
+
+// collapseBlankRun reduces the blank lines around index headerEnd, including the separator
+// added after the new header, to one
+func collapseBlankRun(lines []string, headerEnd int) []string {
+	start := headerEnd
+	for start > 0 && strings.TrimSpace(lines[start-1]) == "" {
+		start--
+	}
+	if !hasExtraBlankLines(lines, start) {
+		return lines
+	}
+	return slices.Delete(lines, start+1, start+blankRunLength(lines, start))
+}
 
-	return false
+// hasWrongBlankRun reports whether other than n blank lines starting at index start separate the
+// header from following content. A header running into further comments, as in a banner, and
+// blank lines running to the end of the file are left alone.
+func hasWrongBlankRun(cfg *config.Config, ext string, lines []string, start, n int) bool {
+	run := blankRunLength(lines, start)
+	if run == n || start+run >= len(lines) {
+		return false
+	}
+	return run > 0 || leadingCommentEnd(cfg, ext, lines, start) == start
+}
+
+// resizeBlankRun sets the blank lines around index headerEnd, including the separator added
+// after the new header, to n, unless they run to the end of the file
+func resizeBlankRun(lines []string, headerEnd, n int) []string {
+	start := headerEnd
+	for start > 0 && strings.TrimSpace(lines[start-1]) == "" {
+		start--
+	}
+	run := blankRunLength(lines, start)
+	if run == n || start+run >= len(lines) {
+		return lines
+	}
+	lines = slices.Delete(lines, start, start+run)
+	return slices.Insert(lines, start, slices.Repeat([]string{""}, n)...)
 }
 
 // addBlankLineIfNeeded adds a blank line only if the next content line isn't already blank
@@ -396,20 +1183,37 @@ func addBlankLineIfNeeded(result *[]string, lines []string, startLine int) {
 // ProcessContent applies the same header normalization logic as fixFile but on in-memory content
 // This is primarily for testing the core logic without file I/O
 func (f *Fixer) ProcessContent(content []byte, ext string) ([]byte, error) {
-	lines := strings.Split(string(content), "\n")
-	if len(lines) == 0 || f.config.IsGenerated(lines) {
+	body, bom := cutBOM(content)
+	lines, eol := splitLines(string(body))
+	if len(lines) == 0 || f.config.IsSkippedContent(lines) {
+		return content, nil
+	}
+
+	cfg := f.config
+	if hasShebang(lines) {
+		cfg = cfg.ForShebang(lines[0], ext)
+	}
+
+	cfg, skip := forMinified(cfg, ext, lines)
+	if skip {
 		return content, nil
 	}
 
-	copyrightHeader, err := f.config.GetCopyrightHeader(ext)
+	copyrightHeader, err := cfg.GetCopyrightHeader(ext)
+	if err != nil {
+		return nil, err
+	}
+
+	licenseHeader, err := cfg.GetLicenseHeader(ext)
 	if err != nil {
 		return nil, err
 	}
 
-	licenseHeader, err := f.config.GetLicenseHeader(ext)
+	noticeHeader, err := cfg.GetNoticeHeader(ext)
 	if err != nil {
 		return nil, err
 	}
+	fillerLines := copyrightFillerLines(cfg, ext, strings.Split(copyrightHeader, "\n"))
 
 	var result []string
 	startLine := 0
@@ -419,101 +1223,106 @@ func (f *Fixer) ProcessContent(content []byte, ext string) ([]byte, error) {
 	if hasShebang(lines) {
 		result = append(result, lines[0])
 		startLine = 1
+		if cfg.Files.BlankAfterShebang {
+			result = append(result, "")
+			if startLine < len(lines) && strings.TrimSpace(lines[startLine]) == "" {
+				startLine++
+			}
+		}
 	}
 
 	// Determine scan limit (same as fixFile)
-	maxScan := len(lines)
-	if f.config.Detection.MaxScanLines > 0 {
-		maxScan = min(startLine+f.config.Detection.MaxScanLines, len(lines))
+	maxScan := headerScanEnd(cfg, ext, lines, startLine)
+
+	if extracted, changed := extractDocstringHeader(cfg, ext, lines, startLine); changed {
+		lines = extracted
+		maxScan = headerScanEnd(cfg, ext, lines, startLine)
+	}
+
+	if split, changed := splitHTMLCommentHeaders(cfg, ext, lines, startLine, maxScan); changed {
+		maxScan += len(split) - len(lines)
+		lines = split
 	}
 
 	// Scan for third-party copyrights (same as fixFile)
 	for i := startLine; i < maxScan; i++ {
 		line := lines[i]
-		if f.config.IsThirdPartyCopyright(line) {
+		if cfg.IsThirdPartyCopyright(line, ext) && !isKeptLine(lines, i) {
 			thirdPartyLines = append(thirdPartyLines, line)
 		}
 	}
 
 	// Handle third-party copyrights (same as fixFile)
-	switch f.config.ThirdParty.Action {
+	header := headerBlock(cfg, ext, copyrightHeader, licenseHeader, noticeHeader)
+	switch cfg.ThirdParty.Action {
 	case "above":
-		result = append(result, copyrightHeader)
-		if licenseHeader != "" {
-			result = append(result, licenseHeader)
-		}
+		result = append(result, header...)
 		result = append(result, thirdPartyLines...)
-		result = append(result, "")
 	case "below":
 		result = append(result, thirdPartyLines...)
-		result = append(result, copyrightHeader)
-		if licenseHeader != "" {
-			result = append(result, licenseHeader)
-		}
-		result = append(result, "")
-	case "replace":
-		result = append(result, copyrightHeader)
-		if licenseHeader != "" {
-			result = append(result, licenseHeader)
-		}
-		result = append(result, "")
-	default: // "leave"
-		result = append(result, copyrightHeader)
-		if licenseHeader != "" {
-			result = append(result, licenseHeader)
-		}
-		result = append(result, "")
-	}
-
-	// Get comment prefix for SPDX detection
-	extKey := strings.TrimPrefix(ext, ".")
-	extKey = strings.ReplaceAll(extKey, ".", "_")
-	commentPrefix := f.config.Files.CommentStyles[extKey]
-	if commentPrefix == "" {
-		// Fallback to hardcoded values if not found in config
-		switch ext {
-		case ".go":
-			commentPrefix = "//"
-		case ".sh", ".py", ".hcl", ".tf", ".yml", ".yaml":
-			commentPrefix = "#"
-		case ".md", ".html.markdown":
-			commentPrefix = "<!--"
-		default:
-			commentPrefix = "//"
-		}
+		result = append(result, header...)
+	default: // "replace" or "leave"
+		result = append(result, header...)
 	}
+	result = append(result, "")
+
+	headerEnd := len(result)
 
 	// Process remaining content (same logic as fixFile)
 	skipNext := false
+	blockClose := "" // Closing delimiter of a comment block holding a header being removed
 	for i := startLine; i < len(lines); i++ {
 		line := lines[i]
-		inHeaderArea := i < maxScan
+		inHeaderArea := i < maxScan && !isKeptLine(lines, i)
 
 		if inHeaderArea {
-			if strings.TrimSpace(line) == strings.TrimSpace(copyrightHeader) ||
-				(licenseHeader != "" && strings.TrimSpace(line) == strings.TrimSpace(licenseHeader)) {
+			if closeMarker := headerCommentClose(cfg, ext, lines, i, maxScan, noticeHeader); closeMarker != "" {
+				blockClose = closeMarker
+				continue
+			}
+			if blockClose != "" {
+				if strings.HasSuffix(strings.TrimSpace(line), blockClose) {
+					blockClose = ""
+					skipNext = true
+				}
+				continue
+			}
+
+			if isSameHeaderLine(line, copyrightHeader) ||
+				(licenseHeader != "" && isSameLicenseLine(line, licenseHeader)) ||
+				(noticeHeader != "" && isSameHeaderLine(line, noticeHeader)) {
 				skipNext = true
 				continue
 			}
 
 			// Remove our own copyright lines that need updating
-			if f.config.IsOwnCopyrightLine(line, ext) {
+			if cfg.IsOwnCopyrightLine(line, ext) {
 				skipNext = true
 				continue
 			}
 
+			if isOwnBlockFiller(cfg, ext, lines, i, fillerLines) {
+				continue
+			}
+
 			// Remove any SPDX header line (handles duplicates and different formats)
-			if isSPDXHeaderLine(line, commentPrefix) {
+			if isSPDXHeaderLine(cfg, ext, line) {
 				skipNext = true
 				continue
 			}
 
-			if f.config.ShouldReplace(line) {
+			// Remove our headers written in the wrong comment syntax
+			if cfg.IsWrongSyntaxHeaderLine(line, ext) {
 				skipNext = true
 				continue
 			}
 
-			if f.config.IsThirdPartyCopyright(line) && f.config.ThirdParty.Action != "leave" {
+			if cfg.ShouldReplace(line) {
+				skipNext = true
+				continue
+			}
+
+			if cfg.IsThirdPartyCopyright(line, ext) && cfg.ThirdParty.Action != "leave" {
 				skipNext = true
 				continue
 			}
@@ -529,11 +1338,15 @@ func (f *Fixer) ProcessContent(content []byte, ext string) ([]byte, error) {
 		result = append(result, line)
 	}
 
-	output := strings.Join(result, "\n")
-
-	// Preserve original trailing newline behavior
-	if strings.HasSuffix(string(content), "\n") && !strings.HasSuffix(output, "\n") {
-		output += "\n"
+	result = trimHeaderOnlyBody(result, headerEnd)
+	if blankLines, exact := cfg.HeaderBlankLines(ext); exact {
+		result = resizeBlankRun(result, headerEnd, blankLines)
+	} else if cfg.Detection.CollapseBlankLines {
+		result = collapseBlankRun(result, headerEnd)
+	}
+	output := joinLines(result, eol, bytes.HasSuffix(body, []byte("\n")))
+	if bom {
+		output = utf8BOM + output
 	}
 
 	return []byte(output), nil
@@ -4,10 +4,16 @@
 package copyright
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/YakDriver/copyplop/internal/config"
 )
@@ -121,7 +127,7 @@ function test() {}`,
 				t.Fatalf("Failed to write test file: %v", err)
 			}
 
-			fixed := fixer.fixFile(filePath)
+			fixed := mustFixFile(t, fixer, filePath)
 			if !fixed {
 				t.Error("Expected file to be fixed")
 			}
@@ -307,7 +313,7 @@ func main() {}`,
 				t.Fatalf("Failed to write test file: %v", err)
 			}
 
-			fixed := fixer.fixFile(filePath)
+			fixed := mustFixFile(t, fixer, filePath)
 			if fixed != tt.shouldFix {
 				t.Errorf("fixFile() fixed = %v, want %v", fixed, tt.shouldFix)
 			}
@@ -403,7 +409,7 @@ package main`,
 				t.Fatalf("Failed to write test file: %v", err)
 			}
 
-			fixer.fixFile(filePath)
+			mustFixFile(t, fixer, filePath)
 
 			content, err := os.ReadFile(filePath)
 			if err != nil {
@@ -418,6 +424,56 @@ package main`,
 	}
 }
 
+// Copyright text outside comments, e.g. in a module docstring, is not a third-party notice:
+// moving it above the header would leave the file unparseable
+func TestFixer_ThirdPartyOutsideComments(t *testing.T) {
+	cfg := &config.Config{
+		Copyright: config.Copyright{
+			Holder:      "IBM Corp.",
+			StartYear:   2014,
+			CurrentYear: 2026,
+			Format:      "Copyright {{.Holder}} {{.StartYear}}, {{.CurrentYear}}",
+		},
+		License: config.License{
+			Enabled:    true,
+			Identifier: "MPL-2.0",
+			Format:     "SPDX-License-Identifier: {{.Identifier}}",
+		},
+		Files: config.Files{
+			CommentStyles: map[string]string{"py": "#"},
+		},
+		Detection: config.Detection{
+			MaxScanLines: 20,
+			RequireAtTop: true,
+		},
+		ThirdParty: config.ThirdParty{
+			Action:   "above",
+			Patterns: []string{"Copyright.*[a-zA-Z0-9].*"},
+		},
+	}
+
+	input := "\"\"\"Client for the service.\n\nCopyright IBM Corp. 2014, 2025\n\"\"\"\n\nimport os\n"
+	expected := "# Copyright IBM Corp. 2014, 2026\n# SPDX-License-Identifier: MPL-2.0\n\n" + input
+
+	filePath := filepath.Join(t.TempDir(), "client.py")
+	if err := os.WriteFile(filePath, []byte(input), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	mustFixFile(t, NewFixer(cfg), filePath)
+
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != expected {
+		t.Errorf("Expected:\n%q\n\nGot:\n%q", expected, string(content))
+	}
+	if issue := NewChecker(cfg).checkFile(filePath); issue != nil {
+		t.Errorf("check after fix reported %q", issue.Problem)
+	}
+}
+
 func FuzzCopyplopNormalize(f *testing.F) {
 	// Test cases for different file types with appropriate extensions and content
 	testCases := []struct {
@@ -438,6 +494,8 @@ func FuzzCopyplopNormalize(f *testing.F) {
 				"Copyright 2025 Dirk Avery\n\npackage main\n",
 				"Copyright Dirk Avery 2025\n\npackage main\n",
 				"// Copyright (c) HashiCorp, Inc.\n// SPDX-License-Identifier: MPL-2.0\n\npackage main\n",
+				"\ufeffpackage main\n",
+				"\ufeff// Copyright X\n// SPDX-License-Identifier: Apache-2.0\n\npackage main\n",
 			},
 		},
 		{
@@ -458,6 +516,27 @@ func FuzzCopyplopNormalize(f *testing.F) {
 				"# Copyright Old\nprint('hello')\n",
 			},
 		},
+		{
+			ext:     ".css",
+			comment: "/* */",
+			seeds: []string{
+				"/*\n * Copyright X\n * SPDX-License-Identifier: Apache-2.0\n */\n\nbody { color: red; }\n",
+				"body { color: red; }\n",
+				"/* Copyright Old */\nbody { color: red; }\n",
+				"/* Reset styles */\nbody { margin: 0; }\n",
+				"\ufeff/*\n * Copyright Old\n */\nbody { color: red; }\n",
+			},
+		},
+		{
+			ext:     ".c",
+			comment: "/* */",
+			seeds: []string{
+				"/*\n * Copyright X\n * SPDX-License-Identifier: MIT\n */\n\n#include <stdio.h>\n",
+				"#include <stdio.h>\n\nint main(void) { return 0; }\n",
+				"/*\n * Copyright Old\n */\n#include <stdio.h>\n",
+				"// Copyright Old\n#include <stdio.h>\n",
+			},
+		},
 	}
 
 	// Add seeds for each file type
@@ -473,7 +552,7 @@ func FuzzCopyplopNormalize(f *testing.F) {
 		}
 
 		// Skip invalid extensions
-		if ext != ".go" && ext != ".sh" && ext != ".py" {
+		if ext != ".go" && ext != ".sh" && ext != ".py" && ext != ".css" && ext != ".c" {
 			t.Skip()
 		}
 
@@ -481,9 +560,14 @@ func FuzzCopyplopNormalize(f *testing.F) {
 		cfg := createConfigForExtension(ext)
 		fixer := NewFixer(cfg)
 
-		// Get the actual canonical headers from config
-		canonicalCopyright, _ := cfg.GetCopyrightHeader(ext)
-		canonicalSPDX, _ := cfg.GetLicenseHeader(ext)
+		// Get the actual canonical headers from config, honoring the shebang interpreter's style
+		input, hasBOM := strings.CutPrefix(s, utf8BOM)
+		headerCfg := cfg
+		if firstLine, _, _ := strings.Cut(input, "\n"); strings.HasPrefix(firstLine, "#!") {
+			headerCfg = cfg.ForShebang(firstLine, ext)
+		}
+		canonicalCopyright, _ := headerCfg.GetCopyrightHeader(ext)
+		canonicalSPDX, _ := headerCfg.GetLicenseHeader(ext)
 
 		// Use the real copyplop logic
 		out1, err := fixer.ProcessContent([]byte(s), ext)
@@ -500,15 +584,32 @@ func FuzzCopyplopNormalize(f *testing.F) {
 			t.Fatalf("not idempotent:\nfirst:\n%s\n\nsecond:\n%s", out1, out2)
 		}
 
-		// Property 2: canonical header present
-		outStr := string(out1)
-		if !hasCanonicalHeaderDynamic(outStr, canonicalCopyright, canonicalSPDX) {
+		// Property 2: canonical header present, after the byte order mark the input had
+		outStr, outHasBOM := strings.CutPrefix(string(out1), utf8BOM)
+		if outHasBOM != hasBOM {
+			t.Fatalf("byte order mark not preserved:\n%q", out1)
+		}
+		// Headers are written with the file's line ending; compare lines without it
+		outLines, _ := splitLines(outStr)
+		outLF := strings.Join(outLines, "\n")
+		if style, ok := headerCfg.BlockComment(ext); ok {
+			// A block comment header opens and closes on lines of its own
+			want := style.Open + "\n" + canonicalCopyright + "\n" + canonicalSPDX + "\n" + style.Close
+			body := outLF
+			if strings.HasPrefix(body, "#!") {
+				_, body, _ = strings.Cut(body, "\n")
+			}
+			rest, found := strings.CutPrefix(body, want)
+			if !found || (rest != "" && rest != "\n" && !strings.HasPrefix(rest, "\n\n")) {
+				t.Fatalf("missing canonical header:\n%s", outStr)
+			}
+		} else if !hasCanonicalHeaderDynamic(outLF, canonicalCopyright, canonicalSPDX) {
 			t.Fatalf("missing canonical header:\n%s", outStr)
 		}
 
 		// Property 3: body preserved (allowing for blank line normalization around headers)
-		if !bodiesEquivalent(s, outStr) {
-			t.Fatalf("body content changed unexpectedly\nOriginal:\n%q\nProcessed:\n%q", s, outStr)
+		if !bodiesEquivalent(input, outStr) {
+			t.Fatalf("body content changed unexpectedly\nOriginal:\n%q\nProcessed:\n%q", input, outStr)
 		}
 	})
 }
@@ -521,6 +622,8 @@ func createConfigForExtension(ext string) *config.Config {
 		commentStyle = "//"
 	case ".sh", ".py":
 		commentStyle = "#"
+	case ".css", ".c":
+		commentStyle = "/* */"
 	default:
 		commentStyle = "//"
 	}
@@ -537,8 +640,8 @@ func createConfigForExtension(ext string) *config.Config {
 			Format:     "SPDX-License-Identifier: {{.Identifier}}",
 		},
 		Files: config.Files{
-			Extensions:    []string{".go", ".sh", ".py"},
-			CommentStyles: map[string]string{ext: commentStyle},
+			Extensions:    []string{".go", ".sh", ".py", ".css", ".c"},
+			CommentStyles: map[string]string{strings.TrimPrefix(ext, "."): commentStyle},
 		},
 		Detection: config.Detection{
 			ReplacePatterns: []string{"Copyright (c) HashiCorp, Inc."},
@@ -674,8 +777,8 @@ func extractMeaningfulContent(s string) []string {
 	for _, line := range lines {
 		trimmed := strings.TrimSpace(line)
 
-		// Skip header-like lines
-		if strings.HasPrefix(trimmed, "#!") ||
+		// Skip header-like lines, including the delimiters of block comment headers
+		if strings.HasPrefix(trimmed, "#!") || trimmed == "/*" || trimmed == "*/" ||
 			strings.Contains(trimmed, "Copyright") ||
 			strings.Contains(trimmed, "SPDX") ||
 			(strings.HasPrefix(trimmed, "//") && (strings.Contains(trimmed, "License") || len(trimmed) < 10)) {
@@ -755,7 +858,7 @@ func TestFixer_XMLMultilineCommentReplacement(t *testing.T) {
 	}
 
 	fixer := NewFixer(cfg)
-	if !fixer.fixFile(testFile) {
+	if !mustFixFile(t, fixer, testFile) {
 		t.Fatal("Expected file to be fixed")
 	}
 
@@ -815,7 +918,7 @@ function hello() {}
 	}
 
 	fixer := NewFixer(cfg)
-	if !fixer.fixFile(testFile) {
+	if !mustFixFile(t, fixer, testFile) {
 		t.Fatal("Expected file to be fixed")
 	}
 
@@ -828,3 +931,3855 @@ function hello() {}
 		t.Errorf("Expected:\n%s\n\nGot:\n%s", expected, string(result))
 	}
 }
+
+func TestFixer_Limit(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	cfg := &config.Config{
+		Copyright: config.Copyright{
+			Holder:      "IBM Corp.",
+			StartYear:   2014,
+			CurrentYear: 2025,
+			Format:      "Copyright {{.Holder}} {{.StartYear}}, {{.CurrentYear}}",
+		},
+		Files: config.Files{
+			Extensions:    []string{".go"},
+			CommentStyles: map[string]string{"go": "//"},
+		},
+	}
+
+	// Write files in non-sorted order to make sure selection doesn't depend on creation order
+	names := []string{"e.go", "c.go", "a.go", "d.go", "b.go"}
+	for _, name := range names {
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte("package main\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	fixer := NewFixer(cfg)
+	fixer.Limit = 2
+
+	result, err := fixer.Fix(context.Background(), tmpDir)
+	if err != nil {
+		t.Fatalf("Fix() error = %v", err)
+	}
+
+	if result.Fixed+result.Added != 2 {
+		t.Errorf("Fix() changed %d files, want 2", result.Fixed+result.Added)
+	}
+
+	wantChanged := map[string]bool{"a.go": true, "b.go": true}
+	for _, name := range names {
+		content, err := os.ReadFile(filepath.Join(tmpDir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		changed := strings.HasPrefix(string(content), "// Copyright IBM Corp.")
+		if changed != wantChanged[name] {
+			t.Errorf("%s changed = %v, want %v", name, changed, wantChanged[name])
+		}
+	}
+}
+
+func TestFixer_AddedAndFixed(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	cfg := &config.Config{
+		Copyright: config.Copyright{
+			Holder:      "IBM Corp.",
+			StartYear:   2014,
+			CurrentYear: 2025,
+			Format:      "Copyright {{.Holder}} {{.StartYear}}, {{.CurrentYear}}",
+		},
+		Files: config.Files{
+			Extensions:    []string{".go"},
+			CommentStyles: map[string]string{"go": "//"},
+		},
+		Detection: config.Detection{
+			ReplacePatterns: []string{"Copyright.*HashiCorp"},
+			MaxScanLines:    20,
+		},
+		ThirdParty: config.ThirdParty{
+			Action:   "above",
+			Patterns: []string{"Copyright.*"},
+		},
+	}
+
+	files := map[string]string{
+		"none.go":        "package main\n",                                      // added
+		"third_party.go": "// Copyright 2020 Google LLC\n\npackage main\n",      // added
+		"outdated.go":    "// Copyright IBM Corp. 2014, 2020\n\npackage main\n", // fixed
+		"replaced.go":    "// Copyright (c) HashiCorp, Inc.\n\npackage main\n",  // fixed
+		"current.go":     "// Copyright IBM Corp. 2014, 2025\n\npackage main\n", // unchanged
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	result, err := NewFixer(cfg).Fix(context.Background(), tmpDir)
+	if err != nil {
+		t.Fatalf("Fix() error = %v", err)
+	}
+	// Files that had no header of ours are counted apart from those whose header was fixed
+	if result.Added != 2 || result.Fixed != 2 {
+		t.Errorf("Fix() added %d and fixed %d files, want 2 and 2", result.Added, result.Fixed)
+	}
+}
+
+func TestFixer_FailFast(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	cfg := &config.Config{
+		Copyright: config.Copyright{
+			Holder:      "IBM Corp.",
+			StartYear:   2014,
+			CurrentYear: 2025,
+			Format:      "Copyright {{.Holder}} {{.StartYear}}, {{.CurrentYear}}",
+		},
+		Files: config.Files{
+			Extensions:    []string{".go"},
+			CommentStyles: map[string]string{"go": "//"},
+		},
+	}
+
+	// a.go is a dangling symlink, so reading it fails before b.go and c.go are reached
+	if err := os.Symlink(filepath.Join(tmpDir, "missing"), filepath.Join(tmpDir, "a.go")); err != nil {
+		t.Skipf("symlinks not supported: %v", err)
+	}
+	for _, name := range []string{"b.go", "c.go"} {
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte("package main\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	fixer := NewFixer(cfg)
+	fixer.FailFast = true
+
+	if _, err := fixer.Fix(context.Background(), tmpDir); err == nil || !strings.Contains(err.Error(), "a.go") {
+		t.Fatalf("Fix() error = %v, want error for a.go", err)
+	}
+
+	for _, name := range []string{"b.go", "c.go"} {
+		content, err := os.ReadFile(filepath.Join(tmpDir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(content) != "package main\n" {
+			t.Errorf("%s was modified after the first error", name)
+		}
+	}
+
+	// Without fail-fast the unreadable file is reported and the rest are fixed
+	fixer.FailFast = false
+	result, err := fixer.Fix(context.Background(), tmpDir)
+	if err != nil {
+		t.Fatalf("Fix() error = %v", err)
+	}
+	if result.Added != 2 {
+		t.Errorf("Fix() added headers to %d files, want 2", result.Added)
+	}
+	if len(result.Errors) != 1 || result.Errors[0].File != filepath.Join(tmpDir, "a.go") {
+		t.Errorf("Fix() errors = %v, want one for a.go", result.Errors)
+	}
+}
+
+func TestFixer_ReadOnlyFile(t *testing.T) {
+	cfg := &config.Config{
+		Copyright: config.Copyright{
+			Holder:      "IBM Corp.",
+			StartYear:   2014,
+			CurrentYear: 2025,
+			Format:      "Copyright {{.Holder}} {{.StartYear}}, {{.CurrentYear}}",
+		},
+		Files: config.Files{
+			Extensions:    []string{".go"},
+			CommentStyles: map[string]string{"go": "//"},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		readOnlyDir bool
+	}{
+		// The file's own permissions are honored, though it is replaced by a rename
+		{name: "read-only file"},
+		// The directory is read-only so the file cannot be replaced
+		{name: "read-only directory", readOnlyDir: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.readOnlyDir && os.Getuid() == 0 {
+				t.Skip("root can write to read-only directories")
+			}
+			tmpDir := t.TempDir()
+
+			file := filepath.Join(tmpDir, "main.go")
+			if err := os.WriteFile(file, []byte("package main\n"), 0444); err != nil {
+				t.Fatal(err)
+			}
+			if tt.readOnlyDir {
+				if err := os.Chmod(tmpDir, 0555); err != nil {
+					t.Fatal(err)
+				}
+				t.Cleanup(func() { _ = os.Chmod(tmpDir, 0755) })
+			}
+
+			result, err := NewFixer(cfg).Fix(context.Background(), tmpDir)
+			if err != nil {
+				t.Fatalf("Fix() error = %v", err)
+			}
+			if result.Fixed != 0 || len(result.Changed) != 0 {
+				t.Errorf("Fix() reported %d fixed files %v, want none", result.Fixed, result.Changed)
+			}
+			if len(result.Errors) != 1 || !errors.Is(result.Errors[0], fs.ErrPermission) {
+				t.Errorf("Fix() errors = %v, want a permission error for main.go", result.Errors)
+			}
+
+			content, err := os.ReadFile(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(content) != "package main\n" {
+				t.Errorf("read-only file was rewritten: %q", content)
+			}
+		})
+	}
+}
+
+func TestFixer_Jobs(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	cfg := &config.Config{
+		Copyright: config.Copyright{
+			Holder:      "IBM Corp.",
+			StartYear:   2014,
+			CurrentYear: 2025,
+			Format:      "Copyright {{.Holder}} {{.StartYear}}, {{.CurrentYear}}",
+		},
+		Files: config.Files{
+			Extensions:    []string{".go"},
+			CommentStyles: map[string]string{"go": "//"},
+		},
+		Detection: config.Detection{
+			MaxScanLines: 20,
+		},
+	}
+
+	var want []string
+	for i := range 20 {
+		name := filepath.Join(tmpDir, fmt.Sprintf("f%02d.go", i))
+		want = append(want, name)
+		if err := os.WriteFile(name, []byte("package main\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var diff strings.Builder
+	fixer := NewFixer(cfg)
+	fixer.Jobs = 4
+	fixer.Verify = true
+	fixer.Diff = &diff
+	result, err := fixer.Fix(context.Background(), tmpDir)
+	if err != nil {
+		t.Fatalf("Fix() error = %v", err)
+	}
+	if result.Added != len(want) || len(result.Unverified) != 0 {
+		t.Errorf("Fix() with 4 jobs = %+v, want %d verified fixes", result, len(want))
+	}
+	if !slices.Equal(result.Changed, want) {
+		t.Errorf("Changed = %v, want %v in file order", result.Changed, want)
+	}
+	if got := strings.Count(diff.String(), "\n+// Copyright IBM Corp. 2014, 2025\n"); got != len(want) {
+		t.Errorf("Diff output holds %d whole diffs, want %d", got, len(want))
+	}
+
+	for _, name := range want {
+		content, err := os.ReadFile(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(content) != "// Copyright IBM Corp. 2014, 2025\n\npackage main\n" {
+			t.Errorf("%s = %q, want the header added", name, string(content))
+		}
+	}
+}
+
+// cancelAfterContext reports cancellation once Err has been called n times
+type cancelAfterContext struct {
+	context.Context
+	n int
+}
+
+func (c *cancelAfterContext) Err() error {
+	if c.n <= 0 {
+		return context.Canceled
+	}
+	c.n--
+	return nil
+}
+
+func TestFixer_Cancel(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	cfg := &config.Config{
+		Copyright: config.Copyright{
+			Holder:      "IBM Corp.",
+			StartYear:   2014,
+			CurrentYear: 2025,
+			Format:      "Copyright {{.Holder}} {{.StartYear}}, {{.CurrentYear}}",
+		},
+		Files: config.Files{
+			Extensions:    []string{".go"},
+			CommentStyles: map[string]string{"go": "//"},
+		},
+	}
+
+	names := []string{"a.go", "b.go", "c.go", "d.go", "e.go"}
+	for _, name := range names {
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte("package main\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	fixer := NewFixer(cfg)
+
+	// Cancelled after two files have been processed
+	result, err := fixer.Fix(&cancelAfterContext{Context: context.Background(), n: 2}, tmpDir)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Fix() error = %v, want context.Canceled", err)
+	}
+	if result.Added != 2 {
+		t.Errorf("Fix() added headers to %d files before cancellation, want 2", result.Added)
+	}
+
+	// Every file is either fully fixed or untouched, and no temporary files are left behind
+	entries, err := os.ReadDir(tmpDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != len(names) {
+		t.Errorf("found %d entries after cancellation, want %d", len(entries), len(names))
+	}
+	for i, name := range names {
+		content, err := os.ReadFile(filepath.Join(tmpDir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected := "package main\n"
+		if i < 2 {
+			expected = "// Copyright IBM Corp. 2014, 2025\n\npackage main\n"
+		}
+		if string(content) != expected {
+			t.Errorf("%s = %q, want %q", name, string(content), expected)
+		}
+	}
+}
+
+func TestFixer_WhitespaceInsensitiveHeader(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	cfg := &config.Config{
+		Copyright: config.Copyright{
+			Holder:      "IBM Corp.",
+			StartYear:   2014,
+			CurrentYear: 2025,
+			Format:      "Copyright {{.Holder}} {{.StartYear}}, {{.CurrentYear}}",
+		},
+		License: config.License{
+			Enabled:    true,
+			Identifier: "MPL-2.0",
+			Format:     "SPDX-License-Identifier: {{.Identifier}}",
+		},
+		Files: config.Files{
+			CommentStyles: map[string]string{"go": "//"},
+		},
+		Detection: config.Detection{
+			MaxScanLines: 20,
+		},
+	}
+
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{
+			name:  "tabs between tokens",
+			input: "//\tCopyright\tIBM Corp.\t2014,\t2025\n//\tSPDX-License-Identifier:\tMPL-2.0\n\npackage main",
+		},
+		{
+			name:  "multiple spaces between tokens",
+			input: "//  Copyright  IBM   Corp.  2014, 2025\n// SPDX-License-Identifier:   MPL-2.0\n\npackage main",
+		},
+	}
+
+	fixer := NewFixer(cfg)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filePath := filepath.Join(tmpDir, "test.go")
+			if err := os.WriteFile(filePath, []byte(tt.input), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			if mustFixFile(t, fixer, filePath) {
+				t.Error("Expected header to be recognized as already correct")
+			}
+
+			content, err := os.ReadFile(filePath)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if string(content) != tt.input {
+				t.Errorf("Expected file to be unchanged, got:\n%s", string(content))
+			}
+		})
+	}
+}
+
+func TestFixer_NoticeLine(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	cfg := &config.Config{
+		Copyright: config.Copyright{
+			Holder:      "IBM Corp.",
+			StartYear:   2014,
+			CurrentYear: 2025,
+			Format:      "Copyright {{.Holder}} {{.StartYear}}, {{.CurrentYear}}",
+		},
+		License: config.License{
+			Enabled:    true,
+			Identifier: "Apache-2.0",
+			Format:     "SPDX-License-Identifier: {{.Identifier}}",
+			Notice:     "See NOTICE file",
+		},
+		Files: config.Files{
+			CommentStyles: map[string]string{"go": "//"},
+		},
+		Detection: config.Detection{
+			MaxScanLines: 20,
+		},
+	}
+
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{
+			name:  "missing header",
+			input: "package main\n",
+		},
+		{
+			name:  "header without notice",
+			input: "// Copyright IBM Corp. 2014, 2025\n// SPDX-License-Identifier: Apache-2.0\n\npackage main\n",
+		},
+	}
+
+	expected := `// Copyright IBM Corp. 2014, 2025
+// SPDX-License-Identifier: Apache-2.0
+// See NOTICE file
+
+package main
+`
+
+	fixer := NewFixer(cfg)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filePath := filepath.Join(tmpDir, "test.go")
+			if err := os.WriteFile(filePath, []byte(tt.input), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			if !mustFixFile(t, fixer, filePath) {
+				t.Fatal("Expected file to be fixed")
+			}
+
+			content, err := os.ReadFile(filePath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(content) != expected {
+				t.Errorf("Expected:\n%s\n\nGot:\n%s", expected, string(content))
+			}
+
+			// Second run must recognize the notice and leave the file alone
+			if mustFixFile(t, fixer, filePath) {
+				t.Error("Expected notice line to be recognized on second run")
+			}
+			if issue := NewChecker(cfg).checkFile(filePath); issue != nil {
+				t.Errorf("Expected no check issue, got: %s", issue.Problem)
+			}
+		})
+	}
+}
+
+func TestFixer_ShebangInterpreterCommentStyle(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	cfg := &config.Config{
+		Copyright: config.Copyright{
+			Holder:      "IBM Corp.",
+			StartYear:   2014,
+			CurrentYear: 2025,
+			Format:      "Copyright {{.Holder}} {{.StartYear}}, {{.CurrentYear}}",
+		},
+		License: config.License{
+			Enabled:    true,
+			Identifier: "MPL-2.0",
+			Format:     "SPDX-License-Identifier: {{.Identifier}}",
+		},
+		Files: config.Files{
+			CommentStyles: map[string]string{"py": "#"},
+		},
+		Detection: config.Detection{
+			MaxScanLines: 20,
+		},
+	}
+
+	tests := []struct {
+		name     string
+		filename string
+		input    string
+		expected string
+	}{
+		{
+			name:     "node shebang uses interpreter style",
+			filename: "tool.sh",
+			input:    "#!/usr/bin/env node\nconsole.log('hi')\n",
+			expected: "#!/usr/bin/env node\n// Copyright IBM Corp. 2014, 2025\n// SPDX-License-Identifier: MPL-2.0\n\nconsole.log('hi')\n",
+		},
+		{
+			name:     "configured extension style wins over interpreter",
+			filename: "tool.py",
+			input:    "#!/usr/bin/env node\nprint('hi')\n",
+			expected: "#!/usr/bin/env node\n# Copyright IBM Corp. 2014, 2025\n# SPDX-License-Identifier: MPL-2.0\n\nprint('hi')\n",
+		},
+	}
+
+	fixer := NewFixer(cfg)
+	checker := NewChecker(cfg)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filePath := filepath.Join(tmpDir, tt.filename)
+			if err := os.WriteFile(filePath, []byte(tt.input), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			if !mustFixFile(t, fixer, filePath) {
+				t.Fatal("Expected file to be fixed")
+			}
+
+			content, err := os.ReadFile(filePath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(content) != tt.expected {
+				t.Errorf("Expected:\n%s\n\nGot:\n%s", tt.expected, string(content))
+			}
+
+			if issue := checker.checkFile(filePath); issue != nil {
+				t.Errorf("Expected no check issue, got: %s", issue.Problem)
+			}
+		})
+	}
+}
+
+func TestFixer_WrongCommentSyntax(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	cfg := &config.Config{
+		Copyright: config.Copyright{
+			Holder:      "IBM Corp.",
+			StartYear:   2014,
+			CurrentYear: 2025,
+			Format:      "Copyright {{.Holder}} {{.StartYear}}, {{.CurrentYear}}",
+		},
+		License: config.License{
+			Enabled:    true,
+			Identifier: "MPL-2.0",
+			Format:     "SPDX-License-Identifier: {{.Identifier}}",
+		},
+		Files: config.Files{
+			CommentStyles: map[string]string{"go": "//", "yaml": "#", "md": "<!--"},
+		},
+		Detection: config.Detection{
+			MaxScanLines: 20,
+		},
+		ThirdParty: config.ThirdParty{
+			Action:   "above",
+			Patterns: []string{"Copyright.*[a-zA-Z0-9].*"},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		filename string
+		input    string
+		expected string
+	}{
+		{
+			name:     "block comment header in go file",
+			filename: "block.go",
+			input: `/*
+ * Copyright IBM Corp. 2014, 2025
+ * SPDX-License-Identifier: MPL-2.0
+ */
+
+package main`,
+			expected: `// Copyright IBM Corp. 2014, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package main`,
+		},
+		{
+			// List items and headings are markdown, not comments in the wrong syntax
+			name:     "list item and heading in markdown file",
+			filename: "credits.md",
+			input: `# Copyright IBM Corp. 2014, 2025
+
+* Copyright IBM Corp. 2014, 2025
+`,
+			expected: `<!-- Copyright IBM Corp. 2014, 2025 -->
+<!-- SPDX-License-Identifier: MPL-2.0 -->
+
+# Copyright IBM Corp. 2014, 2025
+
+* Copyright IBM Corp. 2014, 2025
+`,
+		},
+		{
+			name:     "hash header in go file",
+			filename: "wrong.go",
+			input: `# Copyright IBM Corp. 2014, 2025
+# SPDX-License-Identifier: MPL-2.0
+
+package main`,
+			expected: `// Copyright IBM Corp. 2014, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package main`,
+		},
+		{
+			name:     "leftover wrong syntax next to correct header",
+			filename: "leftover.go",
+			input: `// Copyright IBM Corp. 2014, 2025
+// SPDX-License-Identifier: MPL-2.0
+# Copyright IBM Corp. 2014, 2024
+
+package main`,
+			expected: `// Copyright IBM Corp. 2014, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package main`,
+		},
+		{
+			name:     "slash header in yaml file",
+			filename: "wrong.yaml",
+			input: `// Copyright IBM Corp. 2014, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+key: value`,
+			expected: `# Copyright IBM Corp. 2014, 2025
+# "SPDX-License-Identifier: MPL-2.0"
+
+key: value`,
+		},
+	}
+
+	fixer := NewFixer(cfg)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filePath := filepath.Join(tmpDir, tt.filename)
+			if err := os.WriteFile(filePath, []byte(tt.input), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			if !mustFixFile(t, fixer, filePath) {
+				t.Fatal("Expected file to be fixed")
+			}
+
+			content, err := os.ReadFile(filePath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(content) != tt.expected {
+				t.Errorf("Expected:\n%s\n\nGot:\n%s", tt.expected, string(content))
+			}
+		})
+	}
+}
+
+func TestFixer_FrontmatterFormats(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	cfg := &config.Config{
+		Copyright: config.Copyright{
+			Holder:      "IBM Corp.",
+			StartYear:   2014,
+			CurrentYear: 2025,
+			Format:      "Copyright {{.Holder}} {{.StartYear}}, {{.CurrentYear}}",
+		},
+		Files: config.Files{
+			CommentStyles: map[string]string{"md": "<!--"},
+			PlacementExceptions: config.PlacementExceptions{
+				Frontmatter:        []string{".md"},
+				FrontmatterFormats: []string{"yaml", "toml", "json"},
+			},
+		},
+		Detection: config.Detection{
+			MaxScanLines: 20,
+			RequireAtTop: true,
+		},
+	}
+
+	const header = "<!-- Copyright IBM Corp. 2014, 2025 -->\n"
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "yaml",
+			input:    "---\ntitle: Test\n---\n\n# Heading\n",
+			expected: "---\ntitle: Test\n---\n" + header + "\n# Heading\n",
+		},
+		{
+			name:     "toml",
+			input:    "+++\ntitle = \"Test\"\n+++\n\n# Heading\n",
+			expected: "+++\ntitle = \"Test\"\n+++\n" + header + "\n# Heading\n",
+		},
+		{
+			name:     "json with nested object",
+			input:    "{\n  \"title\": \"Test\",\n  \"params\": {\n    \"draft\": true\n  }\n}\n\n# Heading\n",
+			expected: "{\n  \"title\": \"Test\",\n  \"params\": {\n    \"draft\": true\n  }\n}\n" + header + "\n# Heading\n",
+		},
+		{
+			name:     "only frontmatter",
+			input:    "+++\ntitle = \"Test\"\n+++\n",
+			expected: "+++\ntitle = \"Test\"\n+++\n" + header,
+		},
+	}
+
+	fixer := NewFixer(cfg)
+	checker := NewChecker(cfg)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filePath := filepath.Join(tmpDir, "doc.md")
+			if err := os.WriteFile(filePath, []byte(tt.input), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			if issue := checker.checkFile(filePath); issue == nil {
+				t.Error("Expected a check issue before fixing")
+			}
+			mustFixFile(t, fixer, filePath)
+
+			content, err := os.ReadFile(filePath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(content) != tt.expected {
+				t.Errorf("Expected:\n%q\n\nGot:\n%q", tt.expected, string(content))
+			}
+
+			if mustFixFile(t, fixer, filePath) {
+				t.Error("Expected second run to leave the file unchanged")
+			}
+			if issue := checker.checkFile(filePath); issue != nil {
+				t.Errorf("Expected no check issue, got: %s", issue.Problem)
+			}
+		})
+	}
+
+	// Formats that are not configured are not frontmatter
+	yamlOnly := *cfg
+	yamlOnly.Files.PlacementExceptions.FrontmatterFormats = nil
+	if end := getFrontmatterEndNew([]string{"+++", "title = 1", "+++"}, &yamlOnly, "doc.md"); end != 0 {
+		t.Errorf("getFrontmatterEndNew() with only YAML frontmatter = %d, want 0 for TOML", end)
+	}
+}
+
+func TestFixer_FrontmatterBlankLine(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	newConfig := func(blankLine bool) *config.Config {
+		return &config.Config{
+			Copyright: config.Copyright{
+				Holder:      "IBM Corp.",
+				StartYear:   2014,
+				CurrentYear: 2025,
+				Format:      "Copyright {{.Holder}} {{.StartYear}}, {{.CurrentYear}}",
+			},
+			License: config.License{
+				Enabled:    true,
+				Identifier: "MPL-2.0",
+				Format:     "SPDX-License-Identifier: {{.Identifier}}",
+			},
+			Files: config.Files{
+				CommentStyles: map[string]string{"md": "<!--"},
+				PlacementExceptions: config.PlacementExceptions{
+					Frontmatter:               []string{".md"},
+					BlankLineAfterFrontmatter: blankLine,
+				},
+			},
+			Detection: config.Detection{
+				MaxScanLines: 20,
+				RequireAtTop: true,
+			},
+		}
+	}
+
+	inputs := map[string]string{
+		"no header":                "---\ntitle: Test\n---\n# Heading\n",
+		"no header blank":          "---\ntitle: Test\n---\n\n# Heading\n",
+		"header without blank":     "---\ntitle: Test\n---\n<!-- Copyright IBM Corp. 2014, 2025 -->\n<!-- SPDX-License-Identifier: MPL-2.0 -->\n\n# Heading\n",
+		"old header without blank": "---\ntitle: Test\n---\n<!-- Copyright IBM Corp. 2014, 2024 -->\n<!-- SPDX-License-Identifier: MPL-2.0 -->\n\n# Heading\n",
+		"old header with blank":    "---\ntitle: Test\n---\n\n<!-- Copyright IBM Corp. 2014, 2024 -->\n<!-- SPDX-License-Identifier: MPL-2.0 -->\n\n# Heading\n",
+	}
+
+	tests := []struct {
+		name      string
+		blankLine bool
+		expected  string
+	}{
+		{
+			name:      "blank line after frontmatter",
+			blankLine: true,
+			expected:  "---\ntitle: Test\n---\n\n<!-- Copyright IBM Corp. 2014, 2025 -->\n<!-- SPDX-License-Identifier: MPL-2.0 -->\n\n# Heading\n",
+		},
+		{
+			name:      "header directly after frontmatter",
+			blankLine: false,
+			expected:  "---\ntitle: Test\n---\n<!-- Copyright IBM Corp. 2014, 2025 -->\n<!-- SPDX-License-Identifier: MPL-2.0 -->\n\n# Heading\n",
+		},
+	}
+
+	for _, tt := range tests {
+		cfg := newConfig(tt.blankLine)
+		fixer := NewFixer(cfg)
+
+		for inputName, input := range inputs {
+			// The "directly after" layout only normalizes headers it rewrites
+			if !tt.blankLine && strings.HasPrefix(inputName, "old header with blank") {
+				continue
+			}
+
+			t.Run(tt.name+"/"+inputName, func(t *testing.T) {
+				filePath := filepath.Join(tmpDir, "doc.md")
+				if err := os.WriteFile(filePath, []byte(input), 0644); err != nil {
+					t.Fatal(err)
+				}
+
+				mustFixFile(t, fixer, filePath)
+
+				content, err := os.ReadFile(filePath)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if string(content) != tt.expected {
+					t.Errorf("Expected:\n%q\n\nGot:\n%q", tt.expected, string(content))
+				}
+
+				// Second run must be a no-op
+				if mustFixFile(t, fixer, filePath) {
+					t.Error("Expected second run to leave the file unchanged")
+				}
+				if issue := NewChecker(cfg).checkFile(filePath); issue != nil {
+					t.Errorf("Expected no check issue, got: %s", issue.Problem)
+				}
+			})
+		}
+	}
+}
+
+func TestFixer_HeaderOnlyFiles(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	inputs := map[string]string{
+		"empty":                     "",
+		"blank lines only":          "\n\n",
+		"canonical header":          "// Copyright IBM Corp. 2014, 2025\n// SPDX-License-Identifier: MPL-2.0\n",
+		"outdated header":           "// Copyright IBM Corp. 2014, 2024\n// SPDX-License-Identifier: MPL-2.0\n",
+		"outdated header no EOL":    "// Copyright IBM Corp. 2014, 2024\n// SPDX-License-Identifier: MPL-2.0",
+		"outdated header and blank": "// Copyright IBM Corp. 2014, 2024\n// SPDX-License-Identifier: MPL-2.0\n\n\n",
+		"copyright only":            "// Copyright IBM Corp. 2014, 2025\n",
+		"replaced header":           "// Copyright (c) HashiCorp, Inc.\n// SPDX-License-Identifier: MPL-2.0\n",
+	}
+
+	expected := "// Copyright IBM Corp. 2014, 2025\n// SPDX-License-Identifier: MPL-2.0\n"
+
+	for _, action := range []string{"above", "below", "replace", "leave"} {
+		cfg := &config.Config{
+			Copyright: config.Copyright{
+				Holder:      "IBM Corp.",
+				StartYear:   2014,
+				CurrentYear: 2025,
+				Format:      "Copyright {{.Holder}} {{.StartYear}}, {{.CurrentYear}}",
+			},
+			License: config.License{
+				Enabled:    true,
+				Identifier: "MPL-2.0",
+				Format:     "SPDX-License-Identifier: {{.Identifier}}",
+			},
+			Files: config.Files{
+				CommentStyles: map[string]string{"go": "//"},
+			},
+			Detection: config.Detection{
+				ReplacePatterns: []string{"Copyright.*HashiCorp"},
+				MaxScanLines:    20,
+			},
+			ThirdParty: config.ThirdParty{
+				Action:   action,
+				Patterns: []string{"Copyright.*Oracle"},
+			},
+		}
+		fixer := NewFixer(cfg)
+
+		for name, input := range inputs {
+			t.Run(action+"/"+name, func(t *testing.T) {
+				filePath := filepath.Join(tmpDir, "header.go")
+				if err := os.WriteFile(filePath, []byte(input), 0644); err != nil {
+					t.Fatal(err)
+				}
+
+				mustFixFile(t, fixer, filePath)
+
+				content, err := os.ReadFile(filePath)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if string(content) != expected {
+					t.Errorf("Expected:\n%q\n\nGot:\n%q", expected, string(content))
+				}
+
+				if mustFixFile(t, fixer, filePath) {
+					t.Error("Expected second run to leave the header-only file unchanged")
+				}
+				content, err = os.ReadFile(filePath)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if string(content) != expected {
+					t.Errorf("Second run changed content to:\n%q", string(content))
+				}
+			})
+		}
+	}
+}
+
+func TestFixer_HTMLCommentClosingDelimiter(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	cfg := &config.Config{
+		Copyright: config.Copyright{
+			Holder:      "IBM Corp.",
+			StartYear:   2014,
+			CurrentYear: 2025,
+			Format:      "Copyright {{.Holder}} {{.StartYear}}, {{.CurrentYear}}",
+		},
+		License: config.License{
+			Enabled:    true,
+			Identifier: "MPL-2.0",
+			Format:     "SPDX-License-Identifier: {{.Identifier}}",
+		},
+		Files: config.Files{
+			CommentStyles: map[string]string{"md": "<!--"},
+		},
+		Detection: config.Detection{
+			MaxScanLines: 20,
+		},
+	}
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "closing delimiter on separate line",
+			input:    "<!-- Copyright IBM Corp. 2014, 2025\n-->\n<!-- SPDX-License-Identifier: MPL-2.0 -->\n\n# Title\n",
+			expected: "<!-- Copyright IBM Corp. 2014, 2025 -->\n<!-- SPDX-License-Identifier: MPL-2.0 -->\n\n# Title\n",
+		},
+		{
+			name:     "outdated header with closing delimiter on separate line",
+			input:    "<!-- Copyright IBM Corp. 2014, 2024\n-->\n<!-- SPDX-License-Identifier: MPL-2.0\n-->\n\n# Title\n",
+			expected: "<!-- Copyright IBM Corp. 2014, 2025 -->\n<!-- SPDX-License-Identifier: MPL-2.0 -->\n\n# Title\n",
+		},
+		{
+			name:     "both lines in one comment",
+			input:    "<!--\n  Copyright IBM Corp. 2014, 2024\n  SPDX-License-Identifier: MPL-2.0\n-->\n\n# Title\n",
+			expected: "<!-- Copyright IBM Corp. 2014, 2025 -->\n<!-- SPDX-License-Identifier: MPL-2.0 -->\n\n# Title\n",
+		},
+		{
+			name:     "content after closing delimiter",
+			input:    "<!-- Copyright IBM Corp. 2014, 2025 --> <h1>Title</h1>\n<!-- SPDX-License-Identifier: MPL-2.0 -->\n\nBody\n",
+			expected: "<!-- Copyright IBM Corp. 2014, 2025 -->\n<!-- SPDX-License-Identifier: MPL-2.0 -->\n\n<h1>Title</h1>\nBody\n",
+		},
+		{
+			name:     "unrelated multi-line comment preserved",
+			input:    "<!-- Copyright IBM Corp. 2014, 2025 -->\n<!-- SPDX-License-Identifier: MPL-2.0 -->\n\n<!-- TODO:\n  expand this section\n-->\n",
+			expected: "<!-- Copyright IBM Corp. 2014, 2025 -->\n<!-- SPDX-License-Identifier: MPL-2.0 -->\n\n<!-- TODO:\n  expand this section\n-->\n",
+		},
+	}
+
+	fixer := NewFixer(cfg)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filePath := filepath.Join(tmpDir, "doc.md")
+			if err := os.WriteFile(filePath, []byte(tt.input), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			mustFixFile(t, fixer, filePath)
+
+			content, err := os.ReadFile(filePath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(content) != tt.expected {
+				t.Errorf("Expected:\n%q\n\nGot:\n%q", tt.expected, string(content))
+			}
+
+			if mustFixFile(t, fixer, filePath) {
+				t.Error("Expected second run to make no changes")
+			}
+		})
+	}
+}
+
+func TestFixer_YearBumpPreservesThirdParty(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	oracle := "//Copyright (c) 2025, Oracle and/or its affiliates.\t All rights reserved.  "
+
+	tests := []struct {
+		name     string
+		action   string
+		input    string
+		expected string
+	}{
+		{
+			name:     "above with third-party below header",
+			action:   "above",
+			input:    "// Copyright IBM Corp. 2014, 2024\n// SPDX-License-Identifier: MPL-2.0\n" + oracle + "\n\npackage main\n",
+			expected: "// Copyright IBM Corp. 2014, 2025\n// SPDX-License-Identifier: MPL-2.0\n" + oracle + "\n\npackage main\n",
+		},
+		{
+			name:     "above with third-party separated by blank line",
+			action:   "above",
+			input:    "// Copyright IBM Corp. 2014, 2024\n// SPDX-License-Identifier: MPL-2.0\n\n" + oracle + "\n\npackage main\n",
+			expected: "// Copyright IBM Corp. 2014, 2025\n// SPDX-License-Identifier: MPL-2.0\n\n" + oracle + "\n\npackage main\n",
+		},
+		{
+			name:     "above keeps existing third-party position on top",
+			action:   "above",
+			input:    oracle + "\n// Copyright IBM Corp. 2014, 2024\n// SPDX-License-Identifier: MPL-2.0\n\npackage main\n",
+			expected: oracle + "\n// Copyright IBM Corp. 2014, 2025\n// SPDX-License-Identifier: MPL-2.0\n\npackage main\n",
+		},
+		{
+			name:     "below",
+			action:   "below",
+			input:    oracle + "\n// Copyright IBM Corp. 2014, 2024\n// SPDX-License-Identifier: MPL-2.0\n\npackage main\n",
+			expected: oracle + "\n// Copyright IBM Corp. 2014, 2025\n// SPDX-License-Identifier: MPL-2.0\n\npackage main\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{
+				Copyright: config.Copyright{
+					Holder:      "IBM Corp.",
+					StartYear:   2014,
+					CurrentYear: 2025,
+					Format:      "Copyright {{.Holder}} {{.StartYear}}, {{.CurrentYear}}",
+				},
+				License: config.License{
+					Enabled:    true,
+					Identifier: "MPL-2.0",
+					Format:     "SPDX-License-Identifier: {{.Identifier}}",
+				},
+				Files: config.Files{
+					CommentStyles: map[string]string{"go": "//"},
+				},
+				Detection: config.Detection{
+					MaxScanLines: 20,
+					RequireAtTop: true,
+				},
+				ThirdParty: config.ThirdParty{
+					Action:   tt.action,
+					Patterns: []string{"Copyright.*Oracle"},
+				},
+			}
+			fixer := NewFixer(cfg)
+
+			filePath := filepath.Join(tmpDir, "main.go")
+			if err := os.WriteFile(filePath, []byte(tt.input), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			if !mustFixFile(t, fixer, filePath) {
+				t.Fatal("Expected the year update to be applied")
+			}
+
+			content, err := os.ReadFile(filePath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(content) != tt.expected {
+				t.Errorf("Expected:\n%q\n\nGot:\n%q", tt.expected, string(content))
+			}
+			if !strings.Contains(string(content), "\n"+oracle+"\n") && !strings.HasPrefix(string(content), oracle+"\n") {
+				t.Error("Third-party line is not byte-identical")
+			}
+
+			// Check agrees that nothing is left to fix, third-party line above the header or not
+			if issue := NewChecker(cfg).checkFile(filePath); issue != nil {
+				t.Errorf("check after the year bump reported %q", issue.Problem)
+			}
+			if mustFixFile(t, fixer, filePath) {
+				t.Error("Expected second run to make no changes")
+			}
+		})
+	}
+}
+
+func TestFixer_SmartExtensionOverride(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	cfg := &config.Config{
+		Copyright: config.Copyright{
+			Holder:      "IBM Corp.",
+			StartYear:   2014,
+			CurrentYear: 2025,
+			Format:      "Copyright {{.Holder}} {{.StartYear}}, {{.CurrentYear}}",
+		},
+		Files: config.Files{
+			SmartExtensions: []string{".tmpl"},
+			SmartExtensionOverrides: []config.SmartExtensionOverride{
+				{Pattern: "*.md.tmpl", Extension: ".md"},
+				{Pattern: "*.sh.tmpl", Extension: ".sh"},
+			},
+			CommentStyles: map[string]string{"go": "//", "md": "<!--", "tf": "#", "sh": "#"},
+		},
+	}
+	fixer := NewFixer(cfg)
+	checker := NewChecker(cfg)
+
+	tests := []struct {
+		name     string
+		file     string
+		input    string
+		expected string
+	}{
+		{
+			// The heuristic would detect HCL from the resource block
+			name:     "forced mapping overrides heuristic",
+			file:     "resource.md.tmpl",
+			input:    "Example:\n\nresource \"aws_instance\" \"example\" {}\n",
+			expected: "<!-- Copyright IBM Corp. 2014, 2025 -->\n\nExample:\n\nresource \"aws_instance\" \"example\" {}\n",
+		},
+		{
+			// Without a mapping, null bytes make the file look binary and it is skipped
+			name:     "forced mapping bypasses binary detection",
+			file:     "odd.md.tmpl",
+			input:    "text with a stray \x00 byte\n",
+			expected: "<!-- Copyright IBM Corp. 2014, 2025 -->\n\ntext with a stray \x00 byte\n",
+		},
+		{
+			name:     "forced mapping to a hash comment style",
+			file:     "install.sh.tmpl",
+			input:    "echo {{ .Name }}\n",
+			expected: "# Copyright IBM Corp. 2014, 2025\n\necho {{ .Name }}\n",
+		},
+		{
+			name:     "unmapped file uses heuristic",
+			file:     "main.tf.tmpl",
+			input:    "resource \"aws_instance\" \"example\" {}\n",
+			expected: "# Copyright IBM Corp. 2014, 2025\n\nresource \"aws_instance\" \"example\" {}\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filePath := filepath.Join(tmpDir, tt.file)
+			if err := os.WriteFile(filePath, []byte(tt.input), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			mustFixFile(t, fixer, filePath)
+
+			content, err := os.ReadFile(filePath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(content) != tt.expected {
+				t.Errorf("Expected:\n%q\n\nGot:\n%q", tt.expected, string(content))
+			}
+
+			// check resolves the extension as fix does, so it accepts what fix wrote
+			if issue := checker.checkFile(filePath); issue != nil {
+				t.Errorf("Expected no issue after fixing, got %q", issue.Problem)
+			}
+			if issue := checker.verifyFile(filePath); issue != nil {
+				t.Errorf("Expected verify to pass after fixing, got %q", issue.Problem)
+			}
+		})
+	}
+}
+
+func TestFixer_HeaderBanner(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	cfg := &config.Config{
+		Copyright: config.Copyright{
+			Holder:       "IBM Corp.",
+			StartYear:    2014,
+			CurrentYear:  2025,
+			Format:       "Copyright {{.Holder}} {{.StartYear}}, {{.CurrentYear}}",
+			BannerBefore: "==========",
+			BannerAfter:  "==========",
+		},
+		License: config.License{
+			Enabled:    true,
+			Identifier: "MPL-2.0",
+			Format:     "SPDX-License-Identifier: {{.Identifier}}",
+		},
+		Files: config.Files{
+			CommentStyles: map[string]string{"go": "//"},
+		},
+		Detection: config.Detection{
+			MaxScanLines: 20,
+		},
+	}
+
+	expected := "// ==========\n// Copyright IBM Corp. 2014, 2025\n// SPDX-License-Identifier: MPL-2.0\n// ==========\n\npackage main\n"
+
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{name: "no header", input: "package main\n"},
+		{name: "header without banners", input: "// Copyright IBM Corp. 2014, 2025\n// SPDX-License-Identifier: MPL-2.0\n\npackage main\n"},
+		{name: "outdated header with banners", input: "// ==========\n// Copyright IBM Corp. 2014, 2024\n// SPDX-License-Identifier: MPL-2.0\n// ==========\n\npackage main\n"},
+		{name: "only one banner", input: "// ==========\n// Copyright IBM Corp. 2014, 2025\n// SPDX-License-Identifier: MPL-2.0\n\npackage main\n"},
+	}
+
+	fixer := NewFixer(cfg)
+	checker := NewChecker(cfg)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filePath := filepath.Join(tmpDir, "main.go")
+			if err := os.WriteFile(filePath, []byte(tt.input), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			if issue := checker.checkFile(filePath); issue == nil {
+				t.Error("Expected checker to report the file before fixing")
+			}
+
+			mustFixFile(t, fixer, filePath)
+
+			content, err := os.ReadFile(filePath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(content) != expected {
+				t.Errorf("Expected:\n%q\n\nGot:\n%q", expected, string(content))
+			}
+
+			if mustFixFile(t, fixer, filePath) {
+				t.Error("Expected second run to make no changes")
+			}
+			if issue := checker.checkFile(filePath); issue != nil {
+				t.Errorf("Expected no issue after fixing, got %q", issue.Problem)
+			}
+		})
+	}
+}
+
+func TestFixer_TargetedLineUpdate(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	cfg := &config.Config{
+		Copyright: config.Copyright{
+			Holder:      "IBM Corp.",
+			StartYear:   2014,
+			CurrentYear: 2025,
+			Format:      "Copyright {{.Holder}} {{.StartYear}}, {{.CurrentYear}}",
+		},
+		License: config.License{
+			Enabled:    true,
+			Identifier: "MPL-2.0",
+			Format:     "SPDX-License-Identifier: {{.Identifier}}",
+		},
+		Files: config.Files{
+			CommentStyles: map[string]string{"go": "//"},
+		},
+		Detection: config.Detection{
+			MaxScanLines: 20,
+		},
+	}
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			// The correct copyright line keeps its unusual spacing byte-for-byte
+			name:     "only license identifier wrong",
+			input:    "//  Copyright IBM Corp. 2014,\t2025\n// SPDX-License-Identifier: MIT\n\npackage main\n",
+			expected: "//  Copyright IBM Corp. 2014,\t2025\n// SPDX-License-Identifier: MPL-2.0\n\npackage main\n",
+		},
+		{
+			name:     "only copyright year outdated",
+			input:    "// Copyright IBM Corp. 2014, 2024\n//   SPDX-License-Identifier:   MPL-2.0\n\npackage main\n",
+			expected: "// Copyright IBM Corp. 2014, 2025\n//   SPDX-License-Identifier:   MPL-2.0\n\npackage main\n",
+		},
+		{
+			name:     "both outdated",
+			input:    "// Copyright IBM Corp. 2014, 2024\n// SPDX-License-Identifier: MIT\n\npackage main\n",
+			expected: "// Copyright IBM Corp. 2014, 2025\n// SPDX-License-Identifier: MPL-2.0\n\npackage main\n",
+		},
+		{
+			name:     "header below code is moved to the top",
+			input:    "package main\n// Copyright IBM Corp. 2014, 2025\n// SPDX-License-Identifier: MIT\n\nfunc main() {}\n",
+			expected: "// Copyright IBM Corp. 2014, 2025\n// SPDX-License-Identifier: MPL-2.0\n\npackage main\nfunc main() {}\n",
+		},
+	}
+
+	fixer := NewFixer(cfg)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filePath := filepath.Join(tmpDir, "main.go")
+			if err := os.WriteFile(filePath, []byte(tt.input), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			if !mustFixFile(t, fixer, filePath) {
+				t.Fatal("Expected file to be fixed")
+			}
+
+			content, err := os.ReadFile(filePath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(content) != tt.expected {
+				t.Errorf("Expected:\n%q\n\nGot:\n%q", tt.expected, string(content))
+			}
+
+			if mustFixFile(t, fixer, filePath) {
+				t.Error("Expected second run to make no changes")
+			}
+		})
+	}
+}
+
+// mustFixFile runs fixFile and fails the test if it returns an error
+func mustFixFile(t *testing.T, fixer *Fixer, file string) bool {
+	t.Helper()
+	fixed, err := fixer.fixFile(file)
+	if err != nil {
+		t.Fatalf("fixFile() error = %v", err)
+	}
+	return fixed
+}
+
+func TestFixer_LegacyFormatUpgrade(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	cfg := &config.Config{
+		Copyright: config.Copyright{
+			Holder:        "IBM Corp.",
+			StartYear:     2014,
+			CurrentYear:   2025,
+			Format:        "Copyright {{.Holder}} {{.StartYear}}, {{.CurrentYear}}",
+			LegacyFormats: []string{"Copyright (c) {{.StartYear}}-{{.CurrentYear}} {{.Holder}} All rights reserved."},
+		},
+		License: config.License{
+			Enabled:    true,
+			Identifier: "MPL-2.0",
+			Format:     "SPDX-License-Identifier: {{.Identifier}}",
+		},
+		Files: config.Files{
+			CommentStyles: map[string]string{"go": "//"},
+		},
+		Detection: config.Detection{
+			MaxScanLines: 20,
+		},
+		ThirdParty: config.ThirdParty{
+			Action:   "above",
+			Patterns: []string{"Copyright.*"},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "legacy header upgraded",
+			input:    "// Copyright (c) 2014-2023 IBM Corp. All rights reserved.\n// SPDX-License-Identifier: MPL-2.0\n\npackage main\n",
+			expected: "// Copyright IBM Corp. 2014, 2025\n// SPDX-License-Identifier: MPL-2.0\n\npackage main\n",
+		},
+		{
+			name:     "legacy header without license upgraded",
+			input:    "// Copyright (c) 2014-2023 IBM Corp. All rights reserved.\n\npackage main\n",
+			expected: "// Copyright IBM Corp. 2014, 2025\n// SPDX-License-Identifier: MPL-2.0\n\npackage main\n",
+		},
+		{
+			// Another holder's header in the same shape is third-party, not ours
+			name:     "other holder kept",
+			input:    "// Copyright (c) 2014-2023 Oracle. All rights reserved.\n\npackage main\n",
+			expected: "// Copyright IBM Corp. 2014, 2025\n// SPDX-License-Identifier: MPL-2.0\n// Copyright (c) 2014-2023 Oracle. All rights reserved.\n\npackage main\n",
+		},
+	}
+
+	fixer := NewFixer(cfg)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filePath := filepath.Join(tmpDir, "main.go")
+			if err := os.WriteFile(filePath, []byte(tt.input), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			mustFixFile(t, fixer, filePath)
+
+			content, err := os.ReadFile(filePath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(content) != tt.expected {
+				t.Errorf("Expected:\n%q\n\nGot:\n%q", tt.expected, string(content))
+			}
+
+			if mustFixFile(t, fixer, filePath) {
+				t.Error("Expected second run to make no changes")
+			}
+		})
+	}
+}
+
+func TestFixer_LargeFileStreaming(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	cfg := &config.Config{
+		Copyright: config.Copyright{
+			Holder:      "IBM Corp.",
+			StartYear:   2014,
+			CurrentYear: 2025,
+			Format:      "Copyright {{.Holder}} {{.StartYear}}, {{.CurrentYear}}",
+		},
+		License: config.License{
+			Enabled:    true,
+			Identifier: "MPL-2.0",
+			Format:     "SPDX-License-Identifier: {{.Identifier}}",
+		},
+		Files: config.Files{
+			CommentStyles: map[string]string{"go": "//"},
+			PlacementExceptions: config.PlacementExceptions{
+				Frontmatter: []string{".go"},
+			},
+		},
+		Detection: config.Detection{
+			MaxScanLines: 20,
+		},
+	}
+
+	var b strings.Builder
+	for i := range 20000 {
+		fmt.Fprintf(&b, "var v%d = %d // Copyright IBM Corp. 2014, 2020\n", i, i)
+	}
+	body := b.String()
+	header := "// Copyright IBM Corp. 2014, 2025\n// SPDX-License-Identifier: MPL-2.0\n"
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "missing header",
+			input:    "package main\n\n" + body,
+			expected: header + "\npackage main\n\n" + body,
+		},
+		{
+			name:     "outdated header",
+			input:    "// Copyright IBM Corp. 2014, 2024\n// SPDX-License-Identifier: MPL-2.0\n\npackage main\n\n" + body,
+			expected: header + "\npackage main\n\n" + body,
+		},
+		{
+			name:     "no trailing newline",
+			input:    "package main\n\n" + strings.TrimSuffix(body, "\n"),
+			expected: header + "\npackage main\n\n" + strings.TrimSuffix(body, "\n"),
+		},
+		{
+			// The header goes after frontmatter that is longer than the initial read
+			name:     "long frontmatter",
+			input:    "---\n" + strings.Repeat("key: value\n", 200) + "---\n" + body,
+			expected: "---\n" + strings.Repeat("key: value\n", 200) + "---\n" + header + "\n" + body,
+		},
+	}
+
+	fixer := NewFixer(cfg)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filePath := filepath.Join(tmpDir, "large.go")
+			if err := os.WriteFile(filePath, []byte(tt.input), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			if !mustFixFile(t, fixer, filePath) {
+				t.Fatal("Expected file to be fixed")
+			}
+
+			content, err := os.ReadFile(filePath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(content) != tt.expected {
+				t.Errorf("Expected %d bytes starting:\n%q\n\nGot %d bytes starting:\n%q",
+					len(tt.expected), tt.expected[:200], len(content), string(content[:min(200, len(content))]))
+			}
+
+			if mustFixFile(t, fixer, filePath) {
+				t.Error("Expected second run to make no changes")
+			}
+		})
+	}
+}
+
+func BenchmarkFixer_fixFile_LargeFile(b *testing.B) {
+	cfg := &config.Config{
+		Copyright: config.Copyright{
+			Holder:      "IBM Corp.",
+			StartYear:   2014,
+			CurrentYear: 2025,
+			Format:      "Copyright {{.Holder}} {{.StartYear}}, {{.CurrentYear}}",
+		},
+		License: config.License{
+			Enabled:    true,
+			Identifier: "MPL-2.0",
+			Format:     "SPDX-License-Identifier: {{.Identifier}}",
+		},
+		Files: config.Files{
+			CommentStyles: map[string]string{"go": "//"},
+		},
+		Detection: config.Detection{
+			MaxScanLines: 20,
+		},
+	}
+	fixer := NewFixer(cfg)
+
+	var body strings.Builder
+	for i := range 200000 {
+		fmt.Fprintf(&body, "var v%d = %d\n", i, i)
+	}
+
+	inputs := map[string]string{
+		"correct": "// Copyright IBM Corp. 2014, 2025\n// SPDX-License-Identifier: MPL-2.0\n\npackage main\n\n" + body.String(),
+		"missing": "package main\n\n" + body.String(),
+	}
+
+	for name, input := range inputs {
+		b.Run(name, func(b *testing.B) {
+			filePath := filepath.Join(b.TempDir(), "large.go")
+			b.ReportAllocs()
+			for b.Loop() {
+				b.StopTimer()
+				if err := os.WriteFile(filePath, []byte(input), 0644); err != nil {
+					b.Fatal(err)
+				}
+				b.StartTimer()
+
+				if _, err := fixer.fixFile(filePath); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func TestFixer_StripTrailingText(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	cfg := &config.Config{
+		Copyright: config.Copyright{
+			Holder:            "IBM Corp.",
+			StartYear:         2014,
+			CurrentYear:       2025,
+			Format:            "Copyright {{.Holder}} {{.StartYear}}, {{.CurrentYear}}",
+			StripTrailingText: true,
+		},
+		License: config.License{
+			Enabled:    true,
+			Identifier: "MPL-2.0",
+			Format:     "SPDX-License-Identifier: {{.Identifier}}",
+		},
+		Files: config.Files{
+			CommentStyles: map[string]string{"go": "//"},
+		},
+		Detection: config.Detection{
+			MaxScanLines: 20,
+		},
+	}
+
+	expected := "// Copyright IBM Corp. 2014, 2025\n// SPDX-License-Identifier: MPL-2.0\n\npackage main\n"
+
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{name: "suffix after current header", input: "// Copyright IBM Corp. 2014, 2025. All rights reserved.\n// SPDX-License-Identifier: MPL-2.0\n\npackage main\n"},
+		{name: "suffix after outdated header", input: "// Copyright IBM Corp. 2014, 2020 All rights reserved.\n// SPDX-License-Identifier: MPL-2.0\n\npackage main\n"},
+		{name: "suffix without years", input: "// Copyright IBM Corp. All rights reserved.\n// SPDX-License-Identifier: MPL-2.0\n\npackage main\n"},
+		{name: "years before holder", input: "// Copyright (c) 2014-2020 IBM Corp. All rights reserved.\n// SPDX-License-Identifier: MPL-2.0\n\npackage main\n"},
+	}
+
+	fixer := NewFixer(cfg)
+	checker := NewChecker(cfg)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filePath := filepath.Join(tmpDir, "main.go")
+			if err := os.WriteFile(filePath, []byte(tt.input), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			if issue := checker.checkFile(filePath); issue == nil {
+				t.Error("Expected checker to report the trailing text")
+			}
+
+			mustFixFile(t, fixer, filePath)
+
+			content, err := os.ReadFile(filePath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(content) != expected {
+				t.Errorf("Expected:\n%q\n\nGot:\n%q", expected, string(content))
+			}
+
+			if mustFixFile(t, fixer, filePath) {
+				t.Error("Expected second run to make no changes")
+			}
+			if issue := checker.checkFile(filePath); issue != nil {
+				t.Errorf("Expected no issue after fixing, got %q", issue.Problem)
+			}
+		})
+	}
+}
+
+func TestFixer_DocumentationFormats(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	// No comment_styles configured: .rst and .adoc use the built-in defaults
+	cfg := &config.Config{
+		Copyright: config.Copyright{
+			Holder:      "IBM Corp.",
+			StartYear:   2014,
+			CurrentYear: 2025,
+			Format:      "Copyright {{.Holder}} {{.StartYear}}, {{.CurrentYear}}",
+		},
+		License: config.License{
+			Enabled:    true,
+			Identifier: "MPL-2.0",
+			Format:     "SPDX-License-Identifier: {{.Identifier}}",
+		},
+		Files: config.Files{
+			Extensions: []string{".rst", ".adoc"},
+		},
+		Detection: config.Detection{
+			MaxScanLines: 20,
+		},
+	}
+
+	tests := []struct {
+		name     string
+		fileName string
+		input    string
+		expected string
+	}{
+		{
+			name:     "rst title",
+			fileName: "index.rst",
+			input:    "Guide\n=====\n\nSome text.\n",
+			expected: ".. Copyright IBM Corp. 2014, 2025\n.. SPDX-License-Identifier: MPL-2.0\n\nGuide\n=====\n\nSome text.\n",
+		},
+		{
+			name:     "rst leading directive",
+			fileName: "index.rst",
+			input:    ".. _guide:\n\nGuide\n=====\n",
+			expected: ".. Copyright IBM Corp. 2014, 2025\n.. SPDX-License-Identifier: MPL-2.0\n\n.. _guide:\n\nGuide\n=====\n",
+		},
+		{
+			name:     "rst outdated header",
+			fileName: "index.rst",
+			input:    ".. Copyright IBM Corp. 2014, 2020\n.. SPDX-License-Identifier: MPL-2.0\n\nGuide\n=====\n",
+			expected: ".. Copyright IBM Corp. 2014, 2025\n.. SPDX-License-Identifier: MPL-2.0\n\nGuide\n=====\n",
+		},
+		{
+			name:     "adoc document header",
+			fileName: "guide.adoc",
+			input:    "= Guide\n:toc:\n\nSome text.\n",
+			expected: "// Copyright IBM Corp. 2014, 2025\n// SPDX-License-Identifier: MPL-2.0\n\n= Guide\n:toc:\n\nSome text.\n",
+		},
+	}
+
+	fixer := NewFixer(cfg)
+	checker := NewChecker(cfg)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filePath := filepath.Join(tmpDir, tt.fileName)
+			if err := os.WriteFile(filePath, []byte(tt.input), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			mustFixFile(t, fixer, filePath)
+
+			content, err := os.ReadFile(filePath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(content) != tt.expected {
+				t.Errorf("Expected:\n%q\n\nGot:\n%q", tt.expected, string(content))
+			}
+
+			if mustFixFile(t, fixer, filePath) {
+				t.Error("Expected second run to make no changes")
+			}
+			if issue := checker.checkFile(filePath); issue != nil {
+				t.Errorf("Expected no issue after fixing, got %q", issue.Problem)
+			}
+		})
+	}
+}
+
+func TestFixer_Force(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	cfg := &config.Config{
+		Copyright: config.Copyright{
+			Holder:      "IBM Corp.",
+			StartYear:   2014,
+			CurrentYear: 2025,
+			Format:      "Copyright {{.Holder}} {{.StartYear}}, {{.CurrentYear}}",
+		},
+		License: config.License{
+			Enabled:    true,
+			Identifier: "MPL-2.0",
+			Format:     "SPDX-License-Identifier: {{.Identifier}}",
+		},
+		Files: config.Files{
+			CommentStyles: map[string]string{"go": "//"},
+		},
+		Detection: config.Detection{
+			MaxScanLines: 20,
+		},
+	}
+
+	canonical := "// Copyright IBM Corp. 2014, 2025\n// SPDX-License-Identifier: MPL-2.0\n\npackage main\n"
+
+	tests := []struct {
+		name     string
+		input    string
+		force    bool
+		expected string
+		changed  bool
+	}{
+		{
+			name:     "oddly spaced header without force",
+			input:    "//  Copyright  IBM   Corp.  2014, 2025\n// SPDX-License-Identifier:   MPL-2.0\n\npackage main\n",
+			expected: "//  Copyright  IBM   Corp.  2014, 2025\n// SPDX-License-Identifier:   MPL-2.0\n\npackage main\n",
+		},
+		{
+			name:     "oddly spaced header with force",
+			input:    "//  Copyright  IBM   Corp.  2014, 2025\n// SPDX-License-Identifier:   MPL-2.0\n\npackage main\n",
+			force:    true,
+			expected: canonical,
+			changed:  true,
+		},
+		{
+			name:     "canonical header with force",
+			input:    canonical,
+			force:    true,
+			expected: canonical,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filePath := filepath.Join(tmpDir, "main.go")
+			if err := os.WriteFile(filePath, []byte(tt.input), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			fixer := NewFixer(cfg)
+			fixer.Force = tt.force
+			if changed := mustFixFile(t, fixer, filePath); changed != tt.changed {
+				t.Errorf("fixFile() changed = %v, want %v", changed, tt.changed)
+			}
+
+			content, err := os.ReadFile(filePath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(content) != tt.expected {
+				t.Errorf("Expected:\n%q\n\nGot:\n%q", tt.expected, string(content))
+			}
+		})
+	}
+}
+
+func TestFixer_PackageDocComment(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	cfg := &config.Config{
+		Copyright: config.Copyright{
+			Holder:      "IBM Corp.",
+			StartYear:   2014,
+			CurrentYear: 2025,
+			Format:      "Copyright {{.Holder}} {{.StartYear}}, {{.CurrentYear}}",
+		},
+		License: config.License{
+			Enabled:    true,
+			Identifier: "MPL-2.0",
+			Format:     "SPDX-License-Identifier: {{.Identifier}}",
+		},
+		Files: config.Files{
+			CommentStyles: map[string]string{"go": "//"},
+		},
+		Detection: config.Detection{
+			MaxScanLines: 20,
+		},
+	}
+
+	header := "// Copyright IBM Corp. 2014, 2025\n// SPDX-License-Identifier: MPL-2.0\n\n"
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "header added above doc comment",
+			input:    "// Package foo does X.\n//\n// More detail.\npackage foo\n",
+			expected: header + "// Package foo does X.\n//\n// More detail.\npackage foo\n",
+		},
+		{
+			name:     "header added above block doc comment",
+			input:    "/*\nPackage foo does X.\n*/\npackage foo\n",
+			expected: header + "/*\nPackage foo does X.\n*/\npackage foo\n",
+		},
+		{
+			name:     "correct header merged into doc comment",
+			input:    "// Copyright IBM Corp. 2014, 2025\n// SPDX-License-Identifier: MPL-2.0\n// Package foo does X.\npackage foo\n",
+			expected: header + "// Package foo does X.\npackage foo\n",
+		},
+		{
+			name:     "outdated header merged into doc comment",
+			input:    "// Copyright IBM Corp. 2014, 2020\n// SPDX-License-Identifier: MPL-2.0\n// Package foo does X.\npackage foo\n",
+			expected: header + "// Package foo does X.\npackage foo\n",
+		},
+		{
+			name:     "header directly above package clause",
+			input:    "// Copyright IBM Corp. 2014, 2025\n// SPDX-License-Identifier: MPL-2.0\npackage foo\n",
+			expected: header + "package foo\n",
+		},
+		{
+			name:     "build constraint between header and package",
+			input:    header + "//go:build linux\n\n// Package foo does X.\npackage foo\n",
+			expected: header + "//go:build linux\n\n// Package foo does X.\npackage foo\n",
+		},
+	}
+
+	fixer := NewFixer(cfg)
+	checker := NewChecker(cfg)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filePath := filepath.Join(tmpDir, "doc.go")
+			if err := os.WriteFile(filePath, []byte(tt.input), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			if issue := checker.checkFile(filePath); (issue != nil) != (tt.input != tt.expected) {
+				t.Errorf("checkFile() = %v, want an issue only if the file needs fixing", issue)
+			}
+
+			mustFixFile(t, fixer, filePath)
+
+			content, err := os.ReadFile(filePath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(content) != tt.expected {
+				t.Errorf("Expected:\n%q\n\nGot:\n%q", tt.expected, string(content))
+			}
+
+			if mustFixFile(t, fixer, filePath) {
+				t.Error("Expected second run to make no changes")
+			}
+			if issue := checker.checkFile(filePath); issue != nil {
+				t.Errorf("Expected no issue after fixing, got %q", issue.Problem)
+			}
+		})
+	}
+}
+
+func TestFixer_HeaderBlockOnly(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	cfg := &config.Config{
+		Copyright: config.Copyright{
+			Holder:      "IBM Corp.",
+			StartYear:   2014,
+			CurrentYear: 2025,
+			Format:      "Copyright {{.Holder}} {{.StartYear}}, {{.CurrentYear}}",
+		},
+		License: config.License{
+			Enabled:    true,
+			Identifier: "MPL-2.0",
+			Format:     "SPDX-License-Identifier: {{.Identifier}}",
+		},
+		Files: config.Files{
+			CommentStyles: map[string]string{"go": "//", "py": "#", "js": "/**"},
+		},
+		Detection: config.Detection{
+			MaxScanLines:    20,
+			HeaderBlockOnly: true,
+		},
+	}
+
+	tests := []struct {
+		name     string
+		fileName string
+		input    string
+		expected string
+	}{
+		{
+			name:     "copyright in docstring below code",
+			fileName: "main.py",
+			input:    "import os\n\n\ndef f():\n    \"\"\"\n    Copyright IBM Corp. 2014, 2025\n    SPDX-License-Identifier: MPL-2.0\n    \"\"\"\n",
+			expected: "# Copyright IBM Corp. 2014, 2025\n# SPDX-License-Identifier: MPL-2.0\n\nimport os\n\n\ndef f():\n    \"\"\"\n    Copyright IBM Corp. 2014, 2025\n    SPDX-License-Identifier: MPL-2.0\n    \"\"\"\n",
+		},
+		{
+			name:     "copyright comment below code",
+			fileName: "main.go",
+			input:    "package main\n\n// Copyright IBM Corp. 2014, 2025\n// SPDX-License-Identifier: MPL-2.0\nconst notice = 1\n",
+			expected: "// Copyright IBM Corp. 2014, 2025\n// SPDX-License-Identifier: MPL-2.0\n\npackage main\n\n// Copyright IBM Corp. 2014, 2025\n// SPDX-License-Identifier: MPL-2.0\nconst notice = 1\n",
+		},
+		{
+			name:     "header in leading comment block",
+			fileName: "main.go",
+			input:    "// Some leading remark.\n\n// Copyright IBM Corp. 2014, 2025\n// SPDX-License-Identifier: MPL-2.0\n\npackage main\n",
+			expected: "// Some leading remark.\n\n// Copyright IBM Corp. 2014, 2025\n// SPDX-License-Identifier: MPL-2.0\n\npackage main\n",
+		},
+		{
+			name:     "block comment header",
+			fileName: "main.js",
+			input:    "/**\n * Copyright IBM Corp. 2014, 2020\n * SPDX-License-Identifier: MPL-2.0\n */\n\nfunction hello() {}\n",
+			expected: "/**\n * Copyright IBM Corp. 2014, 2025\n * SPDX-License-Identifier: MPL-2.0\n */\n\nfunction hello() {}\n",
+		},
+	}
+
+	fixer := NewFixer(cfg)
+	checker := NewChecker(cfg)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filePath := filepath.Join(tmpDir, tt.fileName)
+			if err := os.WriteFile(filePath, []byte(tt.input), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			if issue := checker.checkFile(filePath); (issue != nil) != (tt.input != tt.expected) {
+				t.Errorf("checkFile() = %v, want an issue only if the file needs fixing", issue)
+			}
+
+			mustFixFile(t, fixer, filePath)
+
+			content, err := os.ReadFile(filePath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(content) != tt.expected {
+				t.Errorf("Expected:\n%q\n\nGot:\n%q", tt.expected, string(content))
+			}
+
+			if mustFixFile(t, fixer, filePath) {
+				t.Error("Expected second run to make no changes")
+			}
+		})
+	}
+}
+
+func TestFixer_Minified(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	minifiedJS := "!function(){" + strings.Repeat("var a=1;", 200) + "}();\n"
+	minifiedCSS := strings.Repeat(".a{color:red}", 100) + "\n"
+	header := "/**\n * Copyright IBM Corp. 2014, 2025\n * SPDX-License-Identifier: MPL-2.0\n */\n\n"
+
+	tests := []struct {
+		name     string
+		minified string
+		fileName string
+		input    string
+		expected string
+	}{
+		{
+			name:     "unset processes minified files normally",
+			fileName: "app.min.js",
+			input:    minifiedJS,
+			expected: "// Copyright IBM Corp. 2014, 2025\n// SPDX-License-Identifier: MPL-2.0\n\n" + minifiedJS,
+		},
+		{
+			name:     "skip",
+			minified: "skip",
+			fileName: "app.min.js",
+			input:    minifiedJS,
+			expected: minifiedJS,
+		},
+		{
+			name:     "block js",
+			minified: "block",
+			fileName: "app.min.js",
+			input:    minifiedJS,
+			expected: header + minifiedJS,
+		},
+		{
+			name:     "block css",
+			minified: "block",
+			fileName: "app.min.css",
+			input:    minifiedCSS,
+			expected: header + minifiedCSS,
+		},
+		{
+			name:     "block leaves regular files alone",
+			minified: "block",
+			fileName: "app.js",
+			input:    "function hello() {}\n",
+			expected: "// Copyright IBM Corp. 2014, 2025\n// SPDX-License-Identifier: MPL-2.0\n\nfunction hello() {}\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{
+				Copyright: config.Copyright{
+					Holder:      "IBM Corp.",
+					StartYear:   2014,
+					CurrentYear: 2025,
+					Format:      "Copyright {{.Holder}} {{.StartYear}}, {{.CurrentYear}}",
+				},
+				License: config.License{
+					Enabled:    true,
+					Identifier: "MPL-2.0",
+					Format:     "SPDX-License-Identifier: {{.Identifier}}",
+				},
+				Files: config.Files{
+					CommentStyles: map[string]string{"js": "//"},
+				},
+				Detection: config.Detection{
+					MaxScanLines: 20,
+					Minified:     tt.minified,
+				},
+			}
+
+			filePath := filepath.Join(tmpDir, tt.fileName)
+			if err := os.WriteFile(filePath, []byte(tt.input), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			fixer := NewFixer(cfg)
+			checker := NewChecker(cfg)
+			if issue := checker.checkFile(filePath); (issue != nil) != (tt.input != tt.expected) {
+				t.Errorf("checkFile() = %v, want an issue only if the file needs fixing", issue)
+			}
+
+			mustFixFile(t, fixer, filePath)
+
+			content, err := os.ReadFile(filePath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(content) != tt.expected {
+				t.Errorf("Expected:\n%.200q\n\nGot:\n%.200q", tt.expected, string(content))
+			}
+
+			if mustFixFile(t, fixer, filePath) {
+				t.Error("Expected second run to make no changes")
+			}
+			if issue := checker.checkFile(filePath); issue != nil {
+				t.Errorf("Expected no issue after fixing, got %q", issue.Problem)
+			}
+		})
+	}
+}
+
+func TestFixer_HolderAliases(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	cfg := &config.Config{
+		Copyright: config.Copyright{
+			Holder:        "IBM Corp.",
+			HolderAliases: []string{"IBM Corporation"},
+			StartYear:     2014,
+			CurrentYear:   2025,
+			Format:        "Copyright {{.Holder}} {{.StartYear}}, {{.CurrentYear}}",
+		},
+		License: config.License{
+			Enabled:    true,
+			Identifier: "MPL-2.0",
+			Format:     "SPDX-License-Identifier: {{.Identifier}}",
+		},
+		Files: config.Files{
+			CommentStyles: map[string]string{"go": "//"},
+		},
+		Detection: config.Detection{
+			MaxScanLines: 20,
+		},
+	}
+
+	expected := "// Copyright IBM Corp. 2014, 2025\n// SPDX-License-Identifier: MPL-2.0\n\npackage main\n"
+
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{name: "alias with current years", input: "// Copyright IBM Corporation 2014, 2025\n// SPDX-License-Identifier: MPL-2.0\n\npackage main\n"},
+		{name: "alias with outdated years", input: "// Copyright IBM Corporation 2014, 2020\n// SPDX-License-Identifier: MPL-2.0\n\npackage main\n"},
+		{name: "alias without license", input: "// Copyright IBM Corporation 2020\n\npackage main\n"},
+	}
+
+	fixer := NewFixer(cfg)
+	checker := NewChecker(cfg)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filePath := filepath.Join(tmpDir, "main.go")
+			if err := os.WriteFile(filePath, []byte(tt.input), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			mustFixFile(t, fixer, filePath)
+
+			content, err := os.ReadFile(filePath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(content) != expected {
+				t.Errorf("Expected:\n%q\n\nGot:\n%q", expected, string(content))
+			}
+
+			if mustFixFile(t, fixer, filePath) {
+				t.Error("Expected second run to make no changes")
+			}
+			if issue := checker.checkFile(filePath); issue != nil {
+				t.Errorf("Expected no issue after fixing, got %q", issue.Problem)
+			}
+		})
+	}
+}
+
+func TestFixer_NowFormat(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	cfg := &config.Config{
+		Copyright: config.Copyright{
+			Holder: "IBM Corp.",
+			Format: `Copyright {{.Holder}} {{.Now.Format "2006-01-02"}}`,
+			Now:    time.Date(2025, time.June, 1, 12, 0, 0, 0, time.UTC),
+		},
+		License: config.License{
+			Enabled:    true,
+			Identifier: "MPL-2.0",
+			Format:     "SPDX-License-Identifier: {{.Identifier}}",
+		},
+		Files: config.Files{
+			CommentStyles: map[string]string{"go": "//"},
+		},
+		Detection: config.Detection{
+			MaxScanLines: 20,
+		},
+	}
+
+	expected := "// Copyright IBM Corp. 2025-06-01\n// SPDX-License-Identifier: MPL-2.0\n\npackage main\n"
+
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{name: "missing header", input: "package main\n"},
+		{name: "header from an earlier date", input: "// Copyright IBM Corp. 2024-12-31\n// SPDX-License-Identifier: MPL-2.0\n\npackage main\n"},
+		{name: "current header", input: expected},
+	}
+
+	fixer := NewFixer(cfg)
+	checker := NewChecker(cfg)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filePath := filepath.Join(tmpDir, "main.go")
+			if err := os.WriteFile(filePath, []byte(tt.input), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			mustFixFile(t, fixer, filePath)
+
+			content, err := os.ReadFile(filePath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(content) != expected {
+				t.Errorf("Expected:\n%q\n\nGot:\n%q", expected, string(content))
+			}
+
+			if mustFixFile(t, fixer, filePath) {
+				t.Error("Expected second run to make no changes")
+			}
+			if issue := checker.checkFile(filePath); issue != nil {
+				t.Errorf("Expected no issue after fixing, got %q", issue.Problem)
+			}
+		})
+	}
+}
+
+func TestFixer_MisorderedHeader(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	cfg := &config.Config{
+		Copyright: config.Copyright{
+			Holder:      "IBM Corp.",
+			StartYear:   2014,
+			CurrentYear: 2025,
+			Format:      "Copyright {{.Holder}} {{.StartYear}}, {{.CurrentYear}}",
+		},
+		License: config.License{
+			Enabled:    true,
+			Identifier: "MPL-2.0",
+			Format:     "SPDX-License-Identifier: {{.Identifier}}",
+		},
+		Files: config.Files{
+			CommentStyles: map[string]string{"go": "//"},
+		},
+		Detection: config.Detection{
+			MaxScanLines: 20,
+		},
+	}
+
+	expected := "// Copyright IBM Corp. 2014, 2025\n// SPDX-License-Identifier: MPL-2.0\n\npackage main\n"
+
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{name: "swapped current lines", input: "// SPDX-License-Identifier: MPL-2.0\n// Copyright IBM Corp. 2014, 2025\n\npackage main\n"},
+		{name: "swapped outdated lines", input: "// SPDX-License-Identifier: Apache-2.0\n// Copyright IBM Corp. 2014, 2020\n\npackage main\n"},
+		{name: "separated by a blank line", input: "// SPDX-License-Identifier: MPL-2.0\n\n// Copyright IBM Corp. 2014, 2025\n\npackage main\n"},
+	}
+
+	fixer := NewFixer(cfg)
+	checker := NewChecker(cfg)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filePath := filepath.Join(tmpDir, "main.go")
+			if err := os.WriteFile(filePath, []byte(tt.input), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			if issue := checker.checkFile(filePath); issue == nil {
+				t.Error("Expected an issue before fixing")
+			}
+
+			if !mustFixFile(t, fixer, filePath) {
+				t.Error("Expected fix to report a change")
+			}
+
+			content, err := os.ReadFile(filePath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(content) != expected {
+				t.Errorf("Expected:\n%q\n\nGot:\n%q", expected, string(content))
+			}
+
+			if mustFixFile(t, fixer, filePath) {
+				t.Error("Expected second run to make no changes")
+			}
+			if issue := checker.checkFile(filePath); issue != nil {
+				t.Errorf("Expected no issue after fixing, got %q", issue.Problem)
+			}
+		})
+	}
+
+	filePath := filepath.Join(tmpDir, "swapped.go")
+	if err := os.WriteFile(filePath, []byte(tests[0].input), 0644); err != nil {
+		t.Fatal(err)
+	}
+	issue := checker.checkFile(filePath)
+	if issue == nil || issue.Problem != "license line comes before copyright line" || issue.Kind != KindIncorrect {
+		t.Errorf("Expected a misordered header issue, got %+v", issue)
+	}
+}
+
+func TestFixer_SPDXVariants(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	cfg := &config.Config{
+		Copyright: config.Copyright{
+			Holder:      "IBM Corp.",
+			StartYear:   2014,
+			CurrentYear: 2025,
+			Format:      "Copyright {{.Holder}} {{.StartYear}}, {{.CurrentYear}}",
+		},
+		License: config.License{
+			Enabled:    true,
+			Identifier: "MIT",
+			Format:     "SPDX-License-Identifier: {{.Identifier}}",
+		},
+		Files: config.Files{
+			CommentStyles: map[string]string{"go": "//"},
+		},
+		Detection: config.Detection{
+			MaxScanLines: 20,
+		},
+	}
+
+	expected := "// Copyright IBM Corp. 2014, 2025\n// SPDX-License-Identifier: MIT\n\npackage main\n"
+
+	tests := []struct {
+		name    string
+		license string
+	}{
+		{name: "lowercase tag", license: "// spdx-license-identifier: MIT"},
+		{name: "uppercase tag", license: "// SPDX-LICENSE-IDENTIFIER: MIT"},
+		{name: "lowercase tag without spaces", license: "// spdx-license-identifier:MIT"},
+		{name: "quoted identifier", license: `// SPDX-License-Identifier: "MIT"`},
+		{name: "lowercase identifier", license: "// SPDX-License-Identifier: mit"},
+	}
+
+	fixer := NewFixer(cfg)
+	checker := NewChecker(cfg)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filePath := filepath.Join(tmpDir, "main.go")
+			input := "// Copyright IBM Corp. 2014, 2025\n" + tt.license + "\n\npackage main\n"
+			if err := os.WriteFile(filePath, []byte(input), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			issue := checker.checkFile(filePath)
+			if issue == nil || issue.Problem != "license line is not in canonical form" {
+				t.Errorf("Expected a non-canonical license issue, got %+v", issue)
+			}
+
+			mustFixFile(t, fixer, filePath)
+
+			content, err := os.ReadFile(filePath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(content) != expected {
+				t.Errorf("Expected:\n%q\n\nGot:\n%q", expected, string(content))
+			}
+
+			if mustFixFile(t, fixer, filePath) {
+				t.Error("Expected second run to make no changes")
+			}
+			if issue := checker.checkFile(filePath); issue != nil {
+				t.Errorf("Expected no issue after fixing, got %q", issue.Problem)
+			}
+		})
+	}
+}
+
+func TestFixer_SPDXColonSpacing(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	cfg := &config.Config{
+		Copyright: config.Copyright{
+			Holder:      "IBM Corp.",
+			StartYear:   2014,
+			CurrentYear: 2025,
+			Format:      "Copyright {{.Holder}} {{.StartYear}}, {{.CurrentYear}}",
+		},
+		License: config.License{
+			Enabled:    true,
+			Identifier: "MPL-2.0",
+			Format:     "SPDX-License-Identifier: {{.Identifier}}",
+		},
+		Files: config.Files{
+			CommentStyles: map[string]string{"go": "//"},
+		},
+		Detection: config.Detection{
+			MaxScanLines: 20,
+		},
+	}
+
+	// The same license line as ours but for the spacing around the colon is left as written
+	fixer := NewFixer(cfg)
+	checker := NewChecker(cfg)
+	for _, license := range []string{
+		"// SPDX-License-Identifier:MPL-2.0",
+		"// SPDX-License-Identifier : MPL-2.0",
+		"// SPDX-License-Identifier :MPL-2.0",
+	} {
+		t.Run(license, func(t *testing.T) {
+			filePath := filepath.Join(tmpDir, "main.go")
+			input := "// Copyright IBM Corp. 2014, 2025\n" + license + "\n\npackage main\n"
+			if err := os.WriteFile(filePath, []byte(input), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			if issue := checker.checkFile(filePath); issue != nil {
+				t.Errorf("Expected no issue, got %q", issue.Problem)
+			}
+			if mustFixFile(t, fixer, filePath) {
+				t.Error("Expected no changes")
+			}
+
+			// An outdated copyright line is updated without touching the license line
+			outdated := strings.Replace(input, "2025", "2024", 1)
+			if err := os.WriteFile(filePath, []byte(outdated), 0644); err != nil {
+				t.Fatal(err)
+			}
+			mustFixFile(t, fixer, filePath)
+			content, err := os.ReadFile(filePath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(content) != input {
+				t.Errorf("Expected:\n%q\n\nGot:\n%q", input, string(content))
+			}
+		})
+	}
+}
+
+func TestFixer_CollapseBlankLines(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	cfg := &config.Config{
+		Copyright: config.Copyright{
+			Holder:      "IBM Corp.",
+			StartYear:   2014,
+			CurrentYear: 2025,
+			Format:      "Copyright {{.Holder}} {{.StartYear}}, {{.CurrentYear}}",
+		},
+		License: config.License{
+			Enabled:    true,
+			Identifier: "MPL-2.0",
+			Format:     "SPDX-License-Identifier: {{.Identifier}}",
+		},
+		Files: config.Files{
+			CommentStyles: map[string]string{"go": "//"},
+		},
+		Detection: config.Detection{
+			MaxScanLines:       20,
+			ReplacePatterns:    []string{"Copyright Old Corp"},
+			CollapseBlankLines: true,
+		},
+	}
+
+	expected := "// Copyright IBM Corp. 2014, 2025\n// SPDX-License-Identifier: MPL-2.0\n\npackage main\n"
+
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{name: "removed header", input: "// Copyright Old Corp\n\n\n\npackage main\n"},
+		{name: "leading blank lines", input: "\n\n// Copyright Old Corp\n\n\n\npackage main\n"},
+		{name: "outdated years", input: "// Copyright IBM Corp. 2014, 2020\n// SPDX-License-Identifier: MPL-2.0\n\n\n\npackage main\n"},
+		{name: "current header", input: "// Copyright IBM Corp. 2014, 2025\n// SPDX-License-Identifier: MPL-2.0\n\n\n\npackage main\n"},
+	}
+
+	fixer := NewFixer(cfg)
+	checker := NewChecker(cfg)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filePath := filepath.Join(tmpDir, "main.go")
+			if err := os.WriteFile(filePath, []byte(tt.input), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			if issue := checker.checkFile(filePath); issue == nil {
+				t.Error("Expected an issue before fixing")
+			}
+
+			if !mustFixFile(t, fixer, filePath) {
+				t.Error("Expected fix to report a change")
+			}
+
+			content, err := os.ReadFile(filePath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(content) != expected {
+				t.Errorf("Expected:\n%q\n\nGot:\n%q", expected, string(content))
+			}
+
+			if mustFixFile(t, fixer, filePath) {
+				t.Error("Expected second run to make no changes")
+			}
+			if issue := checker.checkFile(filePath); issue != nil {
+				t.Errorf("Expected no issue after fixing, got %q", issue.Problem)
+			}
+		})
+	}
+
+	// Without the option, blank lines after the header are left alone
+	cfg.Detection.CollapseBlankLines = false
+	filePath := filepath.Join(tmpDir, "spaced.go")
+	if err := os.WriteFile(filePath, []byte(tests[3].input), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if mustFixFile(t, NewFixer(cfg), filePath) {
+		t.Error("Expected no change when collapse_blank_lines is off")
+	}
+}
+
+func TestFixer_BlankLinesAfterHeader(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	header := "# Copyright IBM Corp. 2014, 2025\n# SPDX-License-Identifier: MPL-2.0\n"
+	inputs := map[string]string{
+		"missing header":  "import os\n",
+		"outdated header": "# Copyright IBM Corp. 2014, 2020\n# SPDX-License-Identifier: MPL-2.0\n\n\n\nimport os\n",
+		"no blank line":   header + "import os\n",
+		"one blank line":  header + "\nimport os\n",
+		"two blank lines": header + "\n\nimport os\n",
+	}
+
+	for _, blankLines := range []int{0, 1, 2} {
+		cfg := &config.Config{
+			Copyright: config.Copyright{
+				Holder:      "IBM Corp.",
+				StartYear:   2014,
+				CurrentYear: 2025,
+				Format:      "Copyright {{.Holder}} {{.StartYear}}, {{.CurrentYear}}",
+			},
+			License: config.License{
+				Enabled:    true,
+				Identifier: "MPL-2.0",
+				Format:     "SPDX-License-Identifier: {{.Identifier}}",
+			},
+			Files: config.Files{
+				CommentStyles:         map[string]string{"py": "#"},
+				BlankLinesAfterHeader: &blankLines,
+			},
+			Detection: config.Detection{MaxScanLines: 20},
+		}
+		expected := header + strings.Repeat("\n", blankLines) + "import os\n"
+
+		fixer := NewFixer(cfg)
+		checker := NewChecker(cfg)
+		for name, input := range inputs {
+			t.Run(fmt.Sprintf("%d/%s", blankLines, name), func(t *testing.T) {
+				filePath := filepath.Join(tmpDir, "main.py")
+				if err := os.WriteFile(filePath, []byte(input), 0644); err != nil {
+					t.Fatal(err)
+				}
+
+				if issue := checker.checkFile(filePath); (issue == nil) != (input == expected) {
+					t.Errorf("checkFile() = %v before fixing, want an issue only when the file differs", issue)
+				}
+
+				mustFixFile(t, fixer, filePath)
+				content, err := os.ReadFile(filePath)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if string(content) != expected {
+					t.Errorf("Expected:\n%q\n\nGot:\n%q", expected, string(content))
+				}
+
+				if mustFixFile(t, fixer, filePath) {
+					t.Error("Expected second run to make no changes")
+				}
+				if issue := checker.checkFile(filePath); issue != nil {
+					t.Errorf("Expected no issue after fixing, got %q", issue.Problem)
+				}
+
+				processed, err := fixer.ProcessContent([]byte(input), ".py")
+				if err != nil {
+					t.Fatal(err)
+				}
+				if string(processed) != expected {
+					t.Errorf("ProcessContent() = %q, want %q", processed, expected)
+				}
+			})
+		}
+	}
+}
+
+func TestFixer_BlankLinesAfterHeaderByExt(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	none := 0
+	cfg := &config.Config{
+		Copyright: config.Copyright{
+			Holder:      "IBM Corp.",
+			StartYear:   2014,
+			CurrentYear: 2025,
+			Format:      "Copyright {{.Holder}} {{.StartYear}}, {{.CurrentYear}}",
+		},
+		Files: config.Files{
+			CommentStyles:              map[string]string{"go": "//", "sh": "#"},
+			BlankLinesAfterHeader:      &none,
+			BlankLinesAfterHeaderByExt: map[string]int{"sh": 2},
+		},
+		Detection: config.Detection{MaxScanLines: 20},
+	}
+
+	tests := []struct {
+		file     string
+		input    string
+		expected string
+	}{
+		{file: "run.sh", input: "echo hi\n", expected: "# Copyright IBM Corp. 2014, 2025\n\n\necho hi\n"},
+		// Go keeps a blank line, so the header is not taken for the package doc comment
+		{file: "main.go", input: "package main\n", expected: "// Copyright IBM Corp. 2014, 2025\n\npackage main\n"},
+	}
+
+	fixer := NewFixer(cfg)
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			filePath := filepath.Join(tmpDir, tt.file)
+			if err := os.WriteFile(filePath, []byte(tt.input), 0644); err != nil {
+				t.Fatal(err)
+			}
+			mustFixFile(t, fixer, filePath)
+			content, err := os.ReadFile(filePath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(content) != tt.expected {
+				t.Errorf("Expected:\n%q\n\nGot:\n%q", tt.expected, string(content))
+			}
+			if mustFixFile(t, fixer, filePath) {
+				t.Error("Expected second run to make no changes")
+			}
+		})
+	}
+}
+
+// Whatever fix writes, check must accept. Both decide on their own what a correct header
+// looks like, so this guards against them drifting apart.
+func TestFixer_CheckerAgrees(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	cfg := &config.Config{
+		Copyright: config.Copyright{
+			Holder:      "IBM Corp.",
+			StartYear:   2014,
+			CurrentYear: 2025,
+			Format:      "Copyright {{.Holder}} {{.StartYear}}, {{.CurrentYear}}",
+		},
+		License: config.License{
+			Enabled:    true,
+			Identifier: "MPL-2.0",
+			Format:     "SPDX-License-Identifier: {{.Identifier}}",
+		},
+		Files: config.Files{
+			CommentStyles: map[string]string{
+				"go":  "//",
+				"py":  "#",
+				"md":  "<!--",
+				"js":  "/**",
+				"sql": "--",
+				"rst": "..",
+				"tex": "%",
+				"el":  ";;",
+				"bas": "'",
+			},
+		},
+		Detection: config.Detection{
+			MaxScanLines:    20,
+			ReplacePatterns: []string{"Copyright Old Corp"},
+		},
+	}
+
+	bodies := map[string]string{
+		".go":  "package main\n",
+		".py":  "import os\n",
+		".md":  "# Title\n",
+		".js":  "const a = 1;\n",
+		".sql": "SELECT 1;\n",
+		".rst": "Title\n=====\n",
+		".tex": "\\documentclass{article}\n",
+		".el":  "(provide 'foo)\n",
+		".bas": "Print \"hi\"\n",
+	}
+
+	fixer := NewFixer(cfg)
+	checker := NewChecker(cfg)
+	for ext, body := range bodies {
+		prefix := cfg.CommentPrefix(ext)
+		inputs := map[string]string{
+			"missing":  body,
+			"outdated": prefix + " Copyright IBM Corp. 2014, 2020\n\n" + body,
+			"replaced": prefix + " Copyright Old Corp\n\n" + body,
+		}
+		for name, input := range inputs {
+			t.Run(ext+" "+name, func(t *testing.T) {
+				filePath := filepath.Join(tmpDir, "file"+ext)
+				if err := os.WriteFile(filePath, []byte(input), 0644); err != nil {
+					t.Fatal(err)
+				}
+
+				mustFixFile(t, fixer, filePath)
+				if issue := checker.checkFile(filePath); issue != nil {
+					content, _ := os.ReadFile(filePath)
+					t.Errorf("Expected no issue after fixing, got %q for:\n%s", issue.Problem, content)
+				}
+				if mustFixFile(t, fixer, filePath) {
+					t.Error("Expected second run to make no changes")
+				}
+			})
+		}
+	}
+}
+
+func TestFixer_Verify(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	cfg := &config.Config{
+		Copyright: config.Copyright{
+			Holder:      "IBM Corp.",
+			StartYear:   2014,
+			CurrentYear: 2025,
+			Format:      "Copyright {{.Holder}} {{.StartYear}}, {{.CurrentYear}}",
+		},
+		Files: config.Files{
+			Extensions:    []string{".go"},
+			CommentStyles: map[string]string{"go": "//"},
+		},
+		Detection: config.Detection{
+			MaxScanLines: 20,
+		},
+	}
+
+	filePath := filepath.Join(tmpDir, "main.go")
+	if err := os.WriteFile(filePath, []byte("package main\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	fixer := NewFixer(cfg)
+	fixer.Verify = true
+	result, err := fixer.Fix(context.Background(), tmpDir)
+	if err != nil {
+		t.Fatalf("Fix() error = %v", err)
+	}
+	if result.Added != 1 || len(result.Unverified) != 0 {
+		t.Errorf("Expected one verified fix, got %+v", result)
+	}
+
+	// A timestamp renders differently for fix and check, so the checker still reports the file
+	broken := *cfg
+	broken.Copyright.Format = `Copyright {{.Holder}} {{.Now.Format "15:04:05.000000000"}}`
+	fixer = NewFixer(&broken)
+	fixer.Verify = true
+	result, err = fixer.Fix(context.Background(), tmpDir)
+	if err != nil {
+		t.Fatalf("Fix() error = %v", err)
+	}
+	if len(result.Unverified) != 1 || result.Unverified[0].File != filePath {
+		t.Errorf("Expected %s to be unverified, got %+v", filePath, result.Unverified)
+	}
+
+	// Without Verify nothing is checked
+	result, err = NewFixer(&broken).Fix(context.Background(), tmpDir)
+	if err != nil {
+		t.Fatalf("Fix() error = %v", err)
+	}
+	if len(result.Unverified) != 0 {
+		t.Errorf("Expected no verification without Verify, got %+v", result.Unverified)
+	}
+}
+
+// Headers fix writes in block comments, opened on a line of their own, or below a banner are at
+// the top for require_at_top
+func TestFixer_RequireAtTopBlockStyles(t *testing.T) {
+	minifiedJS := "!function(){" + strings.Repeat("var a=1;", 200) + "}();\n"
+
+	tests := []struct {
+		name   string
+		file   string
+		input  string
+		config func(*config.Config)
+	}{
+		{
+			name:   "paired comment style",
+			file:   "style.css",
+			input:  "body { color: red; }\n",
+			config: func(c *config.Config) { c.Files.CommentStyles = map[string]string{"css": "/* */"} },
+		},
+		{
+			name:  "block comment style",
+			file:  "main.c",
+			input: "int main(void) { return 0; }\n",
+			config: func(c *config.Config) {
+				c.Files.BlockCommentStyles = map[string]config.BlockCommentStyle{"c": {Open: "/*", Continuation: " * ", Close: " */"}}
+			},
+		},
+		{
+			name:   "javadoc comment style",
+			file:   "app.js",
+			input:  "function hello() {}\n",
+			config: func(c *config.Config) { c.Files.CommentStyles = map[string]string{"js": "/**"} },
+		},
+		{
+			name:   "minified block",
+			file:   "app.min.js",
+			input:  minifiedJS,
+			config: func(c *config.Config) { c.Detection.Minified = "block" },
+		},
+		{
+			name:   "banner before the copyright",
+			file:   "main.go",
+			input:  "package main\n",
+			config: func(c *config.Config) { c.Copyright.BannerBefore = "----" },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{
+				Copyright: config.Copyright{
+					Holder:      "IBM Corp.",
+					StartYear:   2014,
+					CurrentYear: 2025,
+					Format:      "Copyright {{.Holder}} {{.StartYear}}, {{.CurrentYear}}",
+				},
+				License: config.License{
+					Enabled:    true,
+					Identifier: "MPL-2.0",
+					Format:     "SPDX-License-Identifier: {{.Identifier}}",
+				},
+				Detection: config.Detection{
+					MaxScanLines: 20,
+					RequireAtTop: true,
+				},
+			}
+			tt.config(cfg)
+
+			filePath := filepath.Join(t.TempDir(), tt.file)
+			if err := os.WriteFile(filePath, []byte(tt.input), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			fixer := NewFixer(cfg)
+			if !mustFixFile(t, fixer, filePath) {
+				t.Fatal("Expected the header to be added")
+			}
+			if issue := NewChecker(cfg).checkFile(filePath); issue != nil {
+				t.Errorf("check after fix reported %q", issue.Problem)
+			}
+			if mustFixFile(t, fixer, filePath) {
+				t.Error("Expected second run to make no changes")
+			}
+		})
+	}
+}
+
+func TestFixer_BlockCommentStyles(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	cfg := &config.Config{
+		Copyright: config.Copyright{
+			Holder:      "IBM Corp.",
+			StartYear:   2014,
+			CurrentYear: 2025,
+			Format:      "Copyright {{.Holder}} {{.StartYear}}, {{.CurrentYear}}",
+		},
+		License: config.License{
+			Enabled:    true,
+			Identifier: "MPL-2.0",
+			Format:     "SPDX-License-Identifier: {{.Identifier}}",
+		},
+		Files: config.Files{
+			CommentStyles: map[string]string{"css": "/* */"},
+			BlockCommentStyles: map[string]config.BlockCommentStyle{
+				"c":   {Open: "/*", Continuation: " * ", Close: " */"},
+				"pas": {Open: "(*", Continuation: "  ", Close: "*)"},
+			},
+		},
+		Detection: config.Detection{
+			MaxScanLines:    20,
+			ReplacePatterns: []string{"Copyright Old Corp"},
+		},
+	}
+
+	cHeader := "/*\n * Copyright IBM Corp. 2014, 2025\n * SPDX-License-Identifier: MPL-2.0\n */\n"
+	pasHeader := "(*\n  Copyright IBM Corp. 2014, 2025\n  SPDX-License-Identifier: MPL-2.0\n*)\n"
+
+	tests := []struct {
+		name     string
+		file     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "C header added",
+			file:     "main.c",
+			input:    "int main(void) { return 0; }\n",
+			expected: cHeader + "\nint main(void) { return 0; }\n",
+		},
+		{
+			name:     "C header years updated",
+			file:     "main.c",
+			input:    "/*\n * Copyright IBM Corp. 2014, 2020\n * SPDX-License-Identifier: Apache-2.0\n */\n\nint main(void) { return 0; }\n",
+			expected: cHeader + "\nint main(void) { return 0; }\n",
+		},
+		{
+			name:     "C header replaced",
+			file:     "main.c",
+			input:    "/*\n * Copyright Old Corp\n */\n\nint main(void) { return 0; }\n",
+			expected: cHeader + "\nint main(void) { return 0; }\n",
+		},
+		{
+			name:     "C header in line comments replaced",
+			file:     "main.c",
+			input:    "// Copyright IBM Corp. 2014, 2020\n\nint main(void) { return 0; }\n",
+			expected: cHeader + "\nint main(void) { return 0; }\n",
+		},
+		{
+			name:     "CSS header from paired comment style",
+			file:     "site.css",
+			input:    "/*\n * Copyright IBM Corp. 2014, 2020\n */\n\nbody { margin: 0; }\n",
+			expected: cHeader + "\nbody { margin: 0; }\n",
+		},
+		{
+			name:     "CSS comment with other text kept",
+			file:     "site.css",
+			input:    "/*\n * Copyright IBM Corp. 2014, 2020\n * Site styles\n */\nbody { margin: 0; }\n",
+			expected: cHeader + "\n/*\n * Site styles\n */\nbody { margin: 0; }\n",
+		},
+		{
+			name:     "Pascal header added",
+			file:     "main.pas",
+			input:    "program Hello;\n",
+			expected: pasHeader + "\nprogram Hello;\n",
+		},
+		{
+			name:     "Pascal header years updated",
+			file:     "main.pas",
+			input:    "(*\n  Copyright IBM Corp. 2014, 2020\n  SPDX-License-Identifier: MPL-2.0\n*)\n\nprogram Hello;\n",
+			expected: pasHeader + "\nprogram Hello;\n",
+		},
+	}
+
+	fixer := NewFixer(cfg)
+	checker := NewChecker(cfg)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filePath := filepath.Join(tmpDir, tt.file)
+			if err := os.WriteFile(filePath, []byte(tt.input), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			if issue := checker.checkFile(filePath); issue == nil {
+				t.Error("Expected an issue before fixing")
+			}
+
+			if !mustFixFile(t, fixer, filePath) {
+				t.Error("Expected fix to report a change")
+			}
+
+			content, err := os.ReadFile(filePath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(content) != tt.expected {
+				t.Errorf("Expected:\n%s\n\nGot:\n%s", tt.expected, string(content))
+			}
+
+			if mustFixFile(t, fixer, filePath) {
+				t.Error("Expected second run to make no changes")
+			}
+			if issue := checker.checkFile(filePath); issue != nil {
+				t.Errorf("Expected no issue after fixing, got %q", issue.Problem)
+			}
+		})
+	}
+}
+
+func TestFixer_UnchangedThirdPartyLayout(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	cfg := &config.Config{
+		Copyright: config.Copyright{
+			Holder:      "IBM Corp.",
+			StartYear:   2014,
+			CurrentYear: 2025,
+			Format:      "Copyright {{.Holder}} {{.StartYear}}, {{.CurrentYear}}",
+		},
+		License: config.License{
+			Enabled:    true,
+			Identifier: "MPL-2.0",
+			Format:     "SPDX-License-Identifier: {{.Identifier}}",
+		},
+		Files: config.Files{
+			Extensions:    []string{".go"},
+			CommentStyles: map[string]string{"go": "//"},
+		},
+		Detection: config.Detection{
+			MaxScanLines: 20,
+		},
+		ThirdParty: config.ThirdParty{
+			Action:   "leave",
+			Patterns: []string{"Copyright.*Oracle"},
+		},
+	}
+
+	// Our header, then the third-party notice kept as the package doc comment
+	input := "// Copyright IBM Corp. 2014, 2025\n// SPDX-License-Identifier: MPL-2.0\n\n// Copyright (c) 2019 Oracle and/or its affiliates.\npackage main\n"
+	filePath := filepath.Join(tmpDir, "main.go")
+	if err := os.WriteFile(filePath, []byte(input), 0644); err != nil {
+		t.Fatal(err)
+	}
+	past := time.Now().Add(-time.Hour).Truncate(time.Second)
+	if err := os.Chtimes(filePath, past, past); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := NewFixer(cfg).Fix(context.Background(), tmpDir)
+	if err != nil {
+		t.Fatalf("Fix() error = %v", err)
+	}
+	if result.Fixed != 0 || len(result.Changed) != 0 {
+		t.Errorf("Expected no fixed files, got %+v", result)
+	}
+
+	info, err := os.Stat(filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !info.ModTime().Equal(past) {
+		t.Errorf("Expected the file not to be rewritten, mtime changed to %v", info.ModTime())
+	}
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != input {
+		t.Errorf("Expected content unchanged, got:\n%q", string(content))
+	}
+}
+
+func TestFixer_LineEndings(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	cfg := &config.Config{
+		Copyright: config.Copyright{
+			Holder:      "IBM Corp.",
+			StartYear:   2014,
+			CurrentYear: 2025,
+			Format:      "Copyright {{.Holder}} {{.StartYear}}, {{.CurrentYear}}",
+		},
+		License: config.License{
+			Enabled:    true,
+			Identifier: "MPL-2.0",
+			Format:     "SPDX-License-Identifier: {{.Identifier}}",
+		},
+		Files: config.Files{
+			CommentStyles: map[string]string{"go": "//"},
+		},
+		Detection: config.Detection{
+			MaxScanLines: 20,
+		},
+	}
+
+	// A body long enough that only the head of the file is read and the rest is streamed
+	var long strings.Builder
+	for i := range 200 {
+		fmt.Fprintf(&long, "var v%d = %d\r\n", i, i)
+	}
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "crlf missing header",
+			input:    "package main\r\n\r\nfunc main() {}\r\n",
+			expected: "// Copyright IBM Corp. 2014, 2025\r\n// SPDX-License-Identifier: MPL-2.0\r\n\r\npackage main\r\n\r\nfunc main() {}\r\n",
+		},
+		{
+			name:     "crlf outdated header",
+			input:    "// Copyright IBM Corp. 2014, 2020\r\n// SPDX-License-Identifier: MPL-2.0\r\n\r\npackage main\r\n",
+			expected: "// Copyright IBM Corp. 2014, 2025\r\n// SPDX-License-Identifier: MPL-2.0\r\n\r\npackage main\r\n",
+		},
+		{
+			name:     "crlf without trailing newline",
+			input:    "package main\r\n\r\nfunc main() {}",
+			expected: "// Copyright IBM Corp. 2014, 2025\r\n// SPDX-License-Identifier: MPL-2.0\r\n\r\npackage main\r\n\r\nfunc main() {}",
+		},
+		{
+			name:     "mixed endings, mostly crlf",
+			input:    "package main\r\n\r\nfunc main() {}\n",
+			expected: "// Copyright IBM Corp. 2014, 2025\r\n// SPDX-License-Identifier: MPL-2.0\r\n\r\npackage main\r\n\r\nfunc main() {}\r\n",
+		},
+		{
+			name:     "mixed endings, mostly lf",
+			input:    "package main\r\n\nfunc main() {}\n",
+			expected: "// Copyright IBM Corp. 2014, 2025\n// SPDX-License-Identifier: MPL-2.0\n\npackage main\r\n\nfunc main() {}\n",
+		},
+		{
+			name:     "crlf streamed",
+			input:    "package main\r\n\r\n" + long.String(),
+			expected: "// Copyright IBM Corp. 2014, 2025\r\n// SPDX-License-Identifier: MPL-2.0\r\n\r\npackage main\r\n\r\n" + long.String(),
+		},
+	}
+
+	fixer := NewFixer(cfg)
+	checker := NewChecker(cfg)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filePath := filepath.Join(tmpDir, "main.go")
+			if err := os.WriteFile(filePath, []byte(tt.input), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			if !mustFixFile(t, fixer, filePath) {
+				t.Fatal("Expected file to be fixed")
+			}
+			content, err := os.ReadFile(filePath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(content) != tt.expected {
+				t.Errorf("Expected:\n%q\n\nGot:\n%q", tt.expected, string(content))
+			}
+
+			if issue := checker.checkFile(filePath); issue != nil {
+				t.Errorf("Expected no issue after fixing, got %q", issue.Problem)
+			}
+			if issue := checker.verifyFile(filePath); issue != nil {
+				t.Errorf("Expected verify to pass after fixing, got %q", issue.Problem)
+			}
+			if mustFixFile(t, fixer, filePath) {
+				t.Error("Expected second run to make no changes")
+			}
+
+			processed, err := fixer.ProcessContent([]byte(tt.input), ".go")
+			if err != nil {
+				t.Fatalf("ProcessContent() error = %v", err)
+			}
+			if string(processed) != tt.expected {
+				t.Errorf("ProcessContent() =\n%q\n\nwant:\n%q", string(processed), tt.expected)
+			}
+		})
+	}
+}
+
+func TestFixer_NoTrailingNewline(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	cfg := &config.Config{
+		Copyright: config.Copyright{
+			Holder:      "IBM Corp.",
+			StartYear:   2014,
+			CurrentYear: 2025,
+			Format:      "Copyright {{.Holder}} {{.StartYear}}, {{.CurrentYear}}",
+		},
+		License: config.License{
+			Enabled:    true,
+			Identifier: "MPL-2.0",
+			Format:     "SPDX-License-Identifier: {{.Identifier}}",
+		},
+		Files: config.Files{
+			CommentStyles: map[string]string{"go": "//"},
+		},
+		Detection: config.Detection{
+			MaxScanLines:    20,
+			ReplacePatterns: []string{"Copyright Old Corp"},
+		},
+	}
+
+	header := "// Copyright IBM Corp. 2014, 2025\n// SPDX-License-Identifier: MPL-2.0\n"
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "missing header",
+			input:    "package main\n\nfunc main() {}",
+			expected: header + "\npackage main\n\nfunc main() {}",
+		},
+		{
+			name:     "outdated header",
+			input:    "// Copyright IBM Corp. 2014, 2020\n// SPDX-License-Identifier: MPL-2.0\n\npackage main",
+			expected: header + "\npackage main",
+		},
+		{
+			name:     "replaced header",
+			input:    "// Copyright Old Corp\n\npackage main",
+			expected: header + "\npackage main",
+		},
+		{
+			name:     "single line",
+			input:    "package main",
+			expected: header + "\npackage main",
+		},
+	}
+
+	fixer := NewFixer(cfg)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filePath := filepath.Join(tmpDir, "main.go")
+			if err := os.WriteFile(filePath, []byte(tt.input), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			mustFixFile(t, fixer, filePath)
+			content, err := os.ReadFile(filePath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(content) != tt.expected {
+				t.Errorf("Expected:\n%q\n\nGot:\n%q", tt.expected, string(content))
+			}
+
+			processed, err := fixer.ProcessContent([]byte(tt.input), ".go")
+			if err != nil {
+				t.Fatalf("ProcessContent() error = %v", err)
+			}
+			if string(processed) != string(content) {
+				t.Errorf("ProcessContent() = %q, fixFile() wrote %q", processed, content)
+			}
+		})
+	}
+}
+
+func TestFixer_KeepMarker(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	cfg := &config.Config{
+		Copyright: config.Copyright{
+			Holder:      "IBM Corp.",
+			StartYear:   2014,
+			CurrentYear: 2025,
+			Format:      "Copyright {{.Holder}} {{.StartYear}}, {{.CurrentYear}}",
+		},
+		License: config.License{
+			Enabled:    true,
+			Identifier: "MPL-2.0",
+			Format:     "SPDX-License-Identifier: {{.Identifier}}",
+		},
+		Files: config.Files{
+			CommentStyles: map[string]string{"go": "//"},
+		},
+		Detection: config.Detection{
+			MaxScanLines:    20,
+			ReplacePatterns: []string{"Copyright .*Vendor"},
+		},
+	}
+
+	header := "// Copyright IBM Corp. 2014, 2025\n// SPDX-License-Identifier: MPL-2.0\n"
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "marker on the line",
+			input:    "// Copyright 2019 SomeVendor // copyplop:keep\n\npackage main\n",
+			expected: header + "\n// Copyright 2019 SomeVendor // copyplop:keep\n\npackage main\n",
+		},
+		{
+			name:     "marker above the line",
+			input:    "// copyplop:keep\n// Copyright 2019 SomeVendor\n\npackage main\n",
+			expected: header + "\n// copyplop:keep\n// Copyright 2019 SomeVendor\n\npackage main\n",
+		},
+		{
+			name:     "kept line below the header",
+			input:    header + "// copyplop:keep\n// Copyright 2019 SomeVendor\n\npackage main\n",
+			expected: header + "// copyplop:keep\n// Copyright 2019 SomeVendor\n\npackage main\n",
+		},
+		{
+			name:     "outdated header above a kept line",
+			input:    "// Copyright IBM Corp. 2014, 2020\n// SPDX-License-Identifier: MPL-2.0\n// Copyright 2019 SomeVendor // copyplop:keep\n\npackage main\n",
+			expected: header + "// Copyright 2019 SomeVendor // copyplop:keep\n\npackage main\n",
+		},
+		{
+			name:     "unmarked line",
+			input:    "// Copyright 2019 SomeVendor\n\npackage main\n",
+			expected: header + "\npackage main\n",
+		},
+	}
+
+	fixer := NewFixer(cfg)
+	checker := NewChecker(cfg)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filePath := filepath.Join(tmpDir, "main.go")
+			if err := os.WriteFile(filePath, []byte(tt.input), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			mustFixFile(t, fixer, filePath)
+			content, err := os.ReadFile(filePath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(content) != tt.expected {
+				t.Errorf("Expected:\n%q\n\nGot:\n%q", tt.expected, string(content))
+			}
+
+			if mustFixFile(t, fixer, filePath) {
+				t.Error("Expected second run to make no changes")
+			}
+			if issue := checker.checkFile(filePath); issue != nil {
+				t.Errorf("Expected no issue after fixing, got %q", issue.Problem)
+			}
+
+			// ProcessContent always rebuilds the header, but keeps the same lines
+			processed, err := fixer.ProcessContent([]byte(tt.input), ".go")
+			if err != nil {
+				t.Fatalf("ProcessContent() error = %v", err)
+			}
+			if strings.Contains(string(processed), "SomeVendor") != strings.Contains(tt.expected, "SomeVendor") {
+				t.Errorf("ProcessContent() =\n%q\n\nwant the vendor line as in:\n%q", string(processed), tt.expected)
+			}
+		})
+	}
+}
+
+func TestFixer_ByteOrderMark(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	cfg := &config.Config{
+		Copyright: config.Copyright{
+			Holder:      "IBM Corp.",
+			StartYear:   2014,
+			CurrentYear: 2025,
+			Format:      "Copyright {{.Holder}} {{.StartYear}}, {{.CurrentYear}}",
+		},
+		License: config.License{
+			Enabled:    true,
+			Identifier: "MPL-2.0",
+			Format:     "SPDX-License-Identifier: {{.Identifier}}",
+		},
+		Files: config.Files{
+			CommentStyles: map[string]string{"cs": "//"},
+		},
+		Detection: config.Detection{
+			MaxScanLines: 20,
+		},
+	}
+
+	// A body long enough that only the head of the file is read and the rest is streamed
+	var long strings.Builder
+	for i := range 200 {
+		fmt.Fprintf(&long, "// line %d\n", i)
+	}
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "missing header",
+			input:    utf8BOM + "using System;\n",
+			expected: utf8BOM + "// Copyright IBM Corp. 2014, 2025\n// SPDX-License-Identifier: MPL-2.0\n\nusing System;\n",
+		},
+		{
+			name:     "outdated header",
+			input:    utf8BOM + "// Copyright IBM Corp. 2014, 2020\n// SPDX-License-Identifier: MPL-2.0\n\nusing System;\n",
+			expected: utf8BOM + "// Copyright IBM Corp. 2014, 2025\n// SPDX-License-Identifier: MPL-2.0\n\nusing System;\n",
+		},
+		{
+			name:     "crlf",
+			input:    utf8BOM + "using System;\r\n",
+			expected: utf8BOM + "// Copyright IBM Corp. 2014, 2025\r\n// SPDX-License-Identifier: MPL-2.0\r\n\r\nusing System;\r\n",
+		},
+		{
+			name:     "streamed",
+			input:    utf8BOM + "using System;\n\n" + long.String(),
+			expected: utf8BOM + "// Copyright IBM Corp. 2014, 2025\n// SPDX-License-Identifier: MPL-2.0\n\nusing System;\n\n" + long.String(),
+		},
+	}
+
+	fixer := NewFixer(cfg)
+	checker := NewChecker(cfg)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filePath := filepath.Join(tmpDir, "Program.cs")
+			if err := os.WriteFile(filePath, []byte(tt.input), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			if !mustFixFile(t, fixer, filePath) {
+				t.Fatal("Expected file to be fixed")
+			}
+			content, err := os.ReadFile(filePath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(content) != tt.expected {
+				t.Errorf("Expected:\n%q\n\nGot:\n%q", tt.expected, string(content))
+			}
+
+			if issue := checker.checkFile(filePath); issue != nil {
+				t.Errorf("Expected no issue after fixing, got %q", issue.Problem)
+			}
+			if issue := checker.verifyFile(filePath); issue != nil {
+				t.Errorf("Expected verify to pass after fixing, got %q", issue.Problem)
+			}
+			if mustFixFile(t, fixer, filePath) {
+				t.Error("Expected second run to make no changes")
+			}
+
+			processed, err := fixer.ProcessContent([]byte(tt.input), ".cs")
+			if err != nil {
+				t.Fatalf("ProcessContent() error = %v", err)
+			}
+			if string(processed) != tt.expected {
+				t.Errorf("ProcessContent() =\n%q\n\nwant:\n%q", string(processed), tt.expected)
+			}
+		})
+	}
+}
+
+func TestFixer_MultiLineCopyright(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	cfg := &config.Config{
+		Copyright: config.Copyright{
+			Holder:      "Acme Corp",
+			StartYear:   2014,
+			CurrentYear: 2025,
+			Format:      "Copyright {{.Holder}} {{.StartYear}}, {{.CurrentYear}}\n\n123 Main St, Springfield\n",
+		},
+		License: config.License{
+			Enabled:    true,
+			Identifier: "MPL-2.0",
+			Format:     "SPDX-License-Identifier: {{.Identifier}}",
+		},
+		Files: config.Files{
+			CommentStyles: map[string]string{"go": "//"},
+		},
+		Detection: config.Detection{
+			// Enough for a single-line header; the lines the format adds are scanned too
+			MaxScanLines: 3,
+		},
+	}
+
+	const header = "// Copyright Acme Corp 2014, 2025\n//\n// 123 Main St, Springfield\n// SPDX-License-Identifier: MPL-2.0\n\n"
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "missing header",
+			input:    "package main\n",
+			expected: header + "package main\n",
+		},
+		{
+			name:     "outdated years",
+			input:    "// Copyright Acme Corp 2014, 2020\n//\n// 123 Main St, Springfield\n// SPDX-License-Identifier: MPL-2.0\n\npackage main\n",
+			expected: header + "package main\n",
+		},
+		{
+			name:     "single-line header",
+			input:    "// Copyright Acme Corp 2014, 2020\n// SPDX-License-Identifier: MPL-2.0\n\npackage main\n",
+			expected: header + "package main\n",
+		},
+		{
+			name:     "outdated license",
+			input:    "// Copyright Acme Corp 2014, 2025\n//\n// 123 Main St, Springfield\n// SPDX-License-Identifier: MIT\n\npackage main\n",
+			expected: header + "package main\n",
+		},
+	}
+
+	fixer := NewFixer(cfg)
+	checker := NewChecker(cfg)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filePath := filepath.Join(tmpDir, "main.go")
+			if err := os.WriteFile(filePath, []byte(tt.input), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			if issue := checker.checkFile(filePath); issue == nil {
+				t.Error("Expected an issue before fixing")
+			}
+			if !mustFixFile(t, fixer, filePath) {
+				t.Fatal("Expected file to be fixed")
+			}
+			content, err := os.ReadFile(filePath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(content) != tt.expected {
+				t.Errorf("Expected:\n%q\n\nGot:\n%q", tt.expected, string(content))
+			}
+
+			if issue := checker.checkFile(filePath); issue != nil {
+				t.Errorf("Expected no issue after fixing, got %q", issue.Problem)
+			}
+			if issue := checker.verifyFile(filePath); issue != nil {
+				t.Errorf("Expected verify to pass after fixing, got %q", issue.Problem)
+			}
+			if mustFixFile(t, fixer, filePath) {
+				t.Error("Expected second run to make no changes")
+			}
+
+			processed, err := fixer.ProcessContent([]byte(tt.input), ".go")
+			if err != nil {
+				t.Fatalf("ProcessContent() error = %v", err)
+			}
+			if string(processed) != tt.expected {
+				t.Errorf("ProcessContent() =\n%q\n\nwant:\n%q", string(processed), tt.expected)
+			}
+		})
+	}
+}
+
+func TestFixer_CollapseYears(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	cfg := &config.Config{
+		Copyright: config.Copyright{
+			Holder:        "Acme Corp",
+			StartYear:     2025,
+			CurrentYear:   2025,
+			Format:        "Copyright {{.Holder}} {{.StartYear}}, {{.CurrentYear}}",
+			CollapseYears: true,
+		},
+		License: config.License{
+			Enabled:    true,
+			Identifier: "MPL-2.0",
+			Format:     "SPDX-License-Identifier: {{.Identifier}}",
+		},
+		Files: config.Files{
+			CommentStyles: map[string]string{"go": "//"},
+		},
+		Detection: config.Detection{
+			MaxScanLines: 10,
+		},
+	}
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string // empty when the file is left as it is
+	}{
+		{
+			name:     "missing header",
+			input:    "package main\n",
+			expected: "// Copyright Acme Corp 2025\n// SPDX-License-Identifier: MPL-2.0\n\npackage main\n",
+		},
+		{
+			name:  "collapsed",
+			input: "// Copyright Acme Corp 2025\n// SPDX-License-Identifier: MPL-2.0\n\npackage main\n",
+		},
+		{
+			name:  "expanded",
+			input: "// Copyright Acme Corp 2025, 2025\n// SPDX-License-Identifier: MPL-2.0\n\npackage main\n",
+		},
+		{
+			name:     "outdated collapsed",
+			input:    "// Copyright Acme Corp 2024\n// SPDX-License-Identifier: MPL-2.0\n\npackage main\n",
+			expected: "// Copyright Acme Corp 2025\n// SPDX-License-Identifier: MPL-2.0\n\npackage main\n",
+		},
+	}
+
+	fixer := NewFixer(cfg)
+	checker := NewChecker(cfg)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filePath := filepath.Join(tmpDir, "main.go")
+			if err := os.WriteFile(filePath, []byte(tt.input), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			if got := mustFixFile(t, fixer, filePath); got != (tt.expected != "") {
+				t.Fatalf("fixFile() modified = %v, want %v", got, tt.expected != "")
+			}
+			content, err := os.ReadFile(filePath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			expected := tt.expected
+			if expected == "" {
+				expected = tt.input
+			}
+			if string(content) != expected {
+				t.Errorf("Expected:\n%q\n\nGot:\n%q", expected, string(content))
+			}
+
+			if issue := checker.checkFile(filePath); issue != nil {
+				t.Errorf("Expected no issue, got %q", issue.Problem)
+			}
+		})
+	}
+}
+
+func TestFixer_UpdateYearOnly(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	cfg := &config.Config{
+		Copyright: config.Copyright{
+			Holder:         "IBM Corp.",
+			StartYear:      2014,
+			CurrentYear:    2025,
+			Format:         "Copyright {{.Holder}} {{.StartYear}}, {{.CurrentYear}}",
+			UpdateYearOnly: true,
+		},
+		License: config.License{
+			Enabled:    true,
+			Identifier: "MPL-2.0",
+			Format:     "SPDX-License-Identifier: {{.Identifier}}",
+		},
+		Files: config.Files{
+			CommentStyles: map[string]string{"go": "//"},
+		},
+		Detection: config.Detection{
+			MaxScanLines:    10,
+			ReplacePatterns: []string{`Copyright IBM Corp\. \d{4}, \d{4}\. All rights reserved\.`},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string // empty when the file is left as it is
+	}{
+		{
+			name:     "stale end year",
+			input:    "// Copyright IBM Corp. 2014, 2023\n// SPDX-License-Identifier: MPL-2.0\n\npackage main\n",
+			expected: "// Copyright IBM Corp. 2014, 2025\n// SPDX-License-Identifier: MPL-2.0\n\npackage main\n",
+		},
+		{
+			name:     "other start year and spacing kept",
+			input:    "//Copyright IBM Corp. 2019,2023\n// SPDX-License-Identifier: MPL-2.0\n\npackage main\n",
+			expected: "//Copyright IBM Corp. 2019,2025\n// SPDX-License-Identifier: MPL-2.0\n\npackage main\n",
+		},
+		{
+			name:     "trailing text kept",
+			input:    "// Copyright IBM Corp. 2014, 2023. All rights reserved.\n// SPDX-License-Identifier: MPL-2.0\n\npackage main\n",
+			expected: "// Copyright IBM Corp. 2014, 2025. All rights reserved.\n// SPDX-License-Identifier: MPL-2.0\n\npackage main\n",
+		},
+		{
+			name:     "trailing text not matching a replace pattern kept",
+			input:    "// Copyright IBM Corp. 2020, 2024 All rights reserved.\n// SPDX-License-Identifier: MPL-2.0\n\npackage main\n",
+			expected: "// Copyright IBM Corp. 2020, 2025 All rights reserved.\n// SPDX-License-Identifier: MPL-2.0\n\npackage main\n",
+		},
+		{
+			name:     "trailing text kept when the header is rebuilt",
+			input:    "// Copyright IBM Corp. 2020, 2024 All rights reserved.\n\npackage main\n",
+			expected: "// Copyright IBM Corp. 2020, 2025 All rights reserved.\n// SPDX-License-Identifier: MPL-2.0\n\npackage main\n",
+		},
+		{
+			name:     "single year becomes the start of a range",
+			input:    "// Copyright IBM Corp. 2016\n// SPDX-License-Identifier: MPL-2.0\n\npackage main\n",
+			expected: "// Copyright IBM Corp. 2016, 2025\n// SPDX-License-Identifier: MPL-2.0\n\npackage main\n",
+		},
+		{
+			name:  "current year",
+			input: "// Copyright IBM Corp. 2019, 2025\n// SPDX-License-Identifier: MPL-2.0\n\npackage main\n",
+		},
+		{
+			name:  "current single year",
+			input: "// Copyright IBM Corp. 2025\n// SPDX-License-Identifier: MPL-2.0\n\npackage main\n",
+		},
+		{
+			name:     "missing header",
+			input:    "package main\n",
+			expected: "// Copyright IBM Corp. 2014, 2025\n// SPDX-License-Identifier: MPL-2.0\n\npackage main\n",
+		},
+	}
+
+	fixer := NewFixer(cfg)
+	checker := NewChecker(cfg)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filePath := filepath.Join(tmpDir, "main.go")
+			if err := os.WriteFile(filePath, []byte(tt.input), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			if got := mustFixFile(t, fixer, filePath); got != (tt.expected != "") {
+				t.Fatalf("fixFile() modified = %v, want %v", got, tt.expected != "")
+			}
+			content, err := os.ReadFile(filePath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			expected := tt.expected
+			if expected == "" {
+				expected = tt.input
+			}
+			if string(content) != expected {
+				t.Errorf("Expected:\n%q\n\nGot:\n%q", expected, string(content))
+			}
+
+			if issue := checker.checkFile(filePath); issue != nil {
+				t.Errorf("Expected no issue after fixing, got %q", issue.Problem)
+			}
+			if mustFixFile(t, fixer, filePath) {
+				t.Error("Expected second run to make no changes")
+			}
+		})
+	}
+}
+
+func TestFixer_FormatsByExt(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	cfg := &config.Config{
+		Copyright: config.Copyright{
+			Holder:       "Acme Corp",
+			StartYear:    2014,
+			CurrentYear:  2025,
+			Format:       "Copyright {{.Holder}} {{.StartYear}}, {{.CurrentYear}}",
+			FormatsByExt: map[string]string{"sh": "Copyright (c) {{.CurrentYear}} {{.Holder}}. All rights reserved."},
+		},
+		License: config.License{
+			Enabled:      true,
+			Identifier:   "MPL-2.0",
+			Format:       "SPDX-License-Identifier: {{.Identifier}}",
+			FormatsByExt: map[string]string{"sh": "Licensed under the {{.Identifier}}"},
+		},
+		Files: config.Files{
+			CommentStyles: map[string]string{"go": "//", "sh": "#"},
+		},
+		Detection: config.Detection{
+			MaxScanLines: 10,
+		},
+	}
+
+	tests := []struct {
+		file     string
+		input    string
+		expected string
+	}{
+		{
+			file:     "main.go",
+			input:    "package main\n",
+			expected: "// Copyright Acme Corp 2014, 2025\n// SPDX-License-Identifier: MPL-2.0\n\npackage main\n",
+		},
+		{
+			file:     "build.sh",
+			input:    "echo hi\n",
+			expected: "# Copyright (c) 2025 Acme Corp. All rights reserved.\n# Licensed under the MPL-2.0\n\necho hi\n",
+		},
+		{
+			file:     "outdated.sh",
+			input:    "# Copyright (c) 2020 Acme Corp. All rights reserved.\n# Licensed under the MPL-2.0\n\necho hi\n",
+			expected: "# Copyright (c) 2025 Acme Corp. All rights reserved.\n# Licensed under the MPL-2.0\n\necho hi\n",
+		},
+	}
+
+	fixer := NewFixer(cfg)
+	checker := NewChecker(cfg)
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			filePath := filepath.Join(tmpDir, tt.file)
+			if err := os.WriteFile(filePath, []byte(tt.input), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			if !mustFixFile(t, fixer, filePath) {
+				t.Fatal("Expected file to be fixed")
+			}
+			content, err := os.ReadFile(filePath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(content) != tt.expected {
+				t.Errorf("Expected:\n%q\n\nGot:\n%q", tt.expected, string(content))
+			}
+
+			if issue := checker.checkFile(filePath); issue != nil {
+				t.Errorf("Expected no issue after fixing, got %q", issue.Problem)
+			}
+			if mustFixFile(t, fixer, filePath) {
+				t.Error("Expected second run to make no changes")
+			}
+		})
+	}
+}
+
+func TestFixer_ScanBoundary(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	header := "# Copyright IBM Corp. 2014, 2026\n# SPDX-License-Identifier: MPL-2.0\n"
+	tests := []struct {
+		name         string
+		maxScanLines int
+		input        string
+		expected     string
+	}{
+		{
+			name:         "license past the last scanned line",
+			maxScanLines: 4,
+			input:        "# a\n# b\n# c\n" + header + "\nimport os\n",
+			expected:     "# a\n# b\n# c\n" + header + "\nimport os\n",
+		},
+		{
+			name:         "header on the last scanned line",
+			maxScanLines: 4,
+			input:        "# a\n# b\n# c\n# Copyright IBM Corp. 2014, 2025\n# SPDX-License-Identifier: MPL-2.0\n\nimport os\n",
+			expected:     "# a\n# b\n# c\n" + header + "\nimport os\n",
+		},
+		{
+			name:         "header past the last scanned line",
+			maxScanLines: 4,
+			input:        "# a\n# b\n# c\n# d\n" + header + "\nimport os\n",
+			expected:     header + "\n# a\n# b\n# c\n# d\n" + header + "\nimport os\n",
+		},
+		{
+			name:     "no limit",
+			input:    strings.Repeat("# note\n", 30) + header + "\nimport os\n",
+			expected: strings.Repeat("# note\n", 30) + header + "\nimport os\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{
+				Copyright: config.Copyright{
+					Holder:      "IBM Corp.",
+					StartYear:   2014,
+					CurrentYear: 2026,
+					Format:      "Copyright {{.Holder}} {{.StartYear}}, {{.CurrentYear}}",
+				},
+				License: config.License{
+					Enabled:    true,
+					Identifier: "MPL-2.0",
+					Format:     "SPDX-License-Identifier: {{.Identifier}}",
+				},
+				Files:     config.Files{CommentStyles: map[string]string{"py": "#"}},
+				Detection: config.Detection{MaxScanLines: tt.maxScanLines},
+			}
+			filePath := filepath.Join(tmpDir, "boundary.py")
+			if err := os.WriteFile(filePath, []byte(tt.input), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			mustFixFile(t, NewFixer(cfg), filePath)
+			content, err := os.ReadFile(filePath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(content) != tt.expected {
+				t.Errorf("Expected:\n%q\n\nGot:\n%q", tt.expected, string(content))
+			}
+
+			if issue := NewChecker(cfg).checkFile(filePath); issue != nil {
+				t.Errorf("Expected no issue after fixing, got %q", issue.Problem)
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+// Copyright IBM Corp. 2014, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package copyright
+
+import (
+	"errors"
+	"os"
+	"strings"
+
+	"github.com/YakDriver/copyplop/internal/config"
+)
+
+// CanonicalHeader returns the header lines fix inserts for files with extension ext
+func CanonicalHeader(cfg *config.Config, ext string) ([]string, error) {
+	if ext != "" && !strings.HasPrefix(ext, ".") {
+		ext = "." + ext
+	}
+
+	copyrightHeader, err := cfg.GetCopyrightHeader(ext)
+	if err != nil {
+		return nil, err
+	}
+
+	licenseHeader, err := cfg.GetLicenseHeader(ext)
+	if err != nil {
+		return nil, err
+	}
+
+	noticeHeader, err := cfg.GetNoticeHeader(ext)
+	if err != nil {
+		return nil, err
+	}
+
+	bannerBefore, bannerAfter := cfg.GetBannerLines(ext)
+	return headerBlock(cfg, ext, bannerBefore, copyrightHeader, licenseHeader, noticeHeader, bannerAfter), nil
+}
+
+// ExtensionForFile returns the extension whose comment style applies to file, handling
+// compound extensions and, when the file exists, smart-extension content detection
+func ExtensionForFile(cfg *config.Config, file string) (string, error) {
+	content, err := os.ReadFile(file)
+	if err != nil && !os.IsNotExist(err) {
+		return "", err
+	}
+
+	ext, _, ok := resolveExtension(cfg, file, content)
+	if !ok {
+		return "", errors.New("file looks binary")
+	}
+	return ext, nil
+}
@@ -0,0 +1,84 @@
+// Copyright IBM Corp. 2014, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package copyright
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/YakDriver/copyplop/internal/config"
+)
+
+func TestCanonicalHeader_MatchesFix(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	cfg := &config.Config{
+		Copyright: config.Copyright{
+			Holder:       "IBM Corp.",
+			StartYear:    2014,
+			CurrentYear:  2025,
+			Format:       "Copyright {{.Holder}} {{.StartYear}}, {{.CurrentYear}}",
+			BannerBefore: "-----",
+		},
+		License: config.License{
+			Enabled:    true,
+			Identifier: "MPL-2.0",
+			Format:     "SPDX-License-Identifier: {{.Identifier}}",
+			Notice:     "See LICENSE",
+		},
+		Files: config.Files{
+			Extensions:      []string{".go", ".js", ".html.markdown"},
+			SmartExtensions: []string{".tmpl"},
+			CommentStyles:   map[string]string{"go": "//", "js": "/**", "html_markdown": "<!--", "tf": "#"},
+		},
+	}
+	fixer := NewFixer(cfg)
+
+	tests := []struct {
+		file    string
+		ext     string
+		content string
+	}{
+		{file: "main.go", ext: ".go", content: "package main\n"},
+		{file: "app.js", ext: "js", content: "const x = 1;\n"},
+		{file: "page.html.markdown", content: "Some text\n"},
+		{file: "main.tf.tmpl", content: "resource \"aws_instance\" \"example\" {}\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			filePath := filepath.Join(tmpDir, tt.file)
+			if err := os.WriteFile(filePath, []byte(tt.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			ext := tt.ext
+			if ext == "" {
+				var err error
+				ext, err = ExtensionForFile(cfg, filePath)
+				if err != nil {
+					t.Fatalf("ExtensionForFile() error = %v", err)
+				}
+			}
+
+			header, err := CanonicalHeader(cfg, ext)
+			if err != nil {
+				t.Fatalf("CanonicalHeader() error = %v", err)
+			}
+
+			mustFixFile(t, fixer, filePath)
+
+			content, err := os.ReadFile(filePath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			expected := strings.Join(header, "\n") + "\n\n" + tt.content
+			if string(content) != expected {
+				t.Errorf("fixFile inserted:\n%q\n\nCanonicalHeader gives:\n%q", string(content), expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,213 @@
+// Copyright IBM Corp. 2014, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package copyright
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/YakDriver/copyplop/internal/config"
+)
+
+// Jupyter notebooks are JSON, which has no comments, so their header goes at the top of the
+// first cell instead: as Python comments in a code cell, or in the Markdown comment style in
+// a markdown cell. A notebook starting with a raw cell (or no cell) gets a new markdown cell.
+
+// isNotebook reports whether file is a Jupyter notebook
+func isNotebook(file string) bool {
+	return strings.HasSuffix(file, ".ipynb")
+}
+
+// fixNotebook is applyHeader for Jupyter notebooks
+func (f *Fixer) fixNotebook(file, path string) (headerChange, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return headerChange{}, err
+	}
+
+	nb, change, err := fixNotebookHeader(f.config, content)
+	if err != nil {
+		return headerChange{}, fmt.Errorf("%s: %w", file, err)
+	}
+	if change.header == nil {
+		return headerChange{}, nil
+	}
+
+	data, err := marshalNotebook(nb)
+	if err != nil {
+		return headerChange{}, err
+	}
+	if f.Diff != nil {
+		change.diff = unifiedDiff(path, strings.Split(string(content), "\n"), strings.Split(string(data), "\n"))
+	}
+	if f.DryRun {
+		return change, nil
+	}
+	if err := writeFileAtomic(path, data); err != nil {
+		return headerChange{}, err
+	}
+	return change, nil
+}
+
+// checkNotebook is checkFile for Jupyter notebooks
+func (c *Checker) checkNotebook(file string, content []byte) *Issue {
+	_, change, err := fixNotebookHeader(c.config, content)
+	if err != nil {
+		return &Issue{File: file, Kind: KindError, Problem: "invalid notebook: " + err.Error()}
+	}
+	if change.header != nil {
+		return &Issue{File: file, Kind: KindMissing, Problem: "missing or incorrect copyright header"}
+	}
+	return nil
+}
+
+// fixNotebookHeader parses a notebook and puts the canonical header at the top of its first
+// cell, returning the change made, which holds no header when nothing had to change
+func fixNotebookHeader(cfg *config.Config, content []byte) (map[string]any, headerChange, error) {
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.UseNumber() // keep numbers such as execution counts exactly as written
+
+	var nb map[string]any
+	if err := decoder.Decode(&nb); err != nil {
+		return nil, headerChange{}, err
+	}
+
+	cells, _ := nb["cells"].([]any)
+	var cell map[string]any
+	if len(cells) > 0 {
+		cell, _ = cells[0].(map[string]any)
+	}
+
+	ext := ""
+	if cell != nil {
+		switch cell["cell_type"] {
+		case "code":
+			ext = ".py"
+		case "markdown":
+			ext = ".md"
+		}
+	}
+
+	if ext == "" {
+		// No cell, or a raw cell that would be passed through verbatim: add a markdown cell
+		header, err := CanonicalHeader(cfg, ".md")
+		if err != nil {
+			return nil, headerChange{}, err
+		}
+		newCell := map[string]any{
+			"cell_type": "markdown",
+			"metadata":  map[string]any{},
+			"source":    notebookSourceLines(header),
+		}
+		if _, ok := cell["id"]; ok {
+			newCell["id"] = "copyright-header"
+		}
+		nb["cells"] = append([]any{newCell}, cells...)
+		return nb, headerChange{header: header, added: true}, nil
+	}
+
+	source, isList, err := notebookSource(cell["source"])
+	if err != nil {
+		return nil, headerChange{}, err
+	}
+
+	lines := strings.Split(source, "\n")
+	if cfg.IsSkippedContent(lines) {
+		return nb, headerChange{}, nil
+	}
+
+	header, err := CanonicalHeader(cfg, ext)
+	if err != nil {
+		return nil, headerChange{}, err
+	}
+
+	headerEnd := notebookHeaderEnd(cfg, ext, header, lines)
+	rest := lines[headerEnd:]
+	for len(rest) > 0 && strings.TrimSpace(rest[0]) == "" {
+		rest = rest[1:]
+	}
+
+	newLines := slices.Clone(header)
+	if len(rest) > 0 {
+		newLines = append(newLines, "")
+		newLines = append(newLines, rest...)
+	}
+	if slices.Equal(newLines, lines) {
+		return nb, headerChange{}, nil
+	}
+
+	if isList {
+		cell["source"] = notebookSourceLines(newLines)
+	} else {
+		cell["source"] = strings.Join(newLines, "\n")
+	}
+	return nb, headerChange{header: header, added: headerEnd == 0}, nil
+}
+
+// notebookHeaderEnd returns the index of the first line after the header lines (ours, in any
+// version or syntax) at the top of a cell
+func notebookHeaderEnd(cfg *config.Config, ext string, header, lines []string) int {
+	for i, line := range lines {
+		isHeader := cfg.IsOwnCopyrightLine(line, ext) || isSPDXHeaderLine(cfg, ext, line) ||
+			cfg.IsWrongSyntaxHeaderLine(line, ext) || cfg.ShouldReplace(line) ||
+			slices.ContainsFunc(header, func(h string) bool { return isSameHeaderLine(line, h) })
+		if !isHeader {
+			return i
+		}
+	}
+	return len(lines)
+}
+
+// notebookSource returns a cell source, which nbformat stores either as one string or as a
+// list of lines, and whether it was a list
+func notebookSource(source any) (string, bool, error) {
+	switch s := source.(type) {
+	case nil:
+		return "", true, nil
+	case string:
+		return s, false, nil
+	case []any:
+		var sb strings.Builder
+		for _, line := range s {
+			str, ok := line.(string)
+			if !ok {
+				return "", false, fmt.Errorf("cell source contains a non-string line")
+			}
+			sb.WriteString(str)
+		}
+		return sb.String(), true, nil
+	default:
+		return "", false, fmt.Errorf("cell source is not a string or list")
+	}
+}
+
+// notebookSourceLines converts lines to nbformat's list form, where every line but the
+// last keeps its newline
+func notebookSourceLines(lines []string) []any {
+	source := make([]any, len(lines))
+	for i, line := range lines {
+		if i < len(lines)-1 {
+			line += "\n"
+		}
+		source[i] = line
+	}
+	return source
+}
+
+// marshalNotebook serializes a notebook the way Jupyter does (sorted keys, one-space indent,
+// no escaping of non-ASCII or HTML characters) so unchanged parts stay byte-identical
+func marshalNotebook(nb map[string]any) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", " ")
+	if err := encoder.Encode(nb); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,234 @@
+// Copyright IBM Corp. 2014, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package copyright
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/YakDriver/copyplop/internal/config"
+)
+
+// notebookJSON renders a one-cell notebook the way Jupyter writes it
+func notebookJSON(cell string) string {
+	return `{
+ "cells": [
+` + cell + `
+ ],
+ "metadata": {
+  "kernelspec": {
+   "display_name": "Python 3 <ipykernel>",
+   "language": "python",
+   "name": "python3"
+  },
+  "title": "Données"
+ },
+ "nbformat": 4,
+ "nbformat_minor": 5
+}
+`
+}
+
+func TestFixer_Notebook(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	cfg := &config.Config{
+		Copyright: config.Copyright{
+			Holder:      "IBM Corp.",
+			StartYear:   2014,
+			CurrentYear: 2025,
+			Format:      "Copyright {{.Holder}} {{.StartYear}}, {{.CurrentYear}}",
+		},
+		License: config.License{
+			Enabled:    true,
+			Identifier: "MPL-2.0",
+			Format:     "SPDX-License-Identifier: {{.Identifier}}",
+		},
+		Files: config.Files{
+			Extensions:    []string{".ipynb"},
+			CommentStyles: map[string]string{"py": "#", "md": "<!--"},
+		},
+		Detection: config.Detection{
+			MaxScanLines: 20,
+		},
+	}
+
+	correct := notebookJSON(`  {
+   "cell_type": "code",
+   "execution_count": 1,
+   "id": "a1b2c3",
+   "metadata": {},
+   "outputs": [],
+   "source": [
+    "# Copyright IBM Corp. 2014, 2025\n",
+    "# SPDX-License-Identifier: MPL-2.0\n",
+    "\n",
+    "x = 1"
+   ]
+  }`)
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name: "code cell",
+			input: notebookJSON(`  {
+   "cell_type": "code",
+   "execution_count": 1,
+   "id": "a1b2c3",
+   "metadata": {},
+   "outputs": [],
+   "source": [
+    "import pandas as pd\n",
+    "print(\"<done>\")"
+   ]
+  }`),
+			expected: notebookJSON(`  {
+   "cell_type": "code",
+   "execution_count": 1,
+   "id": "a1b2c3",
+   "metadata": {},
+   "outputs": [],
+   "source": [
+    "# Copyright IBM Corp. 2014, 2025\n",
+    "# SPDX-License-Identifier: MPL-2.0\n",
+    "\n",
+    "import pandas as pd\n",
+    "print(\"<done>\")"
+   ]
+  }`),
+		},
+		{
+			name: "outdated header in code cell",
+			input: notebookJSON(`  {
+   "cell_type": "code",
+   "execution_count": null,
+   "id": "a1b2c3",
+   "metadata": {},
+   "outputs": [],
+   "source": [
+    "# Copyright IBM Corp. 2014, 2020\n",
+    "# SPDX-License-Identifier: MIT\n",
+    "import pandas as pd"
+   ]
+  }`),
+			expected: notebookJSON(`  {
+   "cell_type": "code",
+   "execution_count": null,
+   "id": "a1b2c3",
+   "metadata": {},
+   "outputs": [],
+   "source": [
+    "# Copyright IBM Corp. 2014, 2025\n",
+    "# SPDX-License-Identifier: MPL-2.0\n",
+    "\n",
+    "import pandas as pd"
+   ]
+  }`),
+		},
+		{
+			name: "markdown cell with string source",
+			input: notebookJSON(`  {
+   "cell_type": "markdown",
+   "id": "a1b2c3",
+   "metadata": {},
+   "source": "# Analysis\n\nNotes."
+  }`),
+			expected: notebookJSON(`  {
+   "cell_type": "markdown",
+   "id": "a1b2c3",
+   "metadata": {},
+   "source": "<!-- Copyright IBM Corp. 2014, 2025 -->\n<!-- SPDX-License-Identifier: MPL-2.0 -->\n\n# Analysis\n\nNotes."
+  }`),
+		},
+		{
+			name: "raw first cell",
+			input: notebookJSON(`  {
+   "cell_type": "raw",
+   "id": "a1b2c3",
+   "metadata": {},
+   "source": [
+    "---\n",
+    "title: Analysis\n",
+    "---"
+   ]
+  }`),
+			expected: notebookJSON(`  {
+   "cell_type": "markdown",
+   "id": "copyright-header",
+   "metadata": {},
+   "source": [
+    "<!-- Copyright IBM Corp. 2014, 2025 -->\n",
+    "<!-- SPDX-License-Identifier: MPL-2.0 -->"
+   ]
+  },
+  {
+   "cell_type": "raw",
+   "id": "a1b2c3",
+   "metadata": {},
+   "source": [
+    "---\n",
+    "title: Analysis\n",
+    "---"
+   ]
+  }`),
+		},
+		{
+			// A notebook that already has the header is left byte-identical
+			name:     "correct header",
+			input:    correct,
+			expected: correct,
+		},
+	}
+
+	fixer := NewFixer(cfg)
+	checker := NewChecker(cfg)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filePath := filepath.Join(tmpDir, "analysis.ipynb")
+			if err := os.WriteFile(filePath, []byte(tt.input), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			if issue := checker.checkFile(filePath); (issue != nil) != (tt.input != tt.expected) {
+				t.Errorf("checkFile() = %v, want an issue only if the notebook needs fixing", issue)
+			}
+
+			if changed := mustFixFile(t, fixer, filePath); changed != (tt.input != tt.expected) {
+				t.Errorf("fixFile() changed = %v", changed)
+			}
+
+			content, err := os.ReadFile(filePath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(content) != tt.expected {
+				t.Errorf("Expected:\n%s\nGot:\n%s", tt.expected, string(content))
+			}
+
+			if mustFixFile(t, fixer, filePath) {
+				t.Error("Expected second run to make no changes")
+			}
+			if issue := checker.checkFile(filePath); issue != nil {
+				t.Errorf("Expected no issue after fixing, got %q", issue.Problem)
+			}
+		})
+	}
+
+	t.Run("invalid JSON", func(t *testing.T) {
+		filePath := filepath.Join(tmpDir, "broken.ipynb")
+		if err := os.WriteFile(filePath, []byte("{\"cells\": ["), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := fixer.fixFile(filePath); err == nil {
+			t.Error("Expected an error for invalid notebook JSON")
+		}
+		if issue := checker.checkFile(filePath); issue == nil {
+			t.Error("Expected an issue for invalid notebook JSON")
+		}
+	})
+}
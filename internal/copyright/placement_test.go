@@ -4,6 +4,8 @@
 package copyright
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/YakDriver/copyplop/internal/config"
@@ -134,6 +136,18 @@ func TestDetectionFunctions(t *testing.T) {
 			function: hasMarkdownHeading,
 			expected: false,
 		},
+		{
+			name:     "Shebang detected",
+			lines:    []string{"#!/usr/bin/env rust-script"},
+			function: hasShebang,
+			expected: true,
+		},
+		{
+			name:     "Rust inner attribute is not a shebang",
+			lines:    []string{"#![allow(dead_code)]"},
+			function: hasShebang,
+			expected: false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -145,3 +159,449 @@ func TestDetectionFunctions(t *testing.T) {
 		})
 	}
 }
+
+func TestFixer_LeadingLines(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	cfg := &config.Config{
+		Copyright: config.Copyright{
+			Holder:      "IBM Corp.",
+			StartYear:   2014,
+			CurrentYear: 2025,
+			Format:      "Copyright {{.Holder}} {{.StartYear}}, {{.CurrentYear}}",
+		},
+		License: config.License{
+			Enabled:    true,
+			Identifier: "MPL-2.0",
+			Format:     "SPDX-License-Identifier: {{.Identifier}}",
+		},
+		Files: config.Files{
+			CommentStyles: map[string]string{"php": "//", "scss": "//"},
+			PlacementExceptions: config.PlacementExceptions{
+				PHPOpenTag:   true,
+				LeadingLines: []string{`^@charset `},
+			},
+		},
+		Detection: config.Detection{
+			MaxScanLines: 20,
+			RequireAtTop: true,
+		},
+	}
+
+	tests := []struct {
+		name     string
+		filename string
+		input    string
+		expected string
+	}{
+		{
+			name:     "php without header",
+			filename: "index.php",
+			input:    "<?php\n\necho 'hello';\n",
+			expected: "<?php\n// Copyright IBM Corp. 2014, 2025\n// SPDX-License-Identifier: MPL-2.0\n\necho 'hello';\n",
+		},
+		{
+			name:     "php with outdated header",
+			filename: "index.php",
+			input:    "<?php\n// Copyright IBM Corp. 2014, 2020\n// SPDX-License-Identifier: MPL-2.0\n\necho 'hello';\n",
+			expected: "<?php\n// Copyright IBM Corp. 2014, 2025\n// SPDX-License-Identifier: MPL-2.0\n\necho 'hello';\n",
+		},
+		{
+			name:     "php with code after the tag",
+			filename: "inline.php",
+			input:    "<?php echo 'hello'; ?>\n",
+			expected: "// Copyright IBM Corp. 2014, 2025\n// SPDX-License-Identifier: MPL-2.0\n\n<?php echo 'hello'; ?>\n",
+		},
+		{
+			name:     "configured leading line",
+			filename: "style.scss",
+			input:    "@charset \"UTF-8\";\nbody {}\n",
+			expected: "@charset \"UTF-8\";\n// Copyright IBM Corp. 2014, 2025\n// SPDX-License-Identifier: MPL-2.0\n\nbody {}\n",
+		},
+	}
+
+	fixer := NewFixer(cfg)
+	checker := NewChecker(cfg)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filePath := filepath.Join(tmpDir, tt.filename)
+			if err := os.WriteFile(filePath, []byte(tt.input), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			mustFixFile(t, fixer, filePath)
+
+			content, err := os.ReadFile(filePath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(content) != tt.expected {
+				t.Errorf("Expected:\n%q\n\nGot:\n%q", tt.expected, string(content))
+			}
+
+			if mustFixFile(t, fixer, filePath) {
+				t.Error("Expected second run to make no changes")
+			}
+			if issue := checker.checkFile(filePath); issue != nil {
+				t.Errorf("Expected no issue after fixing, got %q", issue.Problem)
+			}
+		})
+	}
+}
+
+func TestFixer_RustInnerAttributes(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	cfg := &config.Config{
+		Copyright: config.Copyright{
+			Holder:      "IBM Corp.",
+			StartYear:   2014,
+			CurrentYear: 2025,
+			Format:      "Copyright {{.Holder}} {{.StartYear}}, {{.CurrentYear}}",
+		},
+		License: config.License{
+			Enabled:    true,
+			Identifier: "MPL-2.0",
+			Format:     "SPDX-License-Identifier: {{.Identifier}}",
+		},
+		Files: config.Files{
+			CommentStyles: map[string]string{"rs": "//"},
+		},
+		Detection: config.Detection{
+			MaxScanLines: 20,
+			RequireAtTop: true,
+		},
+	}
+
+	header := "// Copyright IBM Corp. 2014, 2025\n// SPDX-License-Identifier: MPL-2.0\n"
+	attributes := "#![crate_type = \"lib\"]\n#![cfg_attr(\n    feature = \"nightly\",\n    feature(test)\n)]\n"
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "inner attributes stay first",
+			input:    attributes + "\npub fn add() {}\n",
+			expected: attributes + header + "\npub fn add() {}\n",
+		},
+		{
+			name:     "outdated header below inner attributes",
+			input:    attributes + "// Copyright IBM Corp. 2014, 2020\n// SPDX-License-Identifier: MPL-2.0\n\npub fn add() {}\n",
+			expected: attributes + header + "\npub fn add() {}\n",
+		},
+		{
+			name:     "inner doc comments go below the header",
+			input:    "//! A crate.\n\npub fn add() {}\n",
+			expected: header + "\n//! A crate.\n\npub fn add() {}\n",
+		},
+	}
+
+	fixer := NewFixer(cfg)
+	checker := NewChecker(cfg)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filePath := filepath.Join(tmpDir, "lib.rs")
+			if err := os.WriteFile(filePath, []byte(tt.input), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			mustFixFile(t, fixer, filePath)
+
+			content, err := os.ReadFile(filePath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(content) != tt.expected {
+				t.Errorf("Expected:\n%q\n\nGot:\n%q", tt.expected, string(content))
+			}
+
+			if mustFixFile(t, fixer, filePath) {
+				t.Error("Expected second run to make no changes")
+			}
+			if issue := checker.checkFile(filePath); issue != nil {
+				t.Errorf("Expected no issue after fixing, got %q", issue.Problem)
+			}
+		})
+	}
+}
+
+func TestFixer_BlankAfterShebang(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	header := "# Copyright IBM Corp. 2014, 2025\n# SPDX-License-Identifier: MPL-2.0\n"
+
+	tests := []struct {
+		name     string
+		blank    bool
+		input    string
+		expected string
+	}{
+		{
+			name:     "no blank line configured",
+			input:    "#!/bin/bash\necho hi\n",
+			expected: "#!/bin/bash\n" + header + "\necho hi\n",
+		},
+		{
+			name:     "blank line configured",
+			blank:    true,
+			input:    "#!/bin/bash\necho hi\n",
+			expected: "#!/bin/bash\n\n" + header + "\necho hi\n",
+		},
+		{
+			name:     "blank line added above an existing header",
+			blank:    true,
+			input:    "#!/bin/bash\n" + header + "\necho hi\n",
+			expected: "#!/bin/bash\n\n" + header + "\necho hi\n",
+		},
+		{
+			name:     "existing blank line kept",
+			blank:    true,
+			input:    "#!/bin/bash\n\necho hi\n",
+			expected: "#!/bin/bash\n\n" + header + "\necho hi\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{
+				Copyright: config.Copyright{
+					Holder:      "IBM Corp.",
+					StartYear:   2014,
+					CurrentYear: 2025,
+					Format:      "Copyright {{.Holder}} {{.StartYear}}, {{.CurrentYear}}",
+				},
+				License: config.License{
+					Enabled:    true,
+					Identifier: "MPL-2.0",
+					Format:     "SPDX-License-Identifier: {{.Identifier}}",
+				},
+				Files: config.Files{
+					CommentStyles:     map[string]string{"sh": "#"},
+					BlankAfterShebang: tt.blank,
+				},
+				Detection: config.Detection{
+					MaxScanLines: 20,
+				},
+			}
+			fixer := NewFixer(cfg)
+			checker := NewChecker(cfg)
+
+			filePath := filepath.Join(tmpDir, "run.sh")
+			if err := os.WriteFile(filePath, []byte(tt.input), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			if issue := checker.checkFile(filePath); issue == nil {
+				t.Error("Expected an issue before fixing")
+			}
+
+			if !mustFixFile(t, fixer, filePath) {
+				t.Error("Expected fix to report a change")
+			}
+
+			content, err := os.ReadFile(filePath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(content) != tt.expected {
+				t.Errorf("Expected:\n%q\n\nGot:\n%q", tt.expected, string(content))
+			}
+
+			if mustFixFile(t, fixer, filePath) {
+				t.Error("Expected second run to make no changes")
+			}
+			if issue := checker.checkFile(filePath); issue != nil {
+				t.Errorf("Expected no issue after fixing, got %q", issue.Problem)
+			}
+
+			out, err := fixer.ProcessContent([]byte(tt.input), ".sh")
+			if err != nil {
+				t.Fatalf("ProcessContent error: %v", err)
+			}
+			if string(out) != tt.expected {
+				t.Errorf("ProcessContent expected:\n%q\n\nGot:\n%q", tt.expected, string(out))
+			}
+		})
+	}
+}
+
+func TestFixer_HeaderInBanner(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	cfg := &config.Config{
+		Copyright: config.Copyright{
+			Holder:      "IBM Corp.",
+			StartYear:   2014,
+			CurrentYear: 2025,
+			Format:      "Copyright {{.Holder}} {{.StartYear}}, {{.CurrentYear}}",
+		},
+		License: config.License{
+			Enabled:    true,
+			Identifier: "MPL-2.0",
+			Format:     "SPDX-License-Identifier: {{.Identifier}}",
+		},
+		Files: config.Files{
+			CommentStyles: map[string]string{"go": "//", "js": "/**"},
+		},
+		Detection: config.Detection{
+			MaxScanLines:    20,
+			ReplacePatterns: []string{"Copyright Old Corp"},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		file     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "outdated years",
+			file:     "main.go",
+			input:    "// ==========\n// Project Foo\n//\n// Some description\n// Copyright IBM Corp. 2014, 2020\n// SPDX-License-Identifier: Apache-2.0\n// ==========\n\npackage main\n",
+			expected: "// ==========\n// Project Foo\n//\n// Some description\n// Copyright IBM Corp. 2014, 2025\n// SPDX-License-Identifier: MPL-2.0\n// ==========\n\npackage main\n",
+		},
+		{
+			name:     "license added below the copyright",
+			file:     "main.go",
+			input:    "// ==========\n// Project Foo\n//\n// Copyright IBM Corp. 2014, 2020\n//\n// Some description\n// ==========\n\npackage main\n",
+			expected: "// ==========\n// Project Foo\n//\n// Copyright IBM Corp. 2014, 2025\n// SPDX-License-Identifier: MPL-2.0\n//\n// Some description\n// ==========\n\npackage main\n",
+		},
+		{
+			name:     "replaced copyright",
+			file:     "main.go",
+			input:    "// ==========\n// Project Foo\n// Copyright Old Corp\n// SPDX-License-Identifier: MPL-2.0\n// ==========\n\npackage main\n",
+			expected: "// ==========\n// Project Foo\n// Copyright IBM Corp. 2014, 2025\n// SPDX-License-Identifier: MPL-2.0\n// ==========\n\npackage main\n",
+		},
+		{
+			name:     "block comment banner",
+			file:     "app.js",
+			input:    "/**\n * Project Foo\n *\n * Copyright IBM Corp. 2014, 2020\n * SPDX-License-Identifier: MPL-2.0\n */\n\nconst a = 1;\n",
+			expected: "/**\n * Project Foo\n *\n * Copyright IBM Corp. 2014, 2025\n * SPDX-License-Identifier: MPL-2.0\n */\n\nconst a = 1;\n",
+		},
+	}
+
+	fixer := NewFixer(cfg)
+	checker := NewChecker(cfg)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filePath := filepath.Join(tmpDir, tt.file)
+			if err := os.WriteFile(filePath, []byte(tt.input), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			if !mustFixFile(t, fixer, filePath) {
+				t.Error("Expected fix to report a change")
+			}
+
+			content, err := os.ReadFile(filePath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(content) != tt.expected {
+				t.Errorf("Expected:\n%s\n\nGot:\n%s", tt.expected, string(content))
+			}
+
+			if mustFixFile(t, fixer, filePath) {
+				t.Error("Expected second run to make no changes")
+			}
+			if issue := checker.checkFile(filePath); issue != nil {
+				t.Errorf("Expected no issue after fixing, got %q", issue.Problem)
+			}
+		})
+	}
+}
+
+func TestFixer_IDLFiles(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	// No comment styles configured: IDL files fall back to "//"
+	cfg := &config.Config{
+		Copyright: config.Copyright{
+			Holder:      "IBM Corp.",
+			StartYear:   2014,
+			CurrentYear: 2025,
+			Format:      "Copyright {{.Holder}} {{.StartYear}}, {{.CurrentYear}}",
+		},
+		License: config.License{
+			Enabled:    true,
+			Identifier: "MPL-2.0",
+			Format:     "SPDX-License-Identifier: {{.Identifier}}",
+		},
+		Detection: config.Detection{
+			MaxScanLines: 20,
+			RequireAtTop: true,
+		},
+	}
+
+	header := "// Copyright IBM Corp. 2014, 2025\n// SPDX-License-Identifier: MPL-2.0\n"
+	proto := "syntax = \"proto3\";\n\npackage api.v1;\n\nmessage Ping {}\n"
+
+	tests := []struct {
+		name     string
+		file     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "proto",
+			file:     "api.proto",
+			input:    proto,
+			expected: header + "\n" + proto,
+		},
+		{
+			name:     "proto with outdated header",
+			file:     "api.proto",
+			input:    "// Copyright IBM Corp. 2014, 2020\n// SPDX-License-Identifier: MPL-2.0\n\n" + proto,
+			expected: header + "\n" + proto,
+		},
+		{
+			name:     "proto with leading comment",
+			file:     "api.proto",
+			input:    "// Ping service definitions.\n" + proto,
+			expected: header + "\n// Ping service definitions.\n" + proto,
+		},
+		{
+			name:     "thrift",
+			file:     "api.thrift",
+			input:    "namespace go api\n\nstruct Ping {}\n",
+			expected: header + "\nnamespace go api\n\nstruct Ping {}\n",
+		},
+		{
+			name:     "flatbuffers",
+			file:     "schema.fbs",
+			input:    "namespace api;\n\ntable Ping {}\n",
+			expected: header + "\nnamespace api;\n\ntable Ping {}\n",
+		},
+	}
+
+	fixer := NewFixer(cfg)
+	checker := NewChecker(cfg)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filePath := filepath.Join(tmpDir, tt.file)
+			if err := os.WriteFile(filePath, []byte(tt.input), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			mustFixFile(t, fixer, filePath)
+
+			content, err := os.ReadFile(filePath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(content) != tt.expected {
+				t.Errorf("Expected:\n%q\n\nGot:\n%q", tt.expected, string(content))
+			}
+
+			if mustFixFile(t, fixer, filePath) {
+				t.Error("Expected second run to make no changes")
+			}
+			if issue := checker.checkFile(filePath); issue != nil {
+				t.Errorf("Expected no issue after fixing, got %q", issue.Problem)
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+// Copyright IBM Corp. 2014, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package copyright
+
+import (
+	"context"
+	"os"
+	"runtime"
+	"strconv"
+	"sync"
+	"sync/atomic"
+
+	"github.com/schollz/progressbar/v3"
+	"golang.org/x/term"
+)
+
+// newProgressBar returns the progress bar for processing files, or a silent one when quiet
+// or not running interactively
+func newProgressBar(files int, description string, quiet bool) *progressbar.ProgressBar {
+	if quiet || !interactive() {
+		return progressbar.DefaultSilent(int64(files), description)
+	}
+	return progressbar.Default(int64(files), description)
+}
+
+// interactive reports whether progress can be drawn: the CI environment variable is not
+// set to true and stderr, which the progress bar is drawn on, is a terminal rather than a
+// pipe or log file
+func interactive() bool {
+	if ci, _ := strconv.ParseBool(os.Getenv("CI")); ci {
+		return false
+	}
+	return term.IsTerminal(int(os.Stderr.Fd()))
+}
+
+// forEachFile calls process for each of files on up to jobs workers (0 = runtime.NumCPU()),
+// advancing bar as files complete. process gets the index of its file, so results can be
+// stored per file without locking and assembled in order afterwards. No more files are handed
+// out once process returns false or ctx is cancelled, in which case ctx's error is returned;
+// files already in progress still complete.
+func forEachFile(ctx context.Context, files []string, jobs int, bar *progressbar.ProgressBar, process func(i int, file string) bool) error {
+	if jobs <= 0 {
+		jobs = runtime.NumCPU()
+	}
+	jobs = min(jobs, len(files))
+
+	var (
+		stopped atomic.Bool
+		barMu   sync.Mutex
+		wg      sync.WaitGroup
+	)
+	indexes := make(chan int)
+	for range jobs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				// A file handed out while another worker was stopping is left alone
+				if stopped.Load() {
+					continue
+				}
+				if !process(i, files[i]) {
+					stopped.Store(true)
+				}
+				barMu.Lock()
+				_ = bar.Add(1)
+				barMu.Unlock()
+			}
+		}()
+	}
+
+	// Only this goroutine consults ctx, handing out files in order until it is cancelled
+	var err error
+	for i := range files {
+		if err = ctx.Err(); err != nil || stopped.Load() {
+			break
+		}
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	return err
+}
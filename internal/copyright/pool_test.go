@@ -0,0 +1,15 @@
+// Copyright IBM Corp. 2014, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package copyright
+
+import "testing"
+
+func TestInteractive_CI(t *testing.T) {
+	for _, value := range []string{"true", "1", "TRUE"} {
+		t.Setenv("CI", value)
+		if interactive() {
+			t.Errorf("interactive() with CI=%s = true, want false", value)
+		}
+	}
+}
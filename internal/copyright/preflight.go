@@ -0,0 +1,108 @@
+// Copyright IBM Corp. 2014, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package copyright
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Preflight actions for a sampled file
+const (
+	PreflightOK    = "ok"
+	PreflightFix   = "fix"
+	PreflightError = "error"
+)
+
+// PreflightReport summarizes what a run would do for each configured extension
+type PreflightReport struct {
+	Extensions []ExtensionPreflight
+}
+
+// ExtensionPreflight is the preflight result for one configured extension. Files is the
+// number of files the extension selects; zero usually means a misconfigured extension or path filter.
+type ExtensionPreflight struct {
+	Extension string
+	Files     int
+	Samples   []PreflightSample
+}
+
+// PreflightSample is the simulated result of fixing one sampled file
+type PreflightSample struct {
+	File          string
+	Action        string
+	Problem       string // check problem for PreflightFix, error text for PreflightError
+	NonIdempotent bool   // a second fix would change the file again
+}
+
+// Preflight samples up to samples files of each configured extension under path and
+// reports what fix would do to them. Fixes run on temporary copies; files under path
+// are never modified.
+func (f *Fixer) Preflight(path string, samples int) (*PreflightReport, error) {
+	files, err := getFilesToProcess(path, f.config, f.Modified)
+	if err != nil {
+		return nil, err
+	}
+
+	tmpDir, err := os.MkdirTemp("", "copyplop-preflight-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	checker := NewChecker(f.config)
+	report := &PreflightReport{}
+	extensions := append(append([]string{}, f.config.Files.Extensions...), f.config.Files.SmartExtensions...)
+	for _, ext := range extensions {
+		result := ExtensionPreflight{Extension: ext}
+		for _, file := range files {
+			if !strings.HasSuffix(file, ext) {
+				continue
+			}
+			result.Files++
+			if len(result.Samples) < samples {
+				copyPath := filepath.Join(tmpDir, fmt.Sprintf("%d-%d%s", len(report.Extensions), len(result.Samples), ext))
+				result.Samples = append(result.Samples, f.preflightFile(checker, file, copyPath))
+			}
+		}
+		report.Extensions = append(report.Extensions, result)
+	}
+
+	return report, nil
+}
+
+// preflightFile fixes a copy of file at copyPath twice: the first run shows what fix would
+// do, the second that the result is stable
+func (f *Fixer) preflightFile(checker *Checker, file, copyPath string) PreflightSample {
+	sample := PreflightSample{File: file, Action: PreflightOK}
+
+	content, err := os.ReadFile(file)
+	if err == nil {
+		err = os.WriteFile(copyPath, content, 0600)
+	}
+	if err != nil {
+		return PreflightSample{File: file, Action: PreflightError, Problem: err.Error()}
+	}
+
+	changed, err := f.fixFileAt(file, copyPath)
+	if err != nil {
+		return PreflightSample{File: file, Action: PreflightError, Problem: err.Error()}
+	}
+	if changed {
+		sample.Action = PreflightFix
+		if issue := checker.checkFile(file); issue != nil {
+			sample.Problem = issue.Problem
+		}
+	}
+
+	changedAgain, err := f.fixFileAt(file, copyPath)
+	if err != nil {
+		return PreflightSample{File: file, Action: PreflightError, Problem: err.Error()}
+	}
+	sample.NonIdempotent = changedAgain
+
+	return sample
+}
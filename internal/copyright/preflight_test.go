@@ -0,0 +1,89 @@
+// Copyright IBM Corp. 2014, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package copyright
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/YakDriver/copyplop/internal/config"
+)
+
+func TestFixer_Preflight(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	cfg := &config.Config{
+		Copyright: config.Copyright{
+			Holder:      "IBM Corp.",
+			StartYear:   2014,
+			CurrentYear: 2025,
+			Format:      "Copyright {{.Holder}} {{.StartYear}}, {{.CurrentYear}}",
+		},
+		License: config.License{
+			Enabled:    true,
+			Identifier: "MPL-2.0",
+			Format:     "SPDX-License-Identifier: {{.Identifier}}",
+		},
+		Files: config.Files{
+			Extensions:    []string{".go", ".rst"},
+			CommentStyles: map[string]string{"go": "//"},
+		},
+		Detection: config.Detection{
+			MaxScanLines: 20,
+		},
+	}
+
+	missing := "package main\n"
+	correct := "// Copyright IBM Corp. 2014, 2025\n// SPDX-License-Identifier: MPL-2.0\n\npackage main\n"
+	files := map[string]string{
+		"a.go": missing,
+		"b.go": correct,
+		"c.go": missing,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	report, err := NewFixer(cfg).Preflight(tmpDir, 2)
+	if err != nil {
+		t.Fatalf("Preflight() error = %v", err)
+	}
+
+	if len(report.Extensions) != 2 {
+		t.Fatalf("Preflight() reported %d extensions, want 2", len(report.Extensions))
+	}
+
+	goReport := report.Extensions[0]
+	if goReport.Extension != ".go" || goReport.Files != 3 || len(goReport.Samples) != 2 {
+		t.Fatalf(".go report = %+v, want 3 files and 2 samples", goReport)
+	}
+	expected := []PreflightSample{
+		{File: filepath.Join(tmpDir, "a.go"), Action: PreflightFix, Problem: "missing or incorrect copyright header"},
+		{File: filepath.Join(tmpDir, "b.go"), Action: PreflightOK},
+	}
+	for i, sample := range goReport.Samples {
+		if sample != expected[i] {
+			t.Errorf("sample %d = %+v, want %+v", i, sample, expected[i])
+		}
+	}
+
+	// A configured extension with no files is reported so it can be flagged
+	if rst := report.Extensions[1]; rst.Extension != ".rst" || rst.Files != 0 || len(rst.Samples) != 0 {
+		t.Errorf(".rst report = %+v, want no files", rst)
+	}
+
+	// Sampled files are left untouched
+	for name, content := range files {
+		got, err := os.ReadFile(filepath.Join(tmpDir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != content {
+			t.Errorf("%s was modified by preflight:\n%q", name, got)
+		}
+	}
+}
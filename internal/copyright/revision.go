@@ -0,0 +1,109 @@
+// Copyright IBM Corp. 2014, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package copyright
+
+import (
+	"context"
+	"io/fs"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// revisionFile is a file as committed at a git revision
+type revisionFile struct {
+	path string // relative to the CWD, like paths from the working tree
+	rel  string // relative to the directory git runs in
+	mode fs.FileMode
+}
+
+// CheckRevision is Check for the files under path as committed at git revision rev. Files
+// are listed and read from git rather than the working tree, which is never touched.
+func (c *Checker) CheckRevision(ctx context.Context, path, rev string) ([]Issue, error) {
+	dir, files, err := getRevisionFiles(path, rev)
+	if err != nil {
+		return nil, err
+	}
+
+	var filesToProcess []revisionFile
+	for _, file := range files {
+		if c.config.ShouldProcess(file.path) && c.config.ShouldProcessMode(file.mode) {
+			filesToProcess = append(filesToProcess, file)
+		}
+	}
+	slices.SortFunc(filesToProcess, func(a, b revisionFile) int { return strings.Compare(a.path, b.path) })
+
+	if len(filesToProcess) == 0 {
+		c.summarize(nil, nil)
+		return nil, nil
+	}
+
+	bar := newProgressBar(len(filesToProcess), "Checking files", c.Quiet)
+	var issues []Issue
+	var scanned []string
+	defer func() { c.summarize(scanned, issues) }()
+
+	for _, file := range filesToProcess {
+		if err := ctx.Err(); err != nil {
+			return issues, err
+		}
+		scanned = append(scanned, file.path)
+		var issue *Issue
+		content, err := runGit(dir, "cat-file", "blob", rev+":./"+file.rel)
+		if err != nil {
+			issue = &Issue{File: file.path, Kind: KindError, Problem: "could not read file at " + rev}
+		} else {
+			issue = c.checkContent(file.path, []byte(content))
+		}
+		if c.reports(issue) {
+			issues = append(issues, *issue)
+			if c.FailFast {
+				break
+			}
+		}
+		_ = bar.Add(1)
+	}
+
+	return issues, nil
+}
+
+// getRevisionFiles lists the regular files under path at git revision rev, returning them
+// along with the directory git must run in to read them
+func getRevisionFiles(path, rev string) (string, []revisionFile, error) {
+	dir, target := gitDirAndTarget(path)
+
+	if _, err := exec.LookPath(gitCommand); err != nil {
+		return "", nil, ErrGitNotFound
+	}
+
+	output, err := runGit(dir, "ls-tree", "-r", "-z", rev, "--", target)
+	if err != nil {
+		return "", nil, gitError(path, err)
+	}
+
+	// Entries are "<mode> <type> <object>\t<path>", NUL-terminated, with paths relative to dir
+	var files []revisionFile
+	for entry := range strings.SplitSeq(output, "\x00") {
+		info, rel, ok := strings.Cut(entry, "\t")
+		if !ok {
+			continue
+		}
+		fields := strings.Fields(info)
+		if len(fields) != 3 || fields[1] != "blob" {
+			continue // submodules
+		}
+		mode, err := strconv.ParseUint(fields[0], 8, 32)
+		if err != nil || mode&0o170000 != 0o100000 {
+			continue // symlinks
+		}
+		files = append(files, revisionFile{
+			path: filepath.Join(dir, filepath.FromSlash(rel)),
+			rel:  rel,
+			mode: fs.FileMode(mode & 0o777),
+		})
+	}
+	return dir, files, nil
+}
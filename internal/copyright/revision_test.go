@@ -0,0 +1,119 @@
+// Copyright IBM Corp. 2014, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package copyright
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/YakDriver/copyplop/internal/config"
+)
+
+func TestChecker_CheckRevision(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	repoDir := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		cmd.Dir = repoDir
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, output)
+		}
+	}
+	write := func(name, content string) {
+		t.Helper()
+		filePath := filepath.Join(repoDir, name)
+		if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	header := "// Copyright IBM Corp. 2014, 2025\n// SPDX-License-Identifier: MPL-2.0\n\n"
+
+	// The first commit has one file without a header, the second fixes it
+	write("good.go", header+"package main\n")
+	write("sub/bad.go", "package sub\n")
+	write("notes.txt", "no header\n")
+	git("init", "-q")
+	git("add", ".")
+	git("commit", "-q", "-m", "initial")
+
+	write("sub/bad.go", header+"package sub\n")
+	git("commit", "-q", "-am", "add header")
+
+	// Uncommitted changes in the working tree are ignored
+	write("good.go", "package main\n")
+
+	cfg := &config.Config{
+		Copyright: config.Copyright{
+			Holder:      "IBM Corp.",
+			StartYear:   2014,
+			CurrentYear: 2025,
+			Format:      "Copyright {{.Holder}} {{.StartYear}}, {{.CurrentYear}}",
+		},
+		License: config.License{
+			Enabled:    true,
+			Identifier: "MPL-2.0",
+			Format:     "SPDX-License-Identifier: {{.Identifier}}",
+		},
+		Files: config.Files{
+			Extensions:    []string{".go"},
+			CommentStyles: map[string]string{"go": "//"},
+		},
+		Detection: config.Detection{
+			MaxScanLines: 20,
+		},
+	}
+	checker := NewChecker(cfg)
+
+	tests := []struct {
+		name     string
+		path     string
+		rev      string
+		expected []string
+	}{
+		{name: "older commit", path: repoDir, rev: "HEAD~1", expected: []string{filepath.Join(repoDir, "sub", "bad.go")}},
+		{name: "latest commit", path: repoDir, rev: "HEAD"},
+		{name: "subdirectory", path: filepath.Join(repoDir, "sub"), rev: "HEAD~1", expected: []string{filepath.Join(repoDir, "sub", "bad.go")}},
+		{name: "single file", path: filepath.Join(repoDir, "good.go"), rev: "HEAD~1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues, err := checker.CheckRevision(context.Background(), tt.path, tt.rev)
+			if err != nil {
+				t.Fatalf("CheckRevision() error = %v", err)
+			}
+			var files []string
+			for _, issue := range issues {
+				files = append(files, issue.File)
+			}
+			if !slices.Equal(files, tt.expected) {
+				t.Errorf("CheckRevision() issues in %v, want %v", files, tt.expected)
+			}
+		})
+	}
+
+	content, err := os.ReadFile(filepath.Join(repoDir, "good.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != "package main\n" {
+		t.Errorf("CheckRevision() modified the working tree: %q", content)
+	}
+
+	if _, err := checker.CheckRevision(context.Background(), repoDir, "no-such-rev"); err == nil {
+		t.Error("CheckRevision() expected error for an unknown revision")
+	}
+}
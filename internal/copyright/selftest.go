@@ -0,0 +1,129 @@
+// Copyright IBM Corp. 2014, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package copyright
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// SelfTestViolation is a property that fix broke for one corpus sample
+type SelfTestViolation struct {
+	Extension string
+	Sample    string
+	Problem   string
+}
+
+// selfTestBody is the file content below the header in every sample; fix must keep it as is
+const selfTestBody = "first line of code\n\nsecond line of code\n"
+
+// selfTestSample is a corpus entry: a name and how to build its content from the canonical
+// header, an outdated header in the same style, and the body
+type selfTestSample struct {
+	name    string
+	content func(header, outdated, body string) string
+	body    string
+}
+
+// selfTestCorpus holds the layouts fix has to handle, including ones fuzzing found fix to
+// mishandle in the past
+var selfTestCorpus = []selfTestSample{
+	{name: "no header", content: func(_, _, body string) string { return body }},
+	{name: "leading blank lines", content: func(_, _, body string) string { return "\n\n" + body }},
+	{name: "canonical header", content: func(header, _, body string) string { return header + "\n\n" + body }},
+	{name: "header without blank line", content: func(header, _, body string) string { return header + "\n" + body }},
+	{name: "header after blank lines", content: func(header, _, body string) string { return "\n\n\n" + header + "\n\n" + body }},
+	{name: "outdated header", content: func(_, outdated, body string) string { return outdated + "\n\n" + body }},
+	{name: "duplicated header", content: func(header, _, body string) string { return header + "\n" + header + "\n\n" + body }},
+	{name: "no trailing newline", content: func(_, _, body string) string { return strings.TrimSuffix(body, "\n") }},
+	{name: "blank lines between code", content: func(_, _, body string) string { return body }, body: "0\n\n0"},
+}
+
+// SelfTest fixes every corpus sample for each configured extension using the fixer's config
+// and reports samples for which fix is not idempotent, leaves a header check still
+// complains about, or changes the content below the header. Violations point at config
+// settings that would make fix churn files. Samples are fixed in a temporary directory.
+func (f *Fixer) SelfTest() ([]SelfTestViolation, error) {
+	tmpDir, err := os.MkdirTemp("", "copyplop-self-test-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	// An outdated header in the same style: older years and another license
+	outdatedCfg := *f.config
+	outdatedCfg.Copyright.CurrentYear = f.config.Copyright.Year() - 5
+	outdatedCfg.License.Identifier = "Apache-2.0"
+
+	checker := NewChecker(f.config)
+	var violations []SelfTestViolation
+	for _, ext := range f.config.Files.Extensions {
+		if isNotebook(ext) {
+			continue // notebooks are JSON, not text with a comment header
+		}
+
+		header, err := CanonicalHeader(f.config, ext)
+		if err != nil {
+			violations = append(violations, SelfTestViolation{Extension: ext, Problem: "config error: " + err.Error()})
+			continue
+		}
+		outdated, err := CanonicalHeader(&outdatedCfg, ext)
+		if err != nil {
+			violations = append(violations, SelfTestViolation{Extension: ext, Problem: "config error: " + err.Error()})
+			continue
+		}
+
+		for i, sample := range selfTestCorpus {
+			body := sample.body
+			if body == "" {
+				body = selfTestBody
+			}
+			content := sample.content(strings.Join(header, "\n"), strings.Join(outdated, "\n"), body)
+			path := filepath.Join(tmpDir, fmt.Sprintf("%d%s", i, ext))
+			if problem := f.selfTestSample(checker, ext, path, content, body); problem != "" {
+				violations = append(violations, SelfTestViolation{Extension: ext, Sample: sample.name, Problem: problem})
+			}
+		}
+	}
+	return violations, nil
+}
+
+// selfTestSample fixes content written to path as a file with extension ext and returns the
+// first property the result violates, or "" if it has none
+func (f *Fixer) selfTestSample(checker *Checker, ext, path, content, body string) string {
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		return err.Error()
+	}
+
+	file := "self-test" + ext
+	if _, err := f.fixFileAt(file, path); err != nil {
+		return "fix failed: " + err.Error()
+	}
+	fixed, err := os.ReadFile(path)
+	if err != nil {
+		return err.Error()
+	}
+
+	// Body preserved: everything below the header is kept, up to trailing newlines
+	if !strings.HasSuffix(strings.TrimRight(string(fixed), "\n"), strings.TrimRight(body, "\n")) {
+		return fmt.Sprintf("content below the header changed: %q", fixed)
+	}
+
+	// Canonical header present: check agrees with the fixed result
+	if issue := checker.checkContent(file, fixed); issue != nil {
+		return "check still reports after fixing: " + issue.Problem
+	}
+
+	// Idempotence: fixing again changes nothing
+	changed, err := f.fixFileAt(file, path)
+	if err != nil {
+		return "second fix failed: " + err.Error()
+	}
+	if changed {
+		return "not idempotent, a second fix changes the file again"
+	}
+	return ""
+}
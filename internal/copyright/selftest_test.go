@@ -0,0 +1,62 @@
+// Copyright IBM Corp. 2014, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package copyright
+
+import (
+	"testing"
+
+	"github.com/YakDriver/copyplop/internal/config"
+)
+
+func TestFixer_SelfTest(t *testing.T) {
+	newConfig := func() *config.Config {
+		return &config.Config{
+			Copyright: config.Copyright{
+				Holder:      "IBM Corp.",
+				StartYear:   2014,
+				CurrentYear: 2025,
+				Format:      "Copyright {{.Holder}} {{.StartYear}}, {{.CurrentYear}}",
+			},
+			License: config.License{
+				Enabled:    true,
+				Identifier: "MPL-2.0",
+				Format:     "SPDX-License-Identifier: {{.Identifier}}",
+			},
+			Files: config.Files{
+				Extensions:    []string{".go", ".py", ".md"},
+				CommentStyles: map[string]string{"go": "//", "py": "#", "md": "<!--"},
+			},
+			Detection: config.Detection{
+				MaxScanLines: 20,
+			},
+		}
+	}
+
+	violations, err := NewFixer(newConfig()).SelfTest()
+	if err != nil {
+		t.Fatalf("SelfTest() error = %v", err)
+	}
+	for _, v := range violations {
+		t.Errorf("unexpected violation for %s (%s): %s", v.Extension, v.Sample, v.Problem)
+	}
+
+	// A timestamp in the format renders differently on every run, so fix never settles
+	broken := newConfig()
+	broken.Copyright.Format = `Copyright {{.Holder}} {{.Now.Format "15:04:05.000000000"}}`
+	violations, err = NewFixer(broken).SelfTest()
+	if err != nil {
+		t.Fatalf("SelfTest() error = %v", err)
+	}
+	flagged := map[string]bool{}
+	for _, v := range violations {
+		if v.Sample == "canonical header" {
+			flagged[v.Extension] = true
+		}
+	}
+	for _, ext := range broken.Files.Extensions {
+		if !flagged[ext] {
+			t.Errorf("SelfTest() did not flag the canonical header sample for %s; violations = %+v", ext, violations)
+		}
+	}
+}
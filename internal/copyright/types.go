@@ -3,12 +3,70 @@
 
 package copyright
 
+import (
+	"fmt"
+	"time"
+)
+
+// Issue kinds classify problems so that issues can be triaged one category at a time
+const (
+	KindMissing        = "missing"         // no copyright header
+	KindIncorrect      = "incorrect"       // a header that is outdated, misplaced or malformed
+	KindLicenseMissing = "license-missing" // copyright present, license line missing
+	KindThirdParty     = "third-party"     // only a third-party copyright header
+	KindError          = "error"           // the file could not be checked
+)
+
 type Issue struct {
-	File    string
-	Problem string
+	File    string `json:"file"`
+	Kind    string `json:"kind,omitempty"`
+	Problem string `json:"problem"`
+	Diff    string `json:"diff,omitempty"`
+	// Suggestion is the header the file should have, with Checker.Suggest
+	Suggestion string `json:"suggestion,omitempty"`
 }
 
 type FixResult struct {
-	Fixed int
-	Added int
+	Fixed   int
+	Added   int
+	Changed []string // files that were modified, in processing order
+
+	// Unverified holds the issues check still reports for fixed files, with Fixer.Verify
+	Unverified []Issue
+
+	// Applied records the header written to each changed file, in processing order
+	Applied []AppliedHeader
+
+	// Errors holds the files that could not be read or written, in processing order
+	Errors []FileError
+
+	// Unstaged holds the changed files that Fixer.Staged did not stage again because they
+	// also had unstaged changes
+	Unstaged []string
+}
+
+// FileError is why a file could not be fixed
+type FileError struct {
+	File string
+	Err  error
+}
+
+func (e FileError) Error() string {
+	return fmt.Sprintf("%s: %v", e.File, e.Err)
+}
+
+func (e FileError) Unwrap() error {
+	return e.Err
+}
+
+// AppliedHeader is the header block fix wrote to a file and when
+type AppliedHeader struct {
+	File   string    `json:"file"`
+	Header string    `json:"header"`
+	Time   time.Time `json:"time"`
+}
+
+type DedupeResult struct {
+	File    string
+	Removed int
 }
@@ -0,0 +1,89 @@
+// Copyright IBM Corp. 2014, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package copyright
+
+import (
+	"context"
+	"os"
+	"slices"
+)
+
+// Verify reports files whose header block does not exactly equal the canonical
+// rendered header. It is stricter than Check, which only looks for the header text.
+// It stops between files when ctx is cancelled, returning the issues found so far
+// along with the context's error.
+func (c *Checker) Verify(ctx context.Context, path string) ([]Issue, error) {
+	filesToProcess, err := getFilesToProcess(path, c.config, c.Modified)
+	if err != nil {
+		return nil, err
+	}
+	return c.verifyFiles(ctx, filesToProcess)
+}
+
+// VerifyFiles verifies the given files, skipping any that the config does not select
+// for processing
+func (c *Checker) VerifyFiles(ctx context.Context, files []string) ([]Issue, error) {
+	return c.verifyFiles(ctx, filterFiles(files, c.config))
+}
+
+func (c *Checker) verifyFiles(ctx context.Context, filesToProcess []string) ([]Issue, error) {
+	if len(filesToProcess) == 0 {
+		return nil, nil
+	}
+
+	bar := newProgressBar(len(filesToProcess), "Verifying files", c.Quiet)
+	found := make([]*Issue, len(filesToProcess))
+	err := forEachFile(ctx, filesToProcess, c.Jobs, bar, func(i int, file string) bool {
+		found[i] = c.verifyFile(file)
+		return true
+	})
+
+	// Issues are reported in file order whichever worker found them
+	var issues []Issue
+	for _, issue := range found {
+		if issue != nil {
+			issues = append(issues, *issue)
+		}
+	}
+	return issues, err
+}
+
+func (c *Checker) verifyFile(file string) *Issue {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return &Issue{File: file, Kind: KindError, Problem: "could not read file"}
+	}
+
+	if isNotebook(file) {
+		return c.checkNotebook(file, content)
+	}
+
+	ext, isSmartExt, ok := resolveExtension(c.config, file, content)
+	if !ok {
+		return nil
+	}
+
+	setup, issue := c.setupHeader(file, content, ext, isSmartExt)
+	if setup == nil {
+		return issue
+	}
+
+	lines, startLine := setup.lines, setup.startLine
+	expected := setup.block()
+	actual := lines[startLine:min(startLine+len(expected), len(lines))]
+	if slices.Equal(actual, expected) {
+		return nil
+	}
+
+	// The diff turns the file's header into the canonical one, with the lines above it as
+	// context; the rest of the file follows, so the header lines end with a newline
+	before := append(slices.Clone(lines[:startLine+len(actual)]), "")
+	after := slices.Concat(lines[:startLine], expected, []string{""})
+	return &Issue{
+		File:    file,
+		Kind:    KindIncorrect,
+		Problem: "header differs from canonical header",
+		Diff:    unifiedDiff(file, before, after),
+	}
+}
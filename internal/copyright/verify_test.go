@@ -0,0 +1,191 @@
+// Copyright IBM Corp. 2014, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package copyright
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/YakDriver/copyplop/internal/config"
+)
+
+func TestChecker_verifyFile(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	cfg := &config.Config{
+		Copyright: config.Copyright{
+			Holder:      "IBM Corp.",
+			StartYear:   2014,
+			CurrentYear: 2025,
+			Format:      "Copyright {{.Holder}} {{.StartYear}}, {{.CurrentYear}}",
+		},
+		License: config.License{
+			Enabled:    true,
+			Identifier: "MPL-2.0",
+			Format:     "SPDX-License-Identifier: {{.Identifier}}",
+		},
+		Files: config.Files{
+			CommentStyles: map[string]string{"go": "//", "js": "/**", "sh": "#"},
+		},
+		Detection: config.Detection{
+			MaxScanLines: 20,
+		},
+	}
+
+	checker := NewChecker(cfg)
+
+	tests := []struct {
+		name         string
+		filename     string
+		content      string
+		expectVerify bool   // expect verify to flag the file
+		expectCheck  bool   // expect check to flag the file
+		expectedHunk string // the diff after its file names
+	}{
+		{
+			name:     "canonical header",
+			filename: "good.go",
+			content: `// Copyright IBM Corp. 2014, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package main`,
+		},
+		{
+			name:     "extra trailing comment",
+			filename: "tampered.go",
+			content: `// Copyright IBM Corp. 2014, 2025 // edited by hand
+// SPDX-License-Identifier: MPL-2.0
+
+package main`,
+			expectVerify: true,
+			expectedHunk: `@@ -1,2 +1,2 @@
+-// Copyright IBM Corp. 2014, 2025 // edited by hand
++// Copyright IBM Corp. 2014, 2025
+ // SPDX-License-Identifier: MPL-2.0
+`,
+		},
+		{
+			name:     "header below a shebang",
+			filename: "tampered.sh",
+			content: `#!/bin/bash
+# Copyright IBM Corp. 2014, 2024
+# SPDX-License-Identifier: MPL-2.0
+
+echo hello`,
+			expectVerify: true,
+			expectCheck:  true,
+			expectedHunk: `@@ -1,3 +1,3 @@
+ #!/bin/bash
+-# Copyright IBM Corp. 2014, 2024
++# Copyright IBM Corp. 2014, 2025
+ # SPDX-License-Identifier: MPL-2.0
+`,
+		},
+		{
+			name:     "canonical block comment",
+			filename: "good.js",
+			content: `/**
+ * Copyright IBM Corp. 2014, 2025
+ * SPDX-License-Identifier: MPL-2.0
+ */
+
+function hello() {}`,
+		},
+		{
+			name:         "missing header",
+			filename:     "missing.go",
+			content:      "package main",
+			expectVerify: true,
+			expectCheck:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filePath := filepath.Join(tmpDir, tt.filename)
+			if err := os.WriteFile(filePath, []byte(tt.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			issue := checker.verifyFile(filePath)
+			if (issue != nil) != tt.expectVerify {
+				t.Errorf("verifyFile() issue = %v, want %v", issue != nil, tt.expectVerify)
+			}
+			if issue != nil && tt.expectedHunk != "" {
+				// The diff reads like git diff, from the file's header to the canonical one
+				want := fmt.Sprintf("--- %s\n+++ %s\n%s", filepath.ToSlash(filepath.Join("a", filePath)), filepath.ToSlash(filepath.Join("b", filePath)), tt.expectedHunk)
+				if issue.Diff != want {
+					t.Errorf("verifyFile() diff:\n%s\nwant:\n%s", issue.Diff, want)
+				}
+			}
+
+			if checkIssue := checker.checkFile(filePath); (checkIssue != nil) != tt.expectCheck {
+				t.Errorf("checkFile() issue = %v, want %v", checkIssue != nil, tt.expectCheck)
+			}
+		})
+	}
+}
+
+func TestChecker_VerifyFiles(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	cfg := &config.Config{
+		Copyright: config.Copyright{
+			Holder:      "IBM Corp.",
+			StartYear:   2014,
+			CurrentYear: 2025,
+			Format:      "Copyright {{.Holder}} {{.StartYear}}, {{.CurrentYear}}",
+		},
+		Files: config.Files{
+			Extensions:    []string{".go"},
+			CommentStyles: map[string]string{"go": "//"},
+		},
+		Detection: config.Detection{
+			MaxScanLines: 20,
+		},
+	}
+
+	var files, want []string
+	for i := range 20 {
+		file := filepath.Join(tmpDir, fmt.Sprintf("f%02d.go", i))
+		content := "// Copyright IBM Corp. 2014, 2025\n\npackage main\n"
+		if i%3 == 0 {
+			content = "// Copyright IBM Corp. 2014, 2025 // edited\n\npackage main\n"
+			want = append(want, file)
+		}
+		if err := os.WriteFile(file, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		files = append(files, file)
+	}
+	files = append(files, filepath.Join(tmpDir, "notes.txt"))
+
+	checker := NewChecker(cfg)
+	checker.Jobs = 4
+	checker.Quiet = true
+	issues, err := checker.VerifyFiles(context.Background(), files)
+	if err != nil {
+		t.Fatalf("VerifyFiles() error = %v", err)
+	}
+
+	// Issues come in file order whichever worker found them; unselected files are skipped
+	var got []string
+	for _, issue := range issues {
+		got = append(got, issue.File)
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("VerifyFiles() files = %v, want %v", got, want)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := checker.VerifyFiles(ctx, files); !errors.Is(err, context.Canceled) {
+		t.Errorf("VerifyFiles() error = %v, want context.Canceled", err)
+	}
+}
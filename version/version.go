@@ -5,13 +5,61 @@ package version
 
 import (
 	_ "embed"
+	"runtime/debug"
 	"strings"
 )
 
 //go:embed VERSION
 var versionFile string
 
+// Commit and Date are injected at build time, e.g.
+// go build -ldflags "-X github.com/YakDriver/copyplop/version.Commit=abc123 -X github.com/YakDriver/copyplop/version.Date=2026-01-02T15:04:05Z"
+var (
+	Commit string
+	Date   string
+)
+
+// Info describes the build of copyplop
+type Info struct {
+	Version string `json:"version"`
+	Commit  string `json:"commit"`
+	Date    string `json:"date"`
+}
+
 // Version returns the current version of copyplop
 func Version() string {
 	return strings.TrimSpace(versionFile)
 }
+
+// Get returns version, commit and build date. Commit and date fall back to the VCS
+// information embedded by the Go toolchain when they were not injected via ldflags.
+func Get() Info {
+	info := Info{
+		Version: Version(),
+		Commit:  Commit,
+		Date:    Date,
+	}
+
+	if buildInfo, ok := debug.ReadBuildInfo(); ok {
+		for _, setting := range buildInfo.Settings {
+			switch setting.Key {
+			case "vcs.revision":
+				if info.Commit == "" {
+					info.Commit = setting.Value
+				}
+			case "vcs.time":
+				if info.Date == "" {
+					info.Date = setting.Value
+				}
+			}
+		}
+	}
+
+	if info.Commit == "" {
+		info.Commit = "unknown"
+	}
+	if info.Date == "" {
+		info.Date = "unknown"
+	}
+	return info
+}